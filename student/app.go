@@ -0,0 +1,96 @@
+package main
+
+import (
+	"stu/config"
+	"stu/events"
+	"stu/repository"
+	"stu/services"
+	"stu/webhooks"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// App holds the configuration, database and services shared by every
+// command.
+type App struct {
+	Config            config.Config
+	DB                *gorm.DB
+	Repo              repository.Repository
+	Cache             *repository.CachingRepository // nil unless CACHE_SIZE is set
+	OutboxRepo        repository.OutboxRepository
+	Bus               *events.Bus
+	SchoolService     services.SchoolService
+	ClassService      services.ClassService
+	StudentService    services.StudentService
+	StatsService      services.StatsService
+	RankingService    services.RankingService
+	WebhookService    services.WebhookService
+	AttendanceService services.AttendanceService
+	TeacherService    services.TeacherService
+	AcademicService   services.AcademicService
+	PromotionService  services.PromotionService
+	GuardianService   services.GuardianService
+	FeeService        services.FeeService
+	ExamService       services.ExamService
+	AppealService     services.AppealService
+	WaitlistService   services.WaitlistService
+	EventStream       services.EventStreamService
+	ChangeService     services.ChangeService
+	Webhooks          *webhooks.Dispatcher
+}
+
+func NewApp() (*App, error) {
+	cfg := config.Load()
+	db, err := gorm.Open(postgres.Open(cfg.DatabaseDSN), &gorm.Config{})
+	if err != nil {
+		return nil, err
+	}
+
+	repo := repository.NewRepository(db)
+	webhookRepo := repository.NewWebhookRepository(db)
+
+	// The outbox relay started by serve publishes to the bus, which queues
+	// webhook deliveries for every committed change.
+	bus := events.NewBus()
+	dispatcher := webhooks.NewDispatcher(webhookRepo, webhooks.Options{})
+	bus.Subscribe(dispatcher)
+
+	var cache *repository.CachingRepository
+	if cfg.CacheSize > 0 {
+		cache = repository.NewCachingRepository(repo, repository.CacheOptions{Size: cfg.CacheSize, TTL: cfg.CacheTTL})
+		bus.Subscribe(cache)
+		repo = cache
+	}
+
+	outboxRepo := repository.NewOutboxRepository(db)
+	academicRepo := repository.NewAcademicRepository(db)
+	examRepo := repository.NewExamRepository(db)
+
+	return &App{
+		Config:            cfg,
+		DB:                db,
+		Repo:              repo,
+		Cache:             cache,
+		OutboxRepo:        outboxRepo,
+		Bus:               bus,
+		SchoolService:     services.NewService(repo),
+		ClassService:      services.NewService(repo),
+		StudentService:    services.NewService(repo),
+		StatsService:      services.NewService(repo),
+		RankingService:    services.NewRankingService(repo, academicRepo, examRepo),
+		WebhookService:    services.NewWebhookService(webhookRepo),
+		AttendanceService: services.NewAttendanceService(repository.NewAttendanceRepository(db), repo),
+		TeacherService:    services.NewTeacherService(repository.NewTeacherRepository(db), repo),
+		AcademicService:   services.NewAcademicService(academicRepo, repo),
+		PromotionService:  services.NewPromotionService(repository.NewPromotionRepository(db), academicRepo, repo),
+		GuardianService:   services.NewGuardianService(repository.NewGuardianRepository(db), repo),
+		FeeService:        services.NewFeeService(repository.NewFeeRepository(db), academicRepo, repo),
+		ExamService:       services.NewExamService(examRepo, academicRepo, repo),
+		AppealService:     services.NewAppealService(repository.NewAppealRepository(db), examRepo, repository.NewGuardianRepository(db), repo, cfg.AppealSLA),
+		WaitlistService:   services.NewWaitlistService(repository.NewWaitlistRepository(db), repo),
+		EventStream:       services.NewEventStreamService(bus, outboxRepo),
+		ChangeService:     services.NewChangeService(repo, repository.NewChangeRepository(db), outboxRepo),
+		Webhooks:          dispatcher,
+	}, nil
+}
@@ -0,0 +1,93 @@
+// Package assessment combines a student's exam marks into weighted subject
+// scores and a final score out of 100.
+package assessment
+
+import (
+	"math"
+	"sort"
+)
+
+// Component is one exam as it counts towards a student's score. Only
+// graded exams, those with at least one result entered, count; a student
+// without a result in a graded exam scores zero in it.
+type Component struct {
+	ExamID   uint   `json:"exam_id"`
+	Subject  string `json:"-"`
+	Name     string `json:"name"`
+	MaxMarks int    `json:"max_marks"`
+	Weight   int    `json:"weight"`
+	Graded   bool   `json:"graded"`
+	Marks    *int   `json:"marks"`
+}
+
+// SubjectScore is the weighted score of a subject out of 100, scaled by
+// the weights of the graded exams so far.
+type SubjectScore struct {
+	Subject    string      `json:"subject"`
+	Score      float64     `json:"score"`
+	Graded     bool        `json:"graded"`
+	Components []Component `json:"components"`
+}
+
+// Breakdown is a student's final score, the mean of their graded subject
+// scores, with the scores it is made of.
+type Breakdown struct {
+	Final    float64        `json:"final"`
+	Graded   bool           `json:"graded"`
+	Subjects []SubjectScore `json:"subjects"`
+}
+
+// Compute scores the components by subject, subjects in name order.
+func Compute(components []Component) Breakdown {
+	bySubject := map[string][]Component{}
+	for _, component := range components {
+		bySubject[component.Subject] = append(bySubject[component.Subject], component)
+	}
+	subjects := make([]string, 0, len(bySubject))
+	for subject := range bySubject {
+		subjects = append(subjects, subject)
+	}
+	sort.Strings(subjects)
+
+	var breakdown Breakdown
+	var sum float64
+	var graded int
+	for _, subject := range subjects {
+		score := scoreSubject(subject, bySubject[subject])
+		breakdown.Subjects = append(breakdown.Subjects, score)
+		if score.Graded {
+			sum += score.Score
+			graded++
+		}
+	}
+	if graded > 0 {
+		breakdown.Final = sum / float64(graded)
+		breakdown.Graded = true
+	}
+	return breakdown
+}
+
+func scoreSubject(subject string, components []Component) SubjectScore {
+	score := SubjectScore{Subject: subject, Components: components}
+	var weighted float64
+	var weights int
+	for _, component := range components {
+		if !component.Graded || component.MaxMarks <= 0 || component.Weight <= 0 {
+			continue
+		}
+		weights += component.Weight
+		if component.Marks != nil {
+			weighted += float64(*component.Marks) / float64(component.MaxMarks) * float64(component.Weight)
+		}
+	}
+	if weights > 0 {
+		score.Score = weighted / float64(weights) * 100
+		score.Graded = true
+	}
+	return score
+}
+
+// Marks rounds the final score to the whole marks stored on a student.
+func (b Breakdown) Marks() int {
+	return int(math.Round(b.Final))
+}
@@ -0,0 +1,49 @@
+package assessment_test
+
+import (
+	"stu/assessment"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func marks(m int) *int { return &m }
+
+func TestCompute_WeighsExams(t *testing.T) {
+	breakdown := assessment.Compute([]assessment.Component{
+		{Subject: "Maths", Name: "Midterm", MaxMarks: 50, Weight: 30, Graded: true, Marks: marks(40)},
+		{Subject: "Maths", Name: "Final", MaxMarks: 100, Weight: 50, Graded: true, Marks: marks(70)},
+		{Subject: "Maths", Name: "Coursework", MaxMarks: 20, Weight: 20, Graded: true, Marks: marks(20)},
+	})
+
+	// 0.8*30 + 0.7*50 + 1*20 = 79
+	assert.True(t, breakdown.Graded)
+	assert.InDelta(t, 79, breakdown.Final, 1e-9)
+	assert.Equal(t, 79, breakdown.Marks())
+	assert.Equal(t, 1, len(breakdown.Subjects))
+}
+
+func TestCompute_ScalesToGradedExams(t *testing.T) {
+	breakdown := assessment.Compute([]assessment.Component{
+		{Subject: "Maths", MaxMarks: 50, Weight: 30, Graded: true, Marks: marks(40)},
+		{Subject: "Maths", MaxMarks: 100, Weight: 50, Graded: false},
+		{Subject: "Science", MaxMarks: 100, Weight: 40, Graded: true},
+		{Subject: "Science", MaxMarks: 100, Weight: 60, Graded: true, Marks: marks(50)},
+		{Subject: "Art", MaxMarks: 10, Weight: 100},
+	})
+
+	assert.Equal(t, []string{"Art", "Maths", "Science"}, []string{
+		breakdown.Subjects[0].Subject, breakdown.Subjects[1].Subject, breakdown.Subjects[2].Subject,
+	})
+	assert.False(t, breakdown.Subjects[0].Graded)
+	assert.InDelta(t, 80, breakdown.Subjects[1].Score, 1e-9)
+	// The missing Science result counts as zero: 0.5*60 = 30.
+	assert.InDelta(t, 30, breakdown.Subjects[2].Score, 1e-9)
+	assert.InDelta(t, 55, breakdown.Final, 1e-9)
+}
+
+func TestCompute_NothingGraded(t *testing.T) {
+	breakdown := assessment.Compute([]assessment.Component{{Subject: "Maths", MaxMarks: 100, Weight: 50}})
+	assert.False(t, breakdown.Graded)
+	assert.Equal(t, 0, breakdown.Marks())
+}
@@ -0,0 +1,213 @@
+package bulk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"stu/models"
+)
+
+// Format is the file encoding used for bulk import and export.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+)
+
+// ParseFormat validates a format name given on the command line.
+func ParseFormat(name string) (Format, error) {
+	switch Format(name) {
+	case FormatCSV, FormatJSON:
+		return Format(name), nil
+	}
+	return "", fmt.Errorf("unsupported format %q (want csv or json)", name)
+}
+
+// codec converts one entity type to and from CSV records. The last added
+// columns of the header were added after the first export format; files
+// exported without them still import, with the columns left empty.
+type codec[T any] struct {
+	header  []string
+	added   int
+	toRow   func(T) []string
+	fromRow func([]string) (T, error)
+}
+
+var schoolCodec = codec[models.School]{
+	header: []string{"id", "name", "class_id"},
+	toRow: func(s models.School) []string {
+		return []string{formatUint(s.ID), s.Name, formatUint(s.ClassID)}
+	},
+	fromRow: func(row []string) (models.School, error) {
+		var s models.School
+		var err error
+		if s.ID, err = parseUint(row[0]); err != nil {
+			return s, err
+		}
+		s.Name = row[1]
+		s.ClassID, err = parseUint(row[2])
+		return s, err
+	},
+}
+
+var classCodec = codec[models.Class]{
+	header: []string{"id", "class_id", "class_name", "student_id", "school_id"},
+	added:  1,
+	toRow: func(c models.Class) []string {
+		return []string{formatUint(c.ID), formatUint(c.ClassID), c.ClassName, formatUint(c.StudentID), formatUint(c.SchoolID)}
+	},
+	fromRow: func(row []string) (models.Class, error) {
+		var c models.Class
+		var err error
+		if c.ID, err = parseUint(row[0]); err != nil {
+			return c, err
+		}
+		if c.ClassID, err = parseUint(row[1]); err != nil {
+			return c, err
+		}
+		c.ClassName = row[2]
+		if c.StudentID, err = parseUint(row[3]); err != nil {
+			return c, err
+		}
+		c.SchoolID, err = parseUint(row[4])
+		return c, err
+	},
+}
+
+var studentCodec = codec[models.Student]{
+	header: []string{"id", "student_id", "name", "marks", "street", "city", "state", "class_id"},
+	added:  1,
+	toRow: func(s models.Student) []string {
+		return []string{
+			formatUint(s.ID), strconv.Itoa(s.StudentID), s.Name, strconv.Itoa(s.Marks),
+			s.Address.Street, s.Address.City, s.Address.State, formatUint(s.ClassID),
+		}
+	},
+	fromRow: func(row []string) (models.Student, error) {
+		var s models.Student
+		var err error
+		if s.ID, err = parseUint(row[0]); err != nil {
+			return s, err
+		}
+		if s.StudentID, err = parseInt(row[1]); err != nil {
+			return s, err
+		}
+		s.Name = row[2]
+		if s.Marks, err = parseInt(row[3]); err != nil {
+			return s, err
+		}
+		s.Address = models.Address{Street: row[4], City: row[5], State: row[6]}
+		s.ClassID, err = parseUint(row[7])
+		return s, err
+	},
+}
+
+func ExportSchools(w io.Writer, format Format, schools []models.School) error {
+	return export(w, format, schools, schoolCodec)
+}
+
+func ImportSchools(r io.Reader, format Format) ([]models.School, error) {
+	return load(r, format, schoolCodec)
+}
+
+func ExportClasses(w io.Writer, format Format, classes []models.Class) error {
+	return export(w, format, classes, classCodec)
+}
+
+func ImportClasses(r io.Reader, format Format) ([]models.Class, error) {
+	return load(r, format, classCodec)
+}
+
+func ExportStudents(w io.Writer, format Format, students []models.Student) error {
+	return export(w, format, students, studentCodec)
+}
+
+func ImportStudents(r io.Reader, format Format) ([]models.Student, error) {
+	return load(r, format, studentCodec)
+}
+
+func export[T any](w io.Writer, format Format, items []T, c codec[T]) error {
+	if format == FormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(c.header); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if err := writer.Write(c.toRow(item)); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+func load[T any](r io.Reader, format Format, c codec[T]) ([]T, error) {
+	if format == FormatJSON {
+		var items []T
+		if err := json.NewDecoder(r).Decode(&items); err != nil {
+			return nil, err
+		}
+		return items, nil
+	}
+	reader := csv.NewReader(r)
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+	if !c.accepts(records[0]) {
+		return nil, fmt.Errorf("unexpected CSV header %v, want %v", records[0], c.header)
+	}
+	items := make([]T, 0, len(records)-1)
+	for line, record := range records[1:] {
+		record = append(record, make([]string, len(c.header)-len(record))...)
+		item, err := c.fromRow(record)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+2, err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// accepts reports whether header is the codec's header, with or without
+// its added columns.
+func (c codec[T]) accepts(header []string) bool {
+	if len(header) != len(c.header) && len(header) != len(c.header)-c.added {
+		return false
+	}
+	for i, column := range header {
+		if c.header[i] != column {
+			return false
+		}
+	}
+	return true
+}
+
+func formatUint(v uint) string {
+	return strconv.FormatUint(uint64(v), 10)
+}
+
+func parseUint(s string) (uint, error) {
+	if s == "" {
+		return 0, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	return uint(v), err
+}
+
+func parseInt(s string) (int, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.Atoi(s)
+}
@@ -0,0 +1,65 @@
+package bulk_test
+
+import (
+	"bytes"
+	"strings"
+	"stu/bulk"
+	"stu/models"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStudents_RoundTrip(t *testing.T) {
+	students := []models.Student{
+		{StudentID: 1001, Name: "Jahnavi K", Marks: 91, Address: models.Address{Street: "12 MG Road", City: "Vizag", State: "AP"}},
+		{StudentID: 1002, Name: "Rahul, Jr.", Marks: 47, ClassID: 3},
+	}
+	students[0].ID = 7
+
+	for _, format := range []bulk.Format{bulk.FormatCSV, bulk.FormatJSON} {
+		var buf bytes.Buffer
+		assert.Nil(t, bulk.ExportStudents(&buf, format, students))
+
+		imported, err := bulk.ImportStudents(&buf, format)
+		assert.Nil(t, err)
+		assert.Len(t, imported, 2)
+		for i := range students {
+			assert.Equal(t, students[i].ID, imported[i].ID)
+			assert.Equal(t, students[i].Name, imported[i].Name)
+			assert.Equal(t, students[i].Marks, imported[i].Marks)
+			assert.Equal(t, students[i].Address, imported[i].Address)
+			assert.Equal(t, students[i].ClassID, imported[i].ClassID)
+		}
+	}
+}
+
+func TestImportSchools_RejectsBadHeader(t *testing.T) {
+	_, err := bulk.ImportSchools(strings.NewReader("id,title,class_id\n1,A,2\n"), bulk.FormatCSV)
+	assert.NotNil(t, err)
+}
+
+func TestImportClasses_ReportsLine(t *testing.T) {
+	input := "id,class_id,class_name,student_id\n1,3,Grade 3-A,4\n2,x,Grade 3-B,5\n"
+	_, err := bulk.ImportClasses(strings.NewReader(input), bulk.FormatCSV)
+	assert.ErrorContains(t, err, "line 3")
+}
+
+func TestImportStudents_AcceptsExportsWithoutClassID(t *testing.T) {
+	input := "id,student_id,name,marks,street,city,state\n7,1001,Jahnavi K,91,12 MG Road,Vizag,AP\n"
+
+	imported, err := bulk.ImportStudents(strings.NewReader(input), bulk.FormatCSV)
+
+	assert.Nil(t, err)
+	assert.Len(t, imported, 1)
+	assert.Equal(t, "Jahnavi K", imported[0].Name)
+	assert.Equal(t, uint(0), imported[0].ClassID)
+}
+
+func TestImportStudents_RejectsShortRows(t *testing.T) {
+	input := "id,student_id,name,marks,street,city,state,class_id\n7,1001,Jahnavi K,91,12 MG Road,Vizag,AP\n"
+
+	_, err := bulk.ImportStudents(strings.NewReader(input), bulk.FormatCSV)
+
+	assert.NotNil(t, err)
+}
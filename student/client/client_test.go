@@ -0,0 +1,376 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sort"
+	"stu/client"
+	"stu/controllers"
+	"stu/models"
+	"stu/routes"
+	"stu/services"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryService is an in-memory implementation of the school, class and
+// student services used to back a real router in tests.
+type memoryService struct {
+	mu       sync.Mutex
+	nextID   uint
+	schools  map[uint]models.School
+	classes  map[uint]models.Class
+	students map[uint]models.Student
+}
+
+func newMemoryService() *memoryService {
+	return &memoryService{
+		schools:  map[uint]models.School{},
+		classes:  map[uint]models.Class{},
+		students: map[uint]models.Student{},
+	}
+}
+
+var errNotFound = errors.New("record not found")
+
+func sortedValues[T any](m map[uint]T) []T {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, m[id])
+	}
+	return values
+}
+
+func page[T any](all []T, offset, limit int) ([]T, int64, error) {
+	total := int64(len(all))
+	if offset > len(all) {
+		offset = len(all)
+	}
+	end := min(offset+limit, len(all))
+	return all[offset:end], total, nil
+}
+
+func (m *memoryService) GetAllSchools() ([]models.School, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedValues(m.schools), nil
+}
+
+func (m *memoryService) ListSchools(offset, limit int) ([]models.School, int64, error) {
+	all, _ := m.GetAllSchools()
+	return page(all, offset, limit)
+}
+
+func (m *memoryService) GetSchoolByID(id uint) (*models.School, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	school, ok := m.schools[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &school, nil
+}
+
+func (m *memoryService) GetSchoolsByIDs(ids []uint) ([]models.School, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var schools []models.School
+	for _, id := range ids {
+		if school, ok := m.schools[id]; ok {
+			schools = append(schools, school)
+		}
+	}
+	return schools, nil
+}
+
+func (m *memoryService) CreateSchool(school *models.School) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	school.ID = m.nextID
+	m.schools[school.ID] = *school
+	return nil
+}
+
+func (m *memoryService) UpdateSchool(school *models.School) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.schools[school.ID] = *school
+	return nil
+}
+
+func (m *memoryService) DeleteSchool(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.schools, id)
+	return nil
+}
+
+func (m *memoryService) GetAllClasses() ([]models.Class, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedValues(m.classes), nil
+}
+
+func (m *memoryService) ListClasses(offset, limit int) ([]models.Class, int64, error) {
+	all, _ := m.GetAllClasses()
+	return page(all, offset, limit)
+}
+
+func (m *memoryService) GetClassByID(id uint) (*models.Class, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	class, ok := m.classes[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &class, nil
+}
+
+func (m *memoryService) GetClassesByIDs(ids []uint) ([]models.Class, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var classes []models.Class
+	for _, id := range ids {
+		if class, ok := m.classes[id]; ok {
+			classes = append(classes, class)
+		}
+	}
+	return classes, nil
+}
+
+func (m *memoryService) GetClassesBySchoolIDs(schoolIDs []uint) ([]models.Class, error) {
+	all, _ := m.GetAllClasses()
+	var classes []models.Class
+	for _, class := range all {
+		if slices.Contains(schoolIDs, class.SchoolID) {
+			classes = append(classes, class)
+		}
+	}
+	return classes, nil
+}
+
+func (m *memoryService) CreateClass(class *models.Class) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	class.ID = m.nextID
+	m.classes[class.ID] = *class
+	return nil
+}
+
+func (m *memoryService) UpdateClass(class *models.Class) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.classes[class.ID] = *class
+	return nil
+}
+
+func (m *memoryService) DeleteClass(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.classes, id)
+	return nil
+}
+
+func (m *memoryService) GetAllStudents() ([]models.Student, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return sortedValues(m.students), nil
+}
+
+func (m *memoryService) ListStudents(offset, limit int) ([]models.Student, int64, error) {
+	all, _ := m.GetAllStudents()
+	return page(all, offset, limit)
+}
+
+func (m *memoryService) GetStudentByID(id uint) (*models.Student, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	student, ok := m.students[id]
+	if !ok {
+		return nil, errNotFound
+	}
+	return &student, nil
+}
+
+func (m *memoryService) GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error) {
+	all, _ := m.GetAllStudents()
+	var students []models.Student
+	for _, student := range all {
+		if slices.Contains(classIDs, student.ClassID) {
+			students = append(students, student)
+		}
+	}
+	return students, nil
+}
+
+func (m *memoryService) CreateStudent(student *models.Student) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextID++
+	student.ID = m.nextID
+	m.students[student.ID] = *student
+	return nil
+}
+
+func (m *memoryService) UpdateStudent(student *models.Student) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.students[student.ID] = *student
+	return nil
+}
+
+func (m *memoryService) DeleteStudent(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.students, id)
+	return nil
+}
+
+func (m *memoryService) SearchStudents(query string, limit int) ([]services.StudentMatch, error) {
+	return nil, nil
+}
+
+func newServer(t *testing.T, wrap func(http.Handler) http.Handler) (*httptest.Server, *memoryService) {
+	gin.SetMode(gin.TestMode)
+	svc := newMemoryService()
+	router := routes.SetupRouter(
+		controllers.NewSchoolController(svc),
+		controllers.NewClassController(svc),
+		controllers.NewStudentController(svc),
+	)
+	var handler http.Handler = router
+	if wrap != nil {
+		handler = wrap(router)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, svc
+}
+
+func TestClient_StudentCRUD(t *testing.T) {
+	server, _ := newServer(t, nil)
+	c := client.New(server.URL)
+	ctx := context.Background()
+
+	student := &models.Student{StudentID: 1001, Name: "Jahnavi", Marks: 88, Address: models.Address{City: "Vizag"}}
+	assert.Nil(t, c.CreateStudent(ctx, student))
+	assert.NotZero(t, student.ID)
+
+	fetched, err := c.GetStudentByID(ctx, student.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, "Jahnavi", fetched.Name)
+	assert.Equal(t, "Vizag", fetched.Address.City)
+
+	fetched.Marks = 93
+	assert.Nil(t, c.UpdateStudent(ctx, fetched))
+
+	all, err := c.GetAllStudents(ctx)
+	assert.Nil(t, err)
+	assert.Len(t, all, 1)
+	assert.Equal(t, 93, all[0].Marks)
+
+	assert.Nil(t, c.DeleteStudent(ctx, student.ID))
+	_, err = c.GetStudentByID(ctx, student.ID)
+	assert.True(t, errors.Is(err, client.ErrNotFound))
+
+	var apiErr *client.APIError
+	assert.True(t, errors.As(err, &apiErr))
+	assert.Equal(t, "Student not found", apiErr.Message)
+}
+
+func TestClient_SchoolsIterator(t *testing.T) {
+	server, svc := newServer(t, nil)
+	for i := 0; i < 7; i++ {
+		svc.CreateSchool(&models.School{Name: "School"})
+	}
+	c := client.New(server.URL)
+
+	var ids []uint
+	it := c.Schools(context.Background(), 3)
+	for it.Next() {
+		ids = append(ids, it.Value().ID)
+	}
+	assert.Nil(t, it.Err())
+	assert.Equal(t, []uint{1, 2, 3, 4, 5, 6, 7}, ids)
+	assert.Equal(t, 7, it.Total())
+}
+
+func TestClient_SendsToken(t *testing.T) {
+	var auth atomic.Value
+	server, _ := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			auth.Store(r.Header.Get("Authorization"))
+			next.ServeHTTP(w, r)
+		})
+	})
+	c := client.New(server.URL, client.WithToken("secret"))
+
+	_, err := c.GetAllClasses(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer secret", auth.Load())
+}
+
+func TestClient_RetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server, _ := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				w.Write([]byte(`{"error":"try again"}`))
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	})
+	c := client.New(server.URL, client.WithRetries(3, time.Millisecond))
+
+	_, err := c.GetAllSchools(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, int32(3), calls.Load())
+}
+
+func TestClient_DoesNotRetryCreate(t *testing.T) {
+	var calls atomic.Int32
+	server, _ := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+	})
+	c := client.New(server.URL, client.WithRetries(3, time.Millisecond))
+
+	err := c.CreateSchool(context.Background(), &models.School{Name: "A"})
+	assert.True(t, errors.Is(err, client.ErrServer))
+	assert.Equal(t, int32(1), calls.Load())
+}
+
+func TestClient_ContextCancellation(t *testing.T) {
+	server, _ := newServer(t, func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		})
+	})
+	c := client.New(server.URL, client.WithRetries(10, time.Second))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetAllStudents(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.Less(t, time.Since(start), time.Second)
+}
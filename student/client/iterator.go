@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// Iterator walks a list endpoint page by page:
+//
+//	it := c.Students(ctx, 100)
+//	for it.Next() {
+//		student := it.Value()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	ctx      context.Context
+	client   *Client
+	path     string
+	pageSize int
+
+	page   []T
+	index  int
+	offset int
+	total  int
+	done   bool
+	err    error
+}
+
+func newIterator[T any](ctx context.Context, c *Client, path string, pageSize int) *Iterator[T] {
+	if pageSize < 1 {
+		pageSize = 50
+	}
+	return &Iterator[T]{ctx: ctx, client: c, path: path, pageSize: pageSize, index: -1, total: -1}
+}
+
+// Next advances to the next item, fetching another page when needed. It
+// returns false when the list is exhausted or an error occurred.
+func (it *Iterator[T]) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	if it.index+1 < len(it.page) {
+		it.index++
+		return true
+	}
+	if it.done {
+		return false
+	}
+
+	query := url.Values{}
+	query.Set("offset", strconv.Itoa(it.offset))
+	query.Set("limit", strconv.Itoa(it.pageSize))
+	var page []T
+	header, err := it.client.do(it.ctx, "GET", it.path, query, nil, &page)
+	if err != nil {
+		it.err = err
+		return false
+	}
+	if total, err := strconv.Atoi(header.Get("X-Total-Count")); err == nil {
+		it.total = total
+	}
+	it.page, it.index = page, 0
+	it.offset += len(page)
+	if len(page) < it.pageSize || (it.total >= 0 && it.offset >= it.total) {
+		it.done = true
+	}
+	return len(page) > 0
+}
+
+// Value returns the current item.
+func (it *Iterator[T]) Value() T {
+	return it.page[it.index]
+}
+
+// Total returns the total number of items reported by the server, or -1
+// before the first page has been fetched.
+func (it *Iterator[T]) Total() int {
+	return it.total
+}
+
+// Err returns the error that stopped iteration, if any.
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"stu/models"
+)
+
+// School methods
+
+func (c *Client) GetAllSchools(ctx context.Context) ([]models.School, error) {
+	var schools []models.School
+	_, err := c.do(ctx, http.MethodGet, "/schools/", nil, nil, &schools)
+	return schools, err
+}
+
+// Schools iterates over all schools, pageSize at a time.
+func (c *Client) Schools(ctx context.Context, pageSize int) *Iterator[models.School] {
+	return newIterator[models.School](ctx, c, "/schools/", pageSize)
+}
+
+func (c *Client) GetSchoolByID(ctx context.Context, id uint) (*models.School, error) {
+	var school models.School
+	if _, err := c.do(ctx, http.MethodGet, idPath("/schools/", id), nil, nil, &school); err != nil {
+		return nil, err
+	}
+	return &school, nil
+}
+
+// CreateSchool creates school and updates it with the stored row.
+func (c *Client) CreateSchool(ctx context.Context, school *models.School) error {
+	_, err := c.do(ctx, http.MethodPost, "/schools/", nil, school, school)
+	return err
+}
+
+func (c *Client) UpdateSchool(ctx context.Context, school *models.School) error {
+	_, err := c.do(ctx, http.MethodPut, idPath("/schools/", school.ID), nil, school, school)
+	return err
+}
+
+func (c *Client) DeleteSchool(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/schools/", id), nil, nil, nil)
+	return err
+}
+
+// Class methods
+
+func (c *Client) GetAllClasses(ctx context.Context) ([]models.Class, error) {
+	var classes []models.Class
+	_, err := c.do(ctx, http.MethodGet, "/classes/", nil, nil, &classes)
+	return classes, err
+}
+
+// Classes iterates over all classes, pageSize at a time.
+func (c *Client) Classes(ctx context.Context, pageSize int) *Iterator[models.Class] {
+	return newIterator[models.Class](ctx, c, "/classes/", pageSize)
+}
+
+func (c *Client) GetClassByID(ctx context.Context, id uint) (*models.Class, error) {
+	var class models.Class
+	if _, err := c.do(ctx, http.MethodGet, idPath("/classes/", id), nil, nil, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// CreateClass creates class and updates it with the stored row.
+func (c *Client) CreateClass(ctx context.Context, class *models.Class) error {
+	_, err := c.do(ctx, http.MethodPost, "/classes/", nil, class, class)
+	return err
+}
+
+func (c *Client) UpdateClass(ctx context.Context, class *models.Class) error {
+	_, err := c.do(ctx, http.MethodPut, idPath("/classes/", class.ID), nil, class, class)
+	return err
+}
+
+func (c *Client) DeleteClass(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/classes/", id), nil, nil, nil)
+	return err
+}
+
+// Student methods
+
+func (c *Client) GetAllStudents(ctx context.Context) ([]models.Student, error) {
+	var students []models.Student
+	_, err := c.do(ctx, http.MethodGet, "/students/", nil, nil, &students)
+	return students, err
+}
+
+// Students iterates over all students, pageSize at a time.
+func (c *Client) Students(ctx context.Context, pageSize int) *Iterator[models.Student] {
+	return newIterator[models.Student](ctx, c, "/students/", pageSize)
+}
+
+func (c *Client) GetStudentByID(ctx context.Context, id uint) (*models.Student, error) {
+	var student models.Student
+	if _, err := c.do(ctx, http.MethodGet, idPath("/students/", id), nil, nil, &student); err != nil {
+		return nil, err
+	}
+	return &student, nil
+}
+
+// CreateStudent creates student and updates it with the stored row.
+func (c *Client) CreateStudent(ctx context.Context, student *models.Student) error {
+	_, err := c.do(ctx, http.MethodPost, "/students/", nil, student, student)
+	return err
+}
+
+func (c *Client) UpdateStudent(ctx context.Context, student *models.Student) error {
+	_, err := c.do(ctx, http.MethodPut, idPath("/students/", student.ID), nil, student, student)
+	return err
+}
+
+func (c *Client) DeleteStudent(ctx context.Context, id uint) error {
+	_, err := c.do(ctx, http.MethodDelete, idPath("/students/", id), nil, nil, nil)
+	return err
+}
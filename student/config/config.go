@@ -0,0 +1,132 @@
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultDatabaseDSN = "host=localhost user=postgres password=jahnavi@2003 dbname=stu port=5432 sslmode=disable"
+
+// Config holds settings shared by every command of the binary.
+type Config struct {
+	DatabaseDSN string
+	HTTPAddr    string
+	// TrustedProxies lists the addresses or CIDRs of the proxies whose
+	// X-Forwarded-For header gives the client IP; by default none is
+	// trusted and the client IP is the peer address.
+	TrustedProxies []string
+	// GRPCAddr is where the gRPC API listens; empty disables it.
+	GRPCAddr string
+	// DebugAddr is where /debug/vars is served, apart from the API; empty
+	// disables it.
+	DebugAddr string
+
+	// OutboxSinks lists where the outbox relay publishes events: "bus"
+	// (in-process, feeds webhooks, the event stream and the cache; always
+	// required) and any of "file" and "nats".
+	OutboxSinks []string
+	OutboxFile  string
+	// NATSURL selects an external NATS server; when empty the nats sink
+	// starts an embedded server storing its streams in NATSStoreDir.
+	NATSURL      string
+	NATSStoreDir string
+
+	// RateLimits is parsed by ratelimit.ParseLimits; empty disables rate
+	// limiting. RateLimitStore is "memory" or "postgres". APIKeys is
+	// parsed by ratelimit.ParseAPIKeys; requests with one of its keys are
+	// limited per client rather than per IP.
+	RateLimits     string
+	RateLimitStore string
+	APIKeys        string
+
+	// CacheSize enables the repository cache when positive; records are
+	// cached for at most CacheTTL. The cache needs the "bus" outbox sink.
+	CacheSize int
+	CacheTTL  time.Duration
+
+	// AppealSLA is how long reviewers have to resolve a grade appeal.
+	AppealSLA time.Duration
+}
+
+// Load reads the configuration from the environment, falling back to the
+// defaults used for local development.
+func Load() Config {
+	return Config{
+		DatabaseDSN: getEnv("DATABASE_DSN", defaultDatabaseDSN),
+		HTTPAddr:    getEnv("HTTP_ADDR", ":8080"),
+		GRPCAddr:    getEnvOrEmpty("GRPC_ADDR", ":9090"),
+		DebugAddr:   getEnv("DEBUG_ADDR", ""),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+
+		OutboxSinks:  strings.Split(getEnv("OUTBOX_SINKS", "bus"), ","),
+		OutboxFile:   getEnv("OUTBOX_FILE", "events.jsonl"),
+		NATSURL:      getEnv("NATS_URL", ""),
+		NATSStoreDir: getEnv("NATS_STORE_DIR", "nats-data"),
+
+		RateLimits:     getEnvOrEmpty("RATE_LIMITS", "default=50:100,POST=5:20"),
+		RateLimitStore: getEnv("RATE_LIMIT_STORE", "memory"),
+		APIKeys:        getEnv("API_KEYS", ""),
+
+		CacheSize: getEnvInt("CACHE_SIZE", 0),
+		CacheTTL:  getEnvDuration("CACHE_TTL", 30*time.Second),
+
+		AppealSLA: getEnvDuration("APPEAL_SLA", 7*24*time.Hour),
+	}
+}
+
+func getEnv(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok && value != "" {
+		return value
+	}
+	return fallback
+}
+
+// getEnvOrEmpty is getEnv for settings that an empty value turns off: it
+// only falls back when key is unset.
+func getEnvOrEmpty(key, fallback string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return fallback
+}
+
+// getEnvList splits a comma separated setting, dropping empty entries.
+func getEnvList(key string) []string {
+	var list []string
+	for _, entry := range strings.Split(getEnv(key, ""), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			list = append(list, entry)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, fallback int) int {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Ignoring %s=%q: %v", key, value, err)
+		return fallback
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := getEnv(key, "")
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Ignoring %s=%q: %v", key, value, err)
+		return fallback
+	}
+	return d
+}
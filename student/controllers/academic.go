@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/models"
+	"stu/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AcademicController struct {
+	service services.AcademicService
+}
+
+func NewAcademicController(service services.AcademicService) *AcademicController {
+	return &AcademicController{
+		service: service,
+	}
+}
+
+// periodInput is the body of academic year and term requests.
+type periodInput struct {
+	Name      string `json:"name"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+func (p periodInput) dates() (time.Time, time.Time, bool) {
+	start, err := time.Parse(dateLayout, p.StartDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.Parse(dateLayout, p.EndDate)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+func (ac *AcademicController) GetAcademicYears(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	years, err := ac.service.GetAcademicYears(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, years)
+}
+
+func (ac *AcademicController) GetAcademicYearByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	year, err := ac.service.GetAcademicYearByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Academic year not found"})
+		return
+	}
+	c.JSON(http.StatusOK, year)
+}
+
+func (ac *AcademicController) CreateAcademicYear(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	var input periodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	start, end, ok := input.dates()
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date must look like 2006-01-02"})
+		return
+	}
+	year := models.AcademicYear{SchoolID: uint(id), Name: input.Name, StartDate: start, EndDate: end}
+	err = ac.service.CreateAcademicYear(&year)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, year)
+}
+
+func (ac *AcademicController) UpdateAcademicYear(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	var input periodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	start, end, ok := input.dates()
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date must look like 2006-01-02"})
+		return
+	}
+	year := models.AcademicYear{Name: input.Name, StartDate: start, EndDate: end}
+	year.ID = uint(id)
+	err = ac.service.UpdateAcademicYear(&year)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, year)
+}
+
+func (ac *AcademicController) DeleteAcademicYear(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	err = ac.service.DeleteAcademicYear(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Academic year deleted successfully"})
+}
+
+func (ac *AcademicController) GetTerms(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	terms, err := ac.service.GetTerms(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, terms)
+}
+
+func (ac *AcademicController) GetTermByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	term, err := ac.service.GetTermByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Term not found"})
+		return
+	}
+	c.JSON(http.StatusOK, term)
+}
+
+func (ac *AcademicController) GetActiveTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	term, err := ac.service.GetActiveTerm(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, term)
+}
+
+func (ac *AcademicController) CreateTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid academic year ID"})
+		return
+	}
+	var input periodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	start, end, ok := input.dates()
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date must look like 2006-01-02"})
+		return
+	}
+	term := models.Term{AcademicYearID: uint(id), Name: input.Name, StartDate: start, EndDate: end}
+	err = ac.service.CreateTerm(&term)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, term)
+}
+
+func (ac *AcademicController) UpdateTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	var input periodInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	start, end, ok := input.dates()
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date must look like 2006-01-02"})
+		return
+	}
+	term := models.Term{Name: input.Name, StartDate: start, EndDate: end}
+	term.ID = uint(id)
+	err = ac.service.UpdateTerm(&term)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, term)
+}
+
+func (ac *AcademicController) DeleteTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	err = ac.service.DeleteTerm(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Term deleted successfully"})
+}
+
+func (ac *AcademicController) ActivateTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	term, err := ac.service.ActivateTerm(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, term)
+}
+
+func (ac *AcademicController) GetTermEnrollments(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	var classID uint64
+	if raw := c.Query("class_id"); raw != "" {
+		classID, err = strconv.ParseUint(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+			return
+		}
+	}
+	enrollments, err := ac.service.GetTermEnrollments(uint(id), uint(classID))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, enrollments)
+}
+
+func (ac *AcademicController) GetStudentHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	enrollments, err := ac.service.GetStudentHistory(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, enrollments)
+}
+
+func (ac *AcademicController) RecordTermMarks(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	termID, err := strconv.ParseUint(c.Param("term_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	var input struct {
+		Marks *int `json:"marks"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil || input.Marks == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	enrollment, err := ac.service.RecordTermMarks(uint(id), uint(termID), *input.Marks)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, enrollment)
+}
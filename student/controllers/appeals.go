@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/models"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AppealController struct {
+	service services.AppealService
+}
+
+func NewAppealController(service services.AppealService) *AppealController {
+	return &AppealController{
+		service: service,
+	}
+}
+
+// GetSchoolAppeals lists the school's unresolved appeals; ?overdue=true
+// keeps only those past their due date.
+func (ac *AppealController) GetSchoolAppeals(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	var overdue bool
+	if raw := c.Query("overdue"); raw != "" {
+		overdue, err = strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "overdue must be true or false"})
+			return
+		}
+	}
+	appeals, err := ac.service.GetSchoolAppeals(uint(id), overdue)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeals)
+}
+
+func (ac *AppealController) GetStudentAppeals(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	appeals, err := ac.service.GetStudentAppeals(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeals)
+}
+
+func (ac *AppealController) CreateAppeal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	var input struct {
+		ExamID     uint   `json:"exam_id"`
+		GuardianID *uint  `json:"guardian_id"`
+		Reason     string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	appeal := models.Appeal{StudentID: uint(id), ExamID: input.ExamID, GuardianID: input.GuardianID, Reason: input.Reason}
+	if err := ac.service.CreateAppeal(&appeal); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, appeal)
+}
+
+func (ac *AppealController) GetAppealByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appeal ID"})
+		return
+	}
+	appeal, err := ac.service.GetAppealByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Appeal not found"})
+		return
+	}
+	c.JSON(http.StatusOK, appeal)
+}
+
+func (ac *AppealController) AssignAppeal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appeal ID"})
+		return
+	}
+	var input struct {
+		Reviewer string `json:"reviewer"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	appeal, err := ac.service.AssignAppeal(uint(id), input.Reviewer)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeal)
+}
+
+func (ac *AppealController) ResolveAppeal(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid appeal ID"})
+		return
+	}
+	var resolution services.AppealResolution
+	if err := c.ShouldBindJSON(&resolution); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	appeal, err := ac.service.ResolveAppeal(uint(id), resolution)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, appeal)
+}
+
+func (ac *AppealController) GetMarkChanges(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	changes, err := ac.service.GetMarkChanges(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
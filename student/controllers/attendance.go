@@ -0,0 +1,157 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"stu/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dateLayout is the format of dates in attendance requests.
+const dateLayout = "2006-01-02"
+
+type AttendanceController struct {
+	service services.AttendanceService
+}
+
+func NewAttendanceController(service services.AttendanceService) *AttendanceController {
+	return &AttendanceController{
+		service: service,
+	}
+}
+
+func (ac *AttendanceController) RecordRollCall(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	var input struct {
+		Date    string                   `json:"date"`
+		Period  int                      `json:"period"`
+		Entries []services.RollCallEntry `json:"entries"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	date, err := time.Parse(dateLayout, input.Date)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must look like 2006-01-02"})
+		return
+	}
+	records, err := ac.service.RecordRollCall(uint(id), services.RollCall{Date: date, Period: input.Period, Entries: input.Entries})
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, records)
+}
+
+func (ac *AttendanceController) GetClassAttendance(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	date, err := time.Parse(dateLayout, c.Query("date"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must look like 2006-01-02"})
+		return
+	}
+	records, err := ac.service.GetClassAttendance(uint(id), date)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, records)
+}
+
+func (ac *AttendanceController) GetClassAttendanceSummary(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	dates, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	summary, err := ac.service.GetClassAttendanceSummary(uint(id), dates)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+func (ac *AttendanceController) GetStudentAttendanceSummary(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	dates, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	summary, err := ac.service.GetStudentAttendanceSummary(uint(id), dates)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+func (ac *AttendanceController) GetChronicAbsentees(c *gin.Context) {
+	dates, err := parseDateRange(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	opts := services.ChronicAbsenceOptions{Dates: dates}
+	if value, ok := c.GetQuery("threshold"); ok {
+		if opts.Threshold, err = strconv.ParseFloat(value, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid threshold"})
+			return
+		}
+	}
+	for param, target := range map[string]*uint{"school_id": &opts.SchoolID, "class_id": &opts.ClassID} {
+		if value, ok := c.GetQuery(param); ok {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = uint(id)
+		}
+	}
+	absentees, err := ac.service.GetChronicAbsentees(opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, absentees)
+}
+
+// parseDateRange reads the optional from and to query parameters.
+func parseDateRange(c *gin.Context) (services.DateRange, error) {
+	var dates services.DateRange
+	var err error
+	if value, ok := c.GetQuery("from"); ok {
+		if dates.From, err = time.Parse(dateLayout, value); err != nil {
+			return dates, errors.New("from must look like 2006-01-02")
+		}
+	}
+	if value, ok := c.GetQuery("to"); ok {
+		if dates.To, err = time.Parse(dateLayout, value); err != nil {
+			return dates, errors.New("to must look like 2006-01-02")
+		}
+	}
+	return dates, nil
+}
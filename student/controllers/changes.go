@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangeController serves the incremental change feed and the batch
+// upload used by offline sync clients.
+type ChangeController struct {
+	service services.ChangeService
+}
+
+func NewChangeController(service services.ChangeService) *ChangeController {
+	return &ChangeController{
+		service: service,
+	}
+}
+
+func (cc *ChangeController) GetChanges(c *gin.Context) {
+	var since uint64
+	if value, ok := c.GetQuery("since"); ok {
+		var err error
+		if since, err = strconv.ParseUint(value, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since cursor"})
+			return
+		}
+	}
+	limit := maxPageLimit
+	if value, ok := c.GetQuery("limit"); ok {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	changes, err := cc.service.GetChanges(since, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+func (cc *ChangeController) ApplyChanges(c *gin.Context) {
+	var upload struct {
+		Changes []services.ClientChange `json:"changes"`
+	}
+	if err := c.ShouldBindJSON(&upload); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	results, err := cc.service.ApplyChanges(upload.Changes)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"results": results})
+}
@@ -1,8 +1,10 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"stu/models"
 	"stu/services"
 	"testing"
@@ -10,6 +12,35 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+
+	defaultSearchLimit = 20
+)
+
+// parsePage reads the optional offset and limit query parameters. paged is
+// false when neither is present, in which case the full list is returned.
+func parsePage(c *gin.Context) (offset, limit int, paged bool, err error) {
+	offsetParam, hasOffset := c.GetQuery("offset")
+	limitParam, hasLimit := c.GetQuery("limit")
+	if !hasOffset && !hasLimit {
+		return 0, 0, false, nil
+	}
+	limit = defaultPageLimit
+	if hasOffset {
+		if offset, err = strconv.Atoi(offsetParam); err != nil || offset < 0 {
+			return 0, 0, true, errors.New("invalid offset")
+		}
+	}
+	if hasLimit {
+		if limit, err = strconv.Atoi(limitParam); err != nil || limit < 1 {
+			return 0, 0, true, errors.New("invalid limit")
+		}
+	}
+	return offset, min(limit, maxPageLimit), true, nil
+}
+
 type SchoolController struct {
 	service services.SchoolService
 }
@@ -21,6 +52,19 @@ func NewSchoolController(service services.SchoolService) *SchoolController {
 }
 
 func (sc *SchoolController) GetAllSchools(c *gin.Context) {
+	if offset, limit, paged, err := parsePage(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	} else if paged {
+		schools, total, err := sc.service.ListSchools(offset, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+		c.JSON(http.StatusOK, schools)
+		return
+	}
 	schools, err := sc.service.GetAllSchools()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -102,6 +146,19 @@ func NewClassController(service services.ClassService) *ClassController {
 }
 
 func (cc *ClassController) GetAllClasses(c *gin.Context) {
+	if offset, limit, paged, err := parsePage(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	} else if paged {
+		classes, total, err := cc.service.ListClasses(offset, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+		c.JSON(http.StatusOK, classes)
+		return
+	}
 	classes, err := cc.service.GetAllClasses()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -132,7 +189,7 @@ func (cc *ClassController) CreateClass(c *gin.Context) {
 	}
 	err := cc.service.CreateClass(&newClass)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, newClass)
@@ -152,7 +209,7 @@ func (cc *ClassController) UpdateClass(c *gin.Context) {
 	updatedClass.ID = uint(id)
 	err = cc.service.UpdateClass(&updatedClass)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, updatedClass)
@@ -173,7 +230,8 @@ func (cc *ClassController) DeleteClass(c *gin.Context) {
 }
 
 type StudentController struct {
-	service services.StudentService
+	service  services.StudentService
+	rankings services.RankingService
 }
 
 func NewStudentController(service services.StudentService) *StudentController {
@@ -182,7 +240,27 @@ func NewStudentController(service services.StudentService) *StudentController {
 	}
 }
 
+// WithRankings lets GetStudentByID restrict the student's percentile to a
+// term or subject.
+func (sc *StudentController) WithRankings(rankings services.RankingService) *StudentController {
+	sc.rankings = rankings
+	return sc
+}
+
 func (sc *StudentController) GetAllStudents(c *gin.Context) {
+	if offset, limit, paged, err := parsePage(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	} else if paged {
+		students, total, err := sc.service.ListStudents(offset, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+		c.JSON(http.StatusOK, students)
+		return
+	}
 	students, err := sc.service.GetAllStudents()
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -191,6 +269,30 @@ func (sc *StudentController) GetAllStudents(c *gin.Context) {
 	c.JSON(http.StatusOK, students)
 }
 
+func (sc *StudentController) SearchStudents(c *gin.Context) {
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "q is required"})
+		return
+	}
+	limit := defaultSearchLimit
+	if value, ok := c.GetQuery("limit"); ok {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit < 1 || limit > maxPageLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid limit"})
+			return
+		}
+	}
+	matches, err := sc.service.SearchStudents(query, limit)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, matches)
+}
+
+// GetStudentByID includes the student's percentile, among the students
+// ranked by term_id and subject when they are given.
 func (sc *StudentController) GetStudentByID(c *gin.Context) {
 	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
 	if err != nil {
@@ -202,6 +304,21 @@ func (sc *StudentController) GetStudentByID(c *gin.Context) {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Student not found"})
 		return
 	}
+	opts, err := rankingFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	if opts.TermID != 0 || opts.Subject != "" {
+		if sc.rankings == nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Percentiles by term or subject are not available"})
+			return
+		}
+		if student.Percentile, err = sc.rankings.GetStudentPercentile(student.ID, opts); err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
 	c.JSON(http.StatusOK, student)
 }
 
@@ -213,7 +330,7 @@ func (sc *StudentController) CreateStudent(c *gin.Context) {
 	}
 	err := sc.service.CreateStudent(&newStudent)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusCreated, newStudent)
@@ -233,7 +350,7 @@ func (sc *StudentController) UpdateStudent(c *gin.Context) {
 	updatedStudent.ID = uint(id)
 	err = sc.service.UpdateStudent(&updatedStudent)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 	c.JSON(http.StatusOK, updatedStudent)
@@ -1,17 +1,26 @@
 package controllers_test
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"stu/assessment"
 	"stu/controllers"
+	"stu/events"
+	"stu/fees"
 	"stu/models"
+	"stu/repository"
+	"stu/services"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 // MockSchoolService is a mock implementation of SchoolService for testing purposes.
@@ -24,11 +33,21 @@ func (m *MockSchoolService) GetAllSchools() ([]models.School, error) {
 	return args.Get(0).([]models.School), args.Error(1)
 }
 
+func (m *MockSchoolService) ListSchools(offset, limit int) ([]models.School, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.School), args.Get(1).(int64), args.Error(2)
+}
+
 func (m *MockSchoolService) GetSchoolByID(id uint) (*models.School, error) {
 	args := m.Called(id)
 	return args.Get(0).(*models.School), args.Error(1)
 }
 
+func (m *MockSchoolService) GetSchoolsByIDs(ids []uint) ([]models.School, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.School), args.Error(1)
+}
+
 func (m *MockSchoolService) CreateSchool(school *models.School) error {
 	args := m.Called(school)
 	return args.Error(0)
@@ -74,6 +93,50 @@ func TestSchoolController_GetAllSchools(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+func TestSchoolController_GetAllSchools_Paged(t *testing.T) {
+	mockService := new(MockSchoolService)
+	controller := controllers.NewSchoolController(mockService)
+
+	// Mock data
+	mockSchools := []models.School{
+		{Name: "School C", ClassID: 3},
+	}
+
+	// Mock the service method
+	mockService.On("ListSchools", 2, 1).Return(mockSchools, int64(3), nil)
+
+	// Create a gin context
+	router := gin.Default()
+	router.GET("/schools", controller.GetAllSchools)
+	req, _ := http.NewRequest("GET", "/schools?offset=2&limit=1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	// Assertions
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+	var schools []models.School
+	err := json.NewDecoder(resp.Body).Decode(&schools)
+	assert.Nil(t, err)
+	assert.Equal(t, mockSchools, schools)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestSchoolController_GetAllSchools_InvalidPage(t *testing.T) {
+	mockService := new(MockSchoolService)
+	controller := controllers.NewSchoolController(mockService)
+
+	router := gin.Default()
+	router.GET("/schools", controller.GetAllSchools)
+	req, _ := http.NewRequest("GET", "/schools?limit=0", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertExpectations(t)
+}
+
 func TestSchoolController_GetSchoolByID(t *testing.T) {
 	mockService := new(MockSchoolService)
 	controller := controllers.NewSchoolController(mockService)
@@ -177,3 +240,1761 @@ func TestSchoolController_DeleteSchool(t *testing.T) {
 	mockService.AssertExpectations(t)
 }
 
+// MockEventStreamService is a mock implementation of EventStreamService for testing purposes.
+type MockEventStreamService struct {
+	mock.Mock
+	stream chan events.Event
+}
+
+func (m *MockEventStreamService) SubscribeStudentEvents(filter services.EventFilter) (<-chan events.Event, func()) {
+	m.Called(filter)
+	return m.stream, func() {}
+}
+
+func (m *MockEventStreamService) StudentEventsSince(position uint64, filter services.EventFilter, limit int) ([]events.Event, error) {
+	args := m.Called(position, filter, limit)
+	return args.Get(0).([]events.Event), args.Error(1)
+}
+
+// readSSE returns the next message of an event stream with its fields
+// joined by newlines, or the heartbeat comment.
+func readSSE(t *testing.T, reader *bufio.Reader) string {
+	var lines []string
+	for {
+		line, err := reader.ReadString('\n')
+		if !assert.Nil(t, err) {
+			return ""
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			if len(lines) == 0 {
+				continue
+			}
+			return strings.Join(lines, "\n")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func TestEventController_StreamStudentEvents_Resume(t *testing.T) {
+	mockService := &MockEventStreamService{stream: make(chan events.Event, 2)}
+	controller := controllers.NewEventController(mockService, time.Hour)
+	filter := services.EventFilter{ClassID: 3}
+
+	missed := events.Event{ID: 5, Position: 2, Type: events.StudentUpdated, EntityType: "student", EntityID: 7, ClassID: 3, Data: json.RawMessage(`{}`)}
+	// live took its ID before missed but committed after it.
+	live := events.Event{ID: 4, Position: 3, Type: events.StudentDeleted, EntityType: "student", EntityID: 7, ClassID: 3, Data: json.RawMessage(`{}`)}
+	mockService.On("SubscribeStudentEvents", filter).Return()
+	mockService.On("StudentEventsSince", uint64(1), filter, 500).Return([]events.Event{missed}, nil)
+	// The replayed event also arrives live and must not be sent twice.
+	mockService.stream <- missed
+	mockService.stream <- live
+
+	router := gin.Default()
+	router.GET("/events", controller.StreamStudentEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	req, _ := http.NewRequest("GET", server.URL+"/events?class_id=3", nil)
+	req.Header.Set("Last-Event-ID", "1")
+	resp, err := http.DefaultClient.Do(req)
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, "retry: 3000", readSSE(t, reader))
+	assert.True(t, strings.HasPrefix(readSSE(t, reader), "id: 2\nevent: student.updated\ndata: "))
+	assert.True(t, strings.HasPrefix(readSSE(t, reader), "id: 3\nevent: student.deleted\ndata: "))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestEventController_StreamStudentEvents_Heartbeat(t *testing.T) {
+	mockService := &MockEventStreamService{stream: make(chan events.Event)}
+	controller := controllers.NewEventController(mockService, 10*time.Millisecond)
+	mockService.On("SubscribeStudentEvents", services.EventFilter{SchoolID: 5}).Return()
+
+	router := gin.Default()
+	router.GET("/events", controller.StreamStudentEvents)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/events?school_id=5")
+	assert.Nil(t, err)
+	defer resp.Body.Close()
+
+	reader := bufio.NewReader(resp.Body)
+	assert.Equal(t, "retry: 3000", readSSE(t, reader))
+	assert.Equal(t, ": heartbeat", readSSE(t, reader))
+
+	mockService.AssertExpectations(t)
+}
+
+func TestEventController_StreamStudentEvents_InvalidScope(t *testing.T) {
+	controller := controllers.NewEventController(new(MockEventStreamService), 0)
+
+	router := gin.Default()
+	router.GET("/events", controller.StreamStudentEvents)
+	req, _ := http.NewRequest("GET", "/events?class_id=abc", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// MockChangeService is a mock implementation of ChangeService for testing purposes.
+type MockChangeService struct {
+	mock.Mock
+}
+
+func (m *MockChangeService) GetChanges(since uint64, limit int) (*services.ChangeSet, error) {
+	args := m.Called(since, limit)
+	return args.Get(0).(*services.ChangeSet), args.Error(1)
+}
+
+func (m *MockChangeService) ApplyChanges(changes []services.ClientChange) ([]services.ChangeResult, error) {
+	args := m.Called(changes)
+	return args.Get(0).([]services.ChangeResult), args.Error(1)
+}
+
+func TestChangeController_GetChanges(t *testing.T) {
+	mockService := new(MockChangeService)
+	controller := controllers.NewChangeController(mockService)
+
+	changeSet := &services.ChangeSet{
+		Changes: []services.Change{
+			{Cursor: 12, EntityType: "student", EntityID: 4, Deleted: true},
+		},
+		NextCursor: 12,
+	}
+	mockService.On("GetChanges", uint64(10), 100).Return(changeSet, nil)
+
+	router := gin.Default()
+	router.GET("/changes", controller.GetChanges)
+	req, _ := http.NewRequest("GET", "/changes?since=10&limit=100", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body services.ChangeSet
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, uint64(12), body.NextCursor)
+	assert.Equal(t, 1, len(body.Changes))
+	assert.True(t, body.Changes[0].Deleted)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestChangeController_GetChanges_InvalidCursor(t *testing.T) {
+	controller := controllers.NewChangeController(new(MockChangeService))
+
+	router := gin.Default()
+	router.GET("/changes", controller.GetChanges)
+	req, _ := http.NewRequest("GET", "/changes?since=-1", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestChangeController_ApplyChanges(t *testing.T) {
+	mockService := new(MockChangeService)
+	controller := controllers.NewChangeController(mockService)
+
+	changes := []services.ClientChange{
+		{EntityType: "student", Operation: services.OperationUpsert, Data: json.RawMessage(`{"ID":3,"name":"Ann"}`)},
+	}
+	results := []services.ChangeResult{{Index: 0, Status: services.ChangeConflict, EntityID: 3}}
+	mockService.On("ApplyChanges", changes).Return(results, nil)
+
+	router := gin.Default()
+	router.POST("/changes", controller.ApplyChanges)
+	payload, _ := json.Marshal(map[string]interface{}{"changes": changes})
+	req, _ := http.NewRequest("POST", "/changes", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body struct {
+		Results []services.ChangeResult `json:"results"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, results, body.Results)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestChangeController_ApplyChanges_TooMany(t *testing.T) {
+	mockService := new(MockChangeService)
+	controller := controllers.NewChangeController(mockService)
+	mockService.On("ApplyChanges", mock.Anything).Return([]services.ChangeResult(nil), &services.ValidationError{Message: "too many"})
+
+	router := gin.Default()
+	router.POST("/changes", controller.ApplyChanges)
+	req, _ := http.NewRequest("POST", "/changes", bytes.NewBufferString(`{"changes":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockStudentService is a mock implementation of StudentService for testing purposes.
+type MockStudentService struct {
+	mock.Mock
+}
+
+func (m *MockStudentService) GetAllStudents() ([]models.Student, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentService) ListStudents(offset, limit int) ([]models.Student, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.Student), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStudentService) GetStudentByID(id uint) (*models.Student, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Student), args.Error(1)
+}
+
+func (m *MockStudentService) GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error) {
+	args := m.Called(classIDs)
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentService) CreateStudent(student *models.Student) error {
+	args := m.Called(student)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) UpdateStudent(student *models.Student) error {
+	args := m.Called(student)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) DeleteStudent(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) SearchStudents(query string, limit int) ([]services.StudentMatch, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]services.StudentMatch), args.Error(1)
+}
+
+func TestStudentController_SearchStudents(t *testing.T) {
+	mockService := new(MockStudentService)
+	controller := controllers.NewStudentController(mockService)
+
+	matches := []services.StudentMatch{{
+		Student:    models.Student{Name: "Jahnavi"},
+		Score:      0.5,
+		Highlights: map[string]string{"name": "<mark>Jahnavi</mark>"},
+	}}
+	mockService.On("SearchStudents", "Jahnvi", 20).Return(matches, nil)
+
+	router := gin.Default()
+	router.GET("/students/search", controller.SearchStudents)
+	req, _ := http.NewRequest("GET", "/students/search?q=Jahnvi", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []services.StudentMatch
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, matches, body)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStudentController_SearchStudents_MissingQuery(t *testing.T) {
+	controller := controllers.NewStudentController(new(MockStudentService))
+
+	router := gin.Default()
+	router.GET("/students/search", controller.SearchStudents)
+	req, _ := http.NewRequest("GET", "/students/search?q=%20", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// MockStatsService is a mock implementation of StatsService for testing purposes.
+type MockStatsService struct {
+	mock.Mock
+}
+
+func (m *MockStatsService) GetClassStats(id uint, opts services.StatsOptions) (*services.MarksStats, error) {
+	args := m.Called(id, opts)
+	return args.Get(0).(*services.MarksStats), args.Error(1)
+}
+
+func (m *MockStatsService) GetSchoolStats(id uint, opts services.StatsOptions) (*services.MarksStats, error) {
+	args := m.Called(id, opts)
+	return args.Get(0).(*services.MarksStats), args.Error(1)
+}
+
+func TestStatsController_GetClassStats(t *testing.T) {
+	mockService := new(MockStatsService)
+	controller := controllers.NewStatsController(mockService)
+
+	from, to := 40, 60
+	result := &services.MarksStats{
+		Count: 2, Mean: 50, Median: 50, Min: 45, Max: 55, PassMark: 50, PassRate: 0.5,
+		Histogram: []services.HistogramBucket{{From: &from, To: &to, Count: 2}},
+	}
+	opts := services.StatsOptions{PassMark: 50, Buckets: []int{40, 60}}
+	mockService.On("GetClassStats", uint(3), opts).Return(result, nil)
+
+	router := gin.Default()
+	router.GET("/classes/:id/stats", controller.GetClassStats)
+	req, _ := http.NewRequest("GET", "/classes/3/stats?pass_mark=50&buckets=40,60", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body services.MarksStats
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, *result, body)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStatsController_GetSchoolStats_NotFound(t *testing.T) {
+	mockService := new(MockStatsService)
+	controller := controllers.NewStatsController(mockService)
+	opts := services.StatsOptions{PassMark: services.DefaultPassMark}
+	mockService.On("GetSchoolStats", uint(9), opts).Return((*services.MarksStats)(nil), gorm.ErrRecordNotFound)
+
+	router := gin.Default()
+	router.GET("/schools/:id/stats", controller.GetSchoolStats)
+	req, _ := http.NewRequest("GET", "/schools/9/stats", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStatsController_GetClassStats_InvalidBuckets(t *testing.T) {
+	controller := controllers.NewStatsController(new(MockStatsService))
+
+	router := gin.Default()
+	router.GET("/classes/:id/stats", controller.GetClassStats)
+	req, _ := http.NewRequest("GET", "/classes/3/stats?bucket_size=0", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+// MockRankingService is a mock implementation of RankingService for testing purposes.
+type MockRankingService struct {
+	mock.Mock
+}
+
+func (m *MockRankingService) GetClassRanking(id uint, opts services.RankingOptions) ([]services.RankedStudent, int64, error) {
+	args := m.Called(id, opts)
+	return args.Get(0).([]services.RankedStudent), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRankingService) GetSchoolRanking(id uint, opts services.RankingOptions) ([]services.RankedStudent, int64, error) {
+	args := m.Called(id, opts)
+	return args.Get(0).([]services.RankedStudent), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRankingService) GetDistrictRanking(opts services.RankingOptions) ([]services.RankedStudent, int64, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]services.RankedStudent), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRankingService) GetStudentPercentile(id uint, opts services.RankingOptions) (*models.Percentile, error) {
+	args := m.Called(id, opts)
+	return args.Get(0).(*models.Percentile), args.Error(1)
+}
+
+func TestStudentController_GetStudentByID_PercentileInTermAndSubject(t *testing.T) {
+	students := new(MockStudentService)
+	rankings := new(MockRankingService)
+	controller := controllers.NewStudentController(students).WithRankings(rankings)
+
+	student := &models.Student{Name: "Jahnavi", Marks: 90, Percentile: &models.Percentile{District: 50}}
+	student.ID = 7
+	class := 75.0
+	students.On("GetStudentByID", uint(7)).Return(student, nil)
+	rankings.On("GetStudentPercentile", uint(7), services.RankingOptions{TermID: 5, Subject: "Maths"}).
+		Return(&models.Percentile{Class: &class, District: 80}, nil)
+
+	router := gin.Default()
+	router.GET("/students/:id", controller.GetStudentByID)
+	req, _ := http.NewRequest("GET", "/students/7?term_id=5&subject=Maths", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body models.Student
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 80.0, body.Percentile.District)
+	assert.Equal(t, &class, body.Percentile.Class)
+
+	req, _ = http.NewRequest("GET", "/students/7?term_id=x", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	rankings.AssertExpectations(t)
+}
+
+func TestRankingController_GetClassRanking(t *testing.T) {
+	mockService := new(MockRankingService)
+	controller := controllers.NewRankingController(mockService)
+
+	ranked := []services.RankedStudent{
+		{Rank: 1, Percentile: 83.3, Student: models.Student{Name: "A", Marks: 90}},
+		{Rank: 2, Percentile: 50, Student: models.Student{Name: "B", Marks: 80}},
+		{Rank: 2, Percentile: 50, Student: models.Student{Name: "C", Marks: 80}},
+	}
+	opts := services.RankingOptions{Method: services.RankingDense, Offset: 0, Limit: 3}
+	mockService.On("GetClassRanking", uint(4), opts).Return(ranked, int64(3), nil)
+
+	router := gin.Default()
+	router.GET("/classes/:id/rankings", controller.GetClassRanking)
+	req, _ := http.NewRequest("GET", "/classes/4/rankings?method=dense&limit=3", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "3", resp.Header().Get("X-Total-Count"))
+	var body []services.RankedStudent
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, ranked, body)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRankingController_GetSchoolRanking_TermAndSubject(t *testing.T) {
+	mockService := new(MockRankingService)
+	controller := controllers.NewRankingController(mockService)
+	opts := services.RankingOptions{TermID: 4, Subject: "Maths", Limit: 50}
+	mockService.On("GetSchoolRanking", uint(2), opts).Return([]services.RankedStudent{}, int64(0), nil)
+
+	router := gin.Default()
+	router.GET("/schools/:id/rankings", controller.GetSchoolRanking)
+	req, _ := http.NewRequest("GET", "/schools/2/rankings?term_id=4&subject=Maths", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	req, _ = http.NewRequest("GET", "/schools/2/rankings?term_id=first", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestRankingController_GetDistrictRanking_InvalidMethod(t *testing.T) {
+	mockService := new(MockRankingService)
+	controller := controllers.NewRankingController(mockService)
+	opts := services.RankingOptions{Method: "olympic", Limit: 50}
+	mockService.On("GetDistrictRanking", opts).Return([]services.RankedStudent(nil), int64(0), &services.ValidationError{Message: "bad method"})
+
+	router := gin.Default()
+	router.GET("/rankings", controller.GetDistrictRanking)
+	req, _ := http.NewRequest("GET", "/rankings?method=olympic", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockAttendanceService is a mock implementation of AttendanceService for testing purposes.
+type MockAttendanceService struct {
+	mock.Mock
+}
+
+func (m *MockAttendanceService) RecordRollCall(classID uint, rollCall services.RollCall) ([]models.AttendanceRecord, error) {
+	args := m.Called(classID, rollCall)
+	return args.Get(0).([]models.AttendanceRecord), args.Error(1)
+}
+
+func (m *MockAttendanceService) GetClassAttendance(classID uint, date time.Time) ([]models.AttendanceRecord, error) {
+	args := m.Called(classID, date)
+	return args.Get(0).([]models.AttendanceRecord), args.Error(1)
+}
+
+func (m *MockAttendanceService) GetStudentAttendanceSummary(studentID uint, dates services.DateRange) (*services.AttendanceSummary, error) {
+	args := m.Called(studentID, dates)
+	return args.Get(0).(*services.AttendanceSummary), args.Error(1)
+}
+
+func (m *MockAttendanceService) GetClassAttendanceSummary(classID uint, dates services.DateRange) (*services.ClassAttendanceSummary, error) {
+	args := m.Called(classID, dates)
+	return args.Get(0).(*services.ClassAttendanceSummary), args.Error(1)
+}
+
+func (m *MockAttendanceService) GetChronicAbsentees(opts services.ChronicAbsenceOptions) ([]services.AttendanceSummary, error) {
+	args := m.Called(opts)
+	return args.Get(0).([]services.AttendanceSummary), args.Error(1)
+}
+
+func TestAttendanceController_RecordRollCall(t *testing.T) {
+	mockService := new(MockAttendanceService)
+	controller := controllers.NewAttendanceController(mockService)
+
+	date := time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC)
+	rollCall := services.RollCall{Date: date, Period: 2, Entries: []services.RollCallEntry{
+		{StudentID: 1, Status: models.AttendancePresent},
+		{StudentID: 2, Status: models.AttendanceLate, Note: "bus"},
+	}}
+	records := []models.AttendanceRecord{
+		{StudentID: 1, ClassID: 5, Date: date, Period: 2, Status: models.AttendancePresent},
+		{StudentID: 2, ClassID: 5, Date: date, Period: 2, Status: models.AttendanceLate, Note: "bus"},
+	}
+	mockService.On("RecordRollCall", uint(5), rollCall).Return(records, nil)
+
+	router := gin.Default()
+	router.POST("/classes/:id/attendance", controller.RecordRollCall)
+	payload := `{"date":"2024-06-03","period":2,"entries":[{"student_id":1,"status":"present"},{"student_id":2,"status":"late","note":"bus"}]}`
+	req, _ := http.NewRequest("POST", "/classes/5/attendance", bytes.NewBufferString(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var body []models.AttendanceRecord
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, records, body)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAttendanceController_RecordRollCall_InvalidDate(t *testing.T) {
+	controller := controllers.NewAttendanceController(new(MockAttendanceService))
+
+	router := gin.Default()
+	router.POST("/classes/:id/attendance", controller.RecordRollCall)
+	req, _ := http.NewRequest("POST", "/classes/5/attendance", bytes.NewBufferString(`{"date":"03/06/2024","entries":[]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+}
+
+func TestAttendanceController_GetStudentAttendanceSummary(t *testing.T) {
+	mockService := new(MockAttendanceService)
+	controller := controllers.NewAttendanceController(mockService)
+
+	dates := services.DateRange{From: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)}
+	summary := &services.AttendanceSummary{StudentID: 7, Total: 4, Present: 2, Late: 1, Absent: 1, AttendanceRate: 75, AbsenceRate: 25}
+	mockService.On("GetStudentAttendanceSummary", uint(7), dates).Return(summary, nil)
+
+	router := gin.Default()
+	router.GET("/students/:id/attendance/summary", controller.GetStudentAttendanceSummary)
+	req, _ := http.NewRequest("GET", "/students/7/attendance/summary?from=2024-06-01", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body services.AttendanceSummary
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, *summary, body)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAttendanceController_GetChronicAbsentees(t *testing.T) {
+	mockService := new(MockAttendanceService)
+	controller := controllers.NewAttendanceController(mockService)
+
+	absentees := []services.AttendanceSummary{{StudentID: 3, Total: 10, Absent: 3, AbsenceRate: 30, AttendanceRate: 70}}
+	mockService.On("GetChronicAbsentees", services.ChronicAbsenceOptions{SchoolID: 2, Threshold: 20}).Return(absentees, nil)
+
+	router := gin.Default()
+	router.GET("/attendance/chronic-absentees", controller.GetChronicAbsentees)
+	req, _ := http.NewRequest("GET", "/attendance/chronic-absentees?school_id=2&threshold=20", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []services.AttendanceSummary
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, absentees, body)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockTeacherService is a mock implementation of TeacherService for testing purposes.
+type MockTeacherService struct {
+	mock.Mock
+}
+
+func (m *MockTeacherService) GetAllTeachers() ([]models.Teacher, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Teacher), args.Error(1)
+}
+
+func (m *MockTeacherService) ListTeachers(offset, limit int) ([]models.Teacher, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.Teacher), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockTeacherService) GetTeacherByID(id uint) (*models.Teacher, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Teacher), args.Error(1)
+}
+
+func (m *MockTeacherService) CreateTeacher(teacher *models.Teacher) error {
+	args := m.Called(teacher)
+	return args.Error(0)
+}
+
+func (m *MockTeacherService) UpdateTeacher(teacher *models.Teacher) error {
+	args := m.Called(teacher)
+	return args.Error(0)
+}
+
+func (m *MockTeacherService) DeleteTeacher(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockTeacherService) GetTeacherClasses(teacherID uint) ([]models.TeacherAssignment, error) {
+	args := m.Called(teacherID)
+	return args.Get(0).([]models.TeacherAssignment), args.Error(1)
+}
+
+func (m *MockTeacherService) GetClassTeachers(classID uint) ([]models.TeacherAssignment, error) {
+	args := m.Called(classID)
+	return args.Get(0).([]models.TeacherAssignment), args.Error(1)
+}
+
+func (m *MockTeacherService) AssignTeacher(assignment *models.TeacherAssignment) error {
+	args := m.Called(assignment)
+	return args.Error(0)
+}
+
+func (m *MockTeacherService) UnassignTeacher(teacherID, assignmentID uint) error {
+	args := m.Called(teacherID, assignmentID)
+	return args.Error(0)
+}
+
+func TestTeacherController_CreateTeacher(t *testing.T) {
+	mockService := new(MockTeacherService)
+	controller := controllers.NewTeacherController(mockService)
+
+	teacher := models.Teacher{Name: "R. Iyer", Email: "iyer@example.com", Qualifications: models.StringList{"B.Ed", "M.Sc"}}
+	mockService.On("CreateTeacher", &teacher).Return(nil)
+
+	router := gin.Default()
+	router.POST("/teachers", controller.CreateTeacher)
+	payload, _ := json.Marshal(teacher)
+	req, _ := http.NewRequest("POST", "/teachers", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTeacherController_GetTeacherClasses(t *testing.T) {
+	mockService := new(MockTeacherService)
+	controller := controllers.NewTeacherController(mockService)
+
+	assignments := []models.TeacherAssignment{
+		{ID: 1, TeacherID: 2, ClassID: 3, Role: models.RoleHomeroom, Class: &models.Class{ClassName: "7A"}},
+		{ID: 2, TeacherID: 2, ClassID: 4, Role: models.RoleSubject, Subject: "Maths", Class: &models.Class{ClassName: "8B"}},
+	}
+	mockService.On("GetTeacherClasses", uint(2)).Return(assignments, nil)
+
+	router := gin.Default()
+	router.GET("/teachers/:id/classes", controller.GetTeacherClasses)
+	req, _ := http.NewRequest("GET", "/teachers/2/classes", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []models.TeacherAssignment
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 2, len(body))
+	assert.Equal(t, "8B", body[1].Class.ClassName)
+	assert.Equal(t, "Maths", body[1].Subject)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestTeacherController_CreateAssignment_Rejected(t *testing.T) {
+	mockService := new(MockTeacherService)
+	controller := controllers.NewTeacherController(mockService)
+
+	assignment := &models.TeacherAssignment{TeacherID: 2, ClassID: 3, Role: models.RoleHomeroom}
+	mockService.On("AssignTeacher", assignment).Return(&services.ValidationError{Message: "class 3 already has a homeroom teacher"})
+
+	router := gin.Default()
+	router.POST("/teachers/:id/assignments", controller.CreateAssignment)
+	req, _ := http.NewRequest("POST", "/teachers/2/assignments", bytes.NewBufferString(`{"class_id":3,"role":"homeroom"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockAcademicService is a mock implementation of AcademicService for testing purposes.
+type MockAcademicService struct {
+	mock.Mock
+}
+
+func (m *MockAcademicService) GetAcademicYears(schoolID uint) ([]models.AcademicYear, error) {
+	args := m.Called(schoolID)
+	return args.Get(0).([]models.AcademicYear), args.Error(1)
+}
+
+func (m *MockAcademicService) GetAcademicYearByID(id uint) (*models.AcademicYear, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.AcademicYear), args.Error(1)
+}
+
+func (m *MockAcademicService) CreateAcademicYear(year *models.AcademicYear) error {
+	args := m.Called(year)
+	return args.Error(0)
+}
+
+func (m *MockAcademicService) UpdateAcademicYear(year *models.AcademicYear) error {
+	args := m.Called(year)
+	return args.Error(0)
+}
+
+func (m *MockAcademicService) DeleteAcademicYear(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAcademicService) GetTerms(yearID uint) ([]models.Term, error) {
+	args := m.Called(yearID)
+	return args.Get(0).([]models.Term), args.Error(1)
+}
+
+func (m *MockAcademicService) GetTermByID(id uint) (*models.Term, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Term), args.Error(1)
+}
+
+func (m *MockAcademicService) GetActiveTerm(schoolID uint) (*models.Term, error) {
+	args := m.Called(schoolID)
+	return args.Get(0).(*models.Term), args.Error(1)
+}
+
+func (m *MockAcademicService) CreateTerm(term *models.Term) error {
+	args := m.Called(term)
+	return args.Error(0)
+}
+
+func (m *MockAcademicService) UpdateTerm(term *models.Term) error {
+	args := m.Called(term)
+	return args.Error(0)
+}
+
+func (m *MockAcademicService) DeleteTerm(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockAcademicService) ActivateTerm(id uint) (*models.Term, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Term), args.Error(1)
+}
+
+func (m *MockAcademicService) GetTermEnrollments(termID, classID uint) ([]models.Enrollment, error) {
+	args := m.Called(termID, classID)
+	return args.Get(0).([]models.Enrollment), args.Error(1)
+}
+
+func (m *MockAcademicService) GetStudentHistory(studentID uint) ([]models.Enrollment, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]models.Enrollment), args.Error(1)
+}
+
+func (m *MockAcademicService) RecordTermMarks(studentID, termID uint, marks int) (*models.Enrollment, error) {
+	args := m.Called(studentID, termID, marks)
+	return args.Get(0).(*models.Enrollment), args.Error(1)
+}
+
+func TestAcademicController_CreateTerm(t *testing.T) {
+	mockService := new(MockAcademicService)
+	controller := controllers.NewAcademicController(mockService)
+
+	term := &models.Term{
+		AcademicYearID: 4,
+		Name:           "Term 1",
+		StartDate:      time.Date(2024, 6, 3, 0, 0, 0, 0, time.UTC),
+		EndDate:        time.Date(2024, 9, 27, 0, 0, 0, 0, time.UTC),
+	}
+	mockService.On("CreateTerm", term).Return(nil)
+
+	router := gin.Default()
+	router.POST("/academic-years/:id/terms", controller.CreateTerm)
+	req, _ := http.NewRequest("POST", "/academic-years/4/terms", bytes.NewBufferString(`{"name":"Term 1","start_date":"2024-06-03","end_date":"2024-09-27"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAcademicController_CreateTerm_InvalidDate(t *testing.T) {
+	mockService := new(MockAcademicService)
+	controller := controllers.NewAcademicController(mockService)
+
+	router := gin.Default()
+	router.POST("/academic-years/:id/terms", controller.CreateTerm)
+	req, _ := http.NewRequest("POST", "/academic-years/4/terms", bytes.NewBufferString(`{"name":"Term 1","start_date":"03/06/2024","end_date":"2024-09-27"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	mockService.AssertNotCalled(t, "CreateTerm", mock.Anything)
+}
+
+func TestAcademicController_GetActiveTerm_None(t *testing.T) {
+	mockService := new(MockAcademicService)
+	controller := controllers.NewAcademicController(mockService)
+
+	mockService.On("GetActiveTerm", uint(2)).Return((*models.Term)(nil), gorm.ErrRecordNotFound)
+
+	router := gin.Default()
+	router.GET("/schools/:id/active-term", controller.GetActiveTerm)
+	req, _ := http.NewRequest("GET", "/schools/2/active-term", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAcademicController_GetStudentHistory(t *testing.T) {
+	mockService := new(MockAcademicService)
+	controller := controllers.NewAcademicController(mockService)
+
+	history := []models.Enrollment{
+		{ID: 1, StudentID: 5, TermID: 1, ClassID: 3, Marks: 61, Term: &models.Term{Name: "Term 1"}},
+		{ID: 9, StudentID: 5, TermID: 2, ClassID: 3, Marks: 74, Term: &models.Term{Name: "Term 2", Active: true}},
+	}
+	mockService.On("GetStudentHistory", uint(5)).Return(history, nil)
+
+	router := gin.Default()
+	router.GET("/students/:id/history", controller.GetStudentHistory)
+	req, _ := http.NewRequest("GET", "/students/5/history", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []models.Enrollment
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 2, len(body))
+	assert.Equal(t, 61, body[0].Marks)
+	assert.True(t, body[1].Term.Active)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAcademicController_RecordTermMarks(t *testing.T) {
+	mockService := new(MockAcademicService)
+	controller := controllers.NewAcademicController(mockService)
+
+	mockService.On("RecordTermMarks", uint(5), uint(1), 0).Return(&models.Enrollment{StudentID: 5, TermID: 1, Marks: 0}, nil)
+
+	router := gin.Default()
+	router.PUT("/students/:id/terms/:term_id", controller.RecordTermMarks)
+	req, _ := http.NewRequest("PUT", "/students/5/terms/1", bytes.NewBufferString(`{"marks":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockPromotionService is a mock implementation of PromotionService for testing purposes.
+type MockPromotionService struct {
+	mock.Mock
+}
+
+func (m *MockPromotionService) GetPromotionPlans(schoolID uint) ([]models.PromotionPlan, error) {
+	args := m.Called(schoolID)
+	return args.Get(0).([]models.PromotionPlan), args.Error(1)
+}
+
+func (m *MockPromotionService) GetPromotionPlan(id uint) (*models.PromotionPlan, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.PromotionPlan), args.Error(1)
+}
+
+func (m *MockPromotionService) DraftPromotionPlan(schoolID uint, rules services.PromotionRules) (*models.PromotionPlan, error) {
+	args := m.Called(schoolID, rules)
+	return args.Get(0).(*models.PromotionPlan), args.Error(1)
+}
+
+func (m *MockPromotionService) OverridePromotion(planID, studentID uint, outcome string, toClassID uint) (*models.PromotionEntry, error) {
+	args := m.Called(planID, studentID, outcome, toClassID)
+	return args.Get(0).(*models.PromotionEntry), args.Error(1)
+}
+
+func (m *MockPromotionService) ApplyPromotionPlan(id uint) (*models.PromotionPlan, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.PromotionPlan), args.Error(1)
+}
+
+func (m *MockPromotionService) DeletePromotionPlan(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func TestPromotionController_DraftPromotionPlan_DefaultPassMark(t *testing.T) {
+	mockService := new(MockPromotionService)
+	controller := controllers.NewPromotionController(mockService)
+
+	rules := services.PromotionRules{
+		AcademicYearID: 3,
+		PassMark:       services.DefaultPassMark,
+		Progression:    []services.ClassProgression{{ClassID: 1, NextClassID: 2}, {ClassID: 2}},
+	}
+	plan := &models.PromotionPlan{ID: 7, SchoolID: 1, AcademicYearID: 3, PassMark: 40, Status: models.PromotionDraft}
+	mockService.On("DraftPromotionPlan", uint(1), rules).Return(plan, nil)
+
+	router := gin.Default()
+	router.POST("/schools/:id/promotions", controller.DraftPromotionPlan)
+	req, _ := http.NewRequest("POST", "/schools/1/promotions", bytes.NewBufferString(`{"academic_year_id":3,"progression":[{"class_id":1,"next_class_id":2},{"class_id":2}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPromotionController_OverridePromotion(t *testing.T) {
+	mockService := new(MockPromotionService)
+	controller := controllers.NewPromotionController(mockService)
+
+	entry := &models.PromotionEntry{PlanID: 7, StudentID: 5, FromClassID: 1, ToClassID: 1, Outcome: models.OutcomeRepeated, Overridden: true}
+	mockService.On("OverridePromotion", uint(7), uint(5), models.OutcomeRepeated, uint(0)).Return(entry, nil)
+
+	router := gin.Default()
+	router.PUT("/promotions/:id/entries/:student_id", controller.OverridePromotion)
+	req, _ := http.NewRequest("PUT", "/promotions/7/entries/5", bytes.NewBufferString(`{"outcome":"repeated"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body models.PromotionEntry
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.True(t, body.Overridden)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestPromotionController_ApplyPromotionPlan_Conflict(t *testing.T) {
+	mockService := new(MockPromotionService)
+	controller := controllers.NewPromotionController(mockService)
+
+	mockService.On("ApplyPromotionPlan", uint(7)).Return((*models.PromotionPlan)(nil), repository.ErrConflict)
+
+	router := gin.Default()
+	router.POST("/promotions/:id/apply", controller.ApplyPromotionPlan)
+	req, _ := http.NewRequest("POST", "/promotions/7/apply", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockGuardianService is a mock implementation of GuardianService for testing purposes.
+type MockGuardianService struct {
+	mock.Mock
+}
+
+func (m *MockGuardianService) GetAllGuardians() ([]models.Guardian, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Guardian), args.Error(1)
+}
+
+func (m *MockGuardianService) ListGuardians(offset, limit int) ([]models.Guardian, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.Guardian), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockGuardianService) GetGuardianByID(id uint) (*models.Guardian, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Guardian), args.Error(1)
+}
+
+func (m *MockGuardianService) CreateGuardian(guardian *models.Guardian) error {
+	args := m.Called(guardian)
+	return args.Error(0)
+}
+
+func (m *MockGuardianService) UpdateGuardian(guardian *models.Guardian) error {
+	args := m.Called(guardian)
+	return args.Error(0)
+}
+
+func (m *MockGuardianService) DeleteGuardian(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockGuardianService) GetGuardianStudents(guardianID uint) ([]models.StudentGuardian, error) {
+	args := m.Called(guardianID)
+	return args.Get(0).([]models.StudentGuardian), args.Error(1)
+}
+
+func (m *MockGuardianService) GetStudentContacts(studentID uint) ([]models.StudentGuardian, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]models.StudentGuardian), args.Error(1)
+}
+
+func (m *MockGuardianService) LinkGuardian(link *models.StudentGuardian) error {
+	args := m.Called(link)
+	return args.Error(0)
+}
+
+func (m *MockGuardianService) UpdateLink(link *models.StudentGuardian) error {
+	args := m.Called(link)
+	return args.Error(0)
+}
+
+func (m *MockGuardianService) UnlinkGuardian(studentID, guardianID uint) error {
+	args := m.Called(studentID, guardianID)
+	return args.Error(0)
+}
+
+func TestGuardianController_CreateGuardian(t *testing.T) {
+	mockService := new(MockGuardianService)
+	controller := controllers.NewGuardianController(mockService)
+
+	guardian := models.Guardian{
+		Name:    "M. Okafor",
+		Phones:  models.StringList{"+234 801 000 0000"},
+		Address: models.Address{Street: "4 Marina", City: "Lagos"},
+	}
+	mockService.On("CreateGuardian", &guardian).Return(nil)
+
+	router := gin.Default()
+	router.POST("/guardians", controller.CreateGuardian)
+	payload, _ := json.Marshal(guardian)
+	req, _ := http.NewRequest("POST", "/guardians", bytes.NewBuffer(payload))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGuardianController_GetStudentContacts(t *testing.T) {
+	mockService := new(MockGuardianService)
+	controller := controllers.NewGuardianController(mockService)
+
+	contacts := []models.StudentGuardian{
+		{StudentID: 5, GuardianID: 2, Relationship: "mother", Priority: 1, HasCustody: true, CanPickup: true, Guardian: &models.Guardian{Name: "M. Okafor"}},
+		{StudentID: 5, GuardianID: 9, Relationship: "uncle", Priority: 2, CanPickup: true, Guardian: &models.Guardian{Name: "T. Okafor"}},
+	}
+	mockService.On("GetStudentContacts", uint(5)).Return(contacts, nil)
+
+	router := gin.Default()
+	router.GET("/students/:id/contacts", controller.GetStudentContacts)
+	req, _ := http.NewRequest("GET", "/students/5/contacts", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []models.StudentGuardian
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 2, len(body))
+	assert.Equal(t, "M. Okafor", body[0].Guardian.Name)
+	assert.Equal(t, "uncle", body[1].Relationship)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestGuardianController_UnlinkGuardian_NotLinked(t *testing.T) {
+	mockService := new(MockGuardianService)
+	controller := controllers.NewGuardianController(mockService)
+
+	mockService.On("UnlinkGuardian", uint(5), uint(3)).Return(gorm.ErrRecordNotFound)
+
+	router := gin.Default()
+	router.DELETE("/students/:id/guardians/:guardian_id", controller.UnlinkGuardian)
+	req, _ := http.NewRequest("DELETE", "/students/5/guardians/3", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockFeeService is a mock implementation of FeeService for testing purposes.
+type MockFeeService struct {
+	mock.Mock
+}
+
+func (m *MockFeeService) GetFeeItems(schoolID uint) ([]models.FeeItem, error) {
+	args := m.Called(schoolID)
+	return args.Get(0).([]models.FeeItem), args.Error(1)
+}
+
+func (m *MockFeeService) GetFeeItemByID(id uint) (*models.FeeItem, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.FeeItem), args.Error(1)
+}
+
+func (m *MockFeeService) CreateFeeItem(item *models.FeeItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) UpdateFeeItem(item *models.FeeItem) error {
+	args := m.Called(item)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) DeleteFeeItem(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) GetStudentDiscounts(studentID uint) ([]models.Discount, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]models.Discount), args.Error(1)
+}
+
+func (m *MockFeeService) CreateDiscount(discount *models.Discount) error {
+	args := m.Called(discount)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) DeleteDiscount(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) GenerateInvoices(termID uint, opts services.InvoiceOptions) ([]models.Invoice, error) {
+	args := m.Called(termID, opts)
+	return args.Get(0).([]models.Invoice), args.Error(1)
+}
+
+func (m *MockFeeService) GetInvoices(filter repository.InvoiceFilter) ([]models.Invoice, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]models.Invoice), args.Error(1)
+}
+
+func (m *MockFeeService) GetInvoiceByID(id uint) (*models.Invoice, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Invoice), args.Error(1)
+}
+
+func (m *MockFeeService) GetInvoiceDocument(id uint) (*fees.Document, error) {
+	args := m.Called(id)
+	return args.Get(0).(*fees.Document), args.Error(1)
+}
+
+func (m *MockFeeService) RecordPayment(payment *models.Payment) error {
+	args := m.Called(payment)
+	return args.Error(0)
+}
+
+func (m *MockFeeService) GetStudentLedger(studentID uint) ([]models.LedgerEntry, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]models.LedgerEntry), args.Error(1)
+}
+
+func (m *MockFeeService) GetOutstandingBalances(filter repository.InvoiceFilter) ([]repository.Balance, error) {
+	args := m.Called(filter)
+	return args.Get(0).([]repository.Balance), args.Error(1)
+}
+
+func TestFeeController_GenerateInvoices(t *testing.T) {
+	mockService := new(MockFeeService)
+	controller := controllers.NewFeeController(mockService)
+
+	opts := services.InvoiceOptions{ClassID: 3, DueDate: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC)}
+	invoices := []models.Invoice{{Number: "INV-2-5", StudentID: 5, TermID: 2, Total: 48500, Balance: 48500}}
+	mockService.On("GenerateInvoices", uint(2), opts).Return(invoices, nil)
+
+	router := gin.Default()
+	router.POST("/terms/:id/invoices", controller.GenerateInvoices)
+	req, _ := http.NewRequest("POST", "/terms/2/invoices", bytes.NewBufferString(`{"class_id":3,"due_date":"2024-07-01"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestFeeController_PrintInvoice(t *testing.T) {
+	mockService := new(MockFeeService)
+	controller := controllers.NewFeeController(mockService)
+
+	doc := &fees.Document{
+		Invoice: models.Invoice{Number: "INV-2-5", Total: 48500, Balance: 48500},
+		Student: models.Student{Name: "Ada"},
+		School:  models.School{Name: "Hillside"},
+	}
+	mockService.On("GetInvoiceDocument", uint(4)).Return(doc, nil)
+
+	router := gin.Default()
+	router.GET("/invoices/:id/print", controller.PrintInvoice)
+	req, _ := http.NewRequest("GET", "/invoices/4/print", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "text/html; charset=utf-8", resp.Header().Get("Content-Type"))
+	assert.Contains(t, resp.Body.String(), "Invoice INV-2-5")
+	assert.Contains(t, resp.Body.String(), "485.00")
+
+	mockService.AssertExpectations(t)
+}
+
+func TestFeeController_RecordPayment_Overpayment(t *testing.T) {
+	mockService := new(MockFeeService)
+	controller := controllers.NewFeeController(mockService)
+
+	payment := &models.Payment{InvoiceID: 4, Amount: 90000, Method: "cash"}
+	mockService.On("RecordPayment", payment).Return(&services.ValidationError{Message: "payment of 900.00 exceeds the balance of invoice 4"})
+
+	router := gin.Default()
+	router.POST("/invoices/:id/payments", controller.RecordPayment)
+	req, _ := http.NewRequest("POST", "/invoices/4/payments", bytes.NewBufferString(`{"amount":90000,"method":"cash"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestFeeController_GetOutstandingBalances(t *testing.T) {
+	mockService := new(MockFeeService)
+	controller := controllers.NewFeeController(mockService)
+
+	balances := []repository.Balance{{StudentID: 5, Invoiced: 48500, Paid: 20000, Balance: 28500}}
+	mockService.On("GetOutstandingBalances", repository.InvoiceFilter{SchoolID: 1, TermID: 2}).Return(balances, nil)
+
+	router := gin.Default()
+	router.GET("/fees/outstanding", controller.GetOutstandingBalances)
+	req, _ := http.NewRequest("GET", "/fees/outstanding?school_id=1&term_id=2", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []repository.Balance
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, int64(28500), body[0].Balance)
+
+	req, _ = http.NewRequest("GET", "/fees/outstanding?class_id=x", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+// MockExamService is a mock implementation of ExamService for testing purposes.
+type MockExamService struct {
+	mock.Mock
+}
+
+func (m *MockExamService) GetClassExams(classID uint) ([]models.Exam, error) {
+	args := m.Called(classID)
+	return args.Get(0).([]models.Exam), args.Error(1)
+}
+
+func (m *MockExamService) GetExamByID(id uint) (*models.Exam, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Exam), args.Error(1)
+}
+
+func (m *MockExamService) CreateExam(exam *models.Exam) error {
+	args := m.Called(exam)
+	return args.Error(0)
+}
+
+func (m *MockExamService) UpdateExam(exam *models.Exam) error {
+	args := m.Called(exam)
+	return args.Error(0)
+}
+
+func (m *MockExamService) DeleteExam(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockExamService) GetExamResults(examID uint) ([]models.ExamResult, error) {
+	args := m.Called(examID)
+	return args.Get(0).([]models.ExamResult), args.Error(1)
+}
+
+func (m *MockExamService) RecordResults(examID uint, entries []services.ResultEntry) ([]models.ExamResult, error) {
+	args := m.Called(examID, entries)
+	return args.Get(0).([]models.ExamResult), args.Error(1)
+}
+
+func (m *MockExamService) GetStudentScores(studentID uint) (*assessment.Breakdown, error) {
+	args := m.Called(studentID)
+	return args.Get(0).(*assessment.Breakdown), args.Error(1)
+}
+
+func TestExamController_CreateExam(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	exam := &models.Exam{
+		ClassID:  3,
+		Subject:  "Maths",
+		Name:     "Midterm",
+		Date:     time.Date(2024, 8, 12, 0, 0, 0, 0, time.UTC),
+		MaxMarks: 50,
+		Weight:   30,
+	}
+	mockService.On("CreateExam", exam).Return(nil)
+
+	router := gin.Default()
+	router.POST("/classes/:id/exams", controller.CreateExam)
+	req, _ := http.NewRequest("POST", "/classes/3/exams", bytes.NewBufferString(`{"subject":"Maths","name":"Midterm","date":"2024-08-12","max_marks":50,"weight":30}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExamController_RecordResults_AboveMaximum(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	entries := []services.ResultEntry{{StudentID: 5, Marks: 55}}
+	mockService.On("RecordResults", uint(4), entries).
+		Return([]models.ExamResult(nil), &services.ValidationError{Message: "marks of student 5 must be between 0 and 50"})
+
+	router := gin.Default()
+	router.PUT("/exams/:id/results", controller.RecordResults)
+	req, _ := http.NewRequest("PUT", "/exams/4/results", bytes.NewBufferString(`{"results":[{"student_id":5,"marks":55}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExamController_GetStudentScores(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	breakdown := &assessment.Breakdown{
+		Final:  79,
+		Graded: true,
+		Subjects: []assessment.SubjectScore{
+			{Subject: "Maths", Score: 79, Graded: true, Components: []assessment.Component{{ExamID: 4, Name: "Midterm", MaxMarks: 50, Weight: 30, Graded: true}}},
+		},
+	}
+	mockService.On("GetStudentScores", uint(5)).Return(breakdown, nil)
+
+	router := gin.Default()
+	router.GET("/students/:id/scores", controller.GetStudentScores)
+	req, _ := http.NewRequest("GET", "/students/5/scores", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body assessment.Breakdown
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, 79.0, body.Final)
+	assert.Equal(t, "Maths", body.Subjects[0].Subject)
+
+	mockService.AssertExpectations(t)
+}
+
+func (m *MockExamService) TransitionMarks(examID uint, action, actor, reason string) (*models.Exam, error) {
+	args := m.Called(examID, action, actor, reason)
+	return args.Get(0).(*models.Exam), args.Error(1)
+}
+
+func (m *MockExamService) GetMarksHistory(examID uint) ([]models.ExamStatusChange, error) {
+	args := m.Called(examID)
+	return args.Get(0).([]models.ExamStatusChange), args.Error(1)
+}
+
+func TestExamController_PublishMarks(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	exam := &models.Exam{ClassID: 3, Subject: "Maths", Status: models.MarksPublished}
+	mockService.On("TransitionMarks", uint(4), services.MarksPublish, "hod.maths", "").Return(exam, nil)
+
+	router := gin.Default()
+	router.POST("/exams/:id/publish", controller.PublishMarks)
+	req, _ := http.NewRequest("POST", "/exams/4/publish", bytes.NewBufferString(`{"actor":"hod.maths"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body models.Exam
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, models.MarksPublished, body.Status)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExamController_ReopenMarks_WithoutReason(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	mockService.On("TransitionMarks", uint(4), services.MarksReopen, "hod.maths", "").
+		Return((*models.Exam)(nil), &services.ValidationError{Message: "a reason is required to reopen marks"})
+
+	router := gin.Default()
+	router.POST("/exams/:id/reopen", controller.ReopenMarks)
+	req, _ := http.NewRequest("POST", "/exams/4/reopen", bytes.NewBufferString(`{"actor":"hod.maths"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExamController_SubmitMarks_Concurrent(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	mockService.On("TransitionMarks", uint(4), services.MarksSubmit, "r.iyer", "").Return((*models.Exam)(nil), repository.ErrConflict)
+
+	router := gin.Default()
+	router.POST("/exams/:id/submit", controller.SubmitMarks)
+	req, _ := http.NewRequest("POST", "/exams/4/submit", bytes.NewBufferString(`{"actor":"r.iyer"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func (m *MockExamService) GetMarksGrid(classID uint, assessment string) (*services.MarksGrid, error) {
+	args := m.Called(classID, assessment)
+	return args.Get(0).(*services.MarksGrid), args.Error(1)
+}
+
+func (m *MockExamService) SaveMarksGrid(classID uint, assessment string, cells []services.GridCell) (*services.MarksGrid, error) {
+	args := m.Called(classID, assessment, cells)
+	return args.Get(0).(*services.MarksGrid), args.Error(1)
+}
+
+func TestExamController_GetMarksGrid(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	marks := 64
+	grid := &services.MarksGrid{ClassID: 3, Assessment: "4", MaxMarks: 80, Editable: true, Rows: []services.MarksGridRow{
+		{StudentID: 1, Name: "Meera", Marks: &marks},
+		{StudentID: 2, Name: "Arjun"},
+	}}
+	mockService.On("GetMarksGrid", uint(3), "4").Return(grid, nil)
+
+	router := gin.Default()
+	router.GET("/classes/:id/marks", controller.GetMarksGrid)
+	req, _ := http.NewRequest("GET", "/classes/3/marks?assessment=4", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body services.MarksGrid
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body.Rows, 2)
+	assert.Equal(t, 64, *body.Rows[0].Marks)
+	assert.Nil(t, body.Rows[1].Marks)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExamController_SaveMarksGrid_InvalidCells(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	marks := 120
+	cells := []services.GridCell{{StudentID: 1, Marks: &marks}}
+	mockService.On("SaveMarksGrid", uint(3), "", cells).Return((*services.MarksGrid)(nil), &services.MarksGridError{
+		Cells: []services.CellError{{StudentID: 1, Error: "marks must be between 0 and 100"}},
+	})
+
+	router := gin.Default()
+	router.PUT("/classes/:id/marks", controller.SaveMarksGrid)
+	req, _ := http.NewRequest("PUT", "/classes/3/marks", bytes.NewBufferString(`{"rows":[{"student_id":1,"marks":120}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+	var body struct {
+		Cells []services.CellError `json:"cells"`
+	}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, []services.CellError{{StudentID: 1, Error: "marks must be between 0 and 100"}}, body.Cells)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestExamController_SaveMarksGrid_StaleCells(t *testing.T) {
+	mockService := new(MockExamService)
+	controller := controllers.NewExamController(mockService)
+
+	marks := 71
+	version := time.Date(2026, 3, 2, 9, 30, 0, 0, time.UTC)
+	cells := []services.GridCell{{StudentID: 2, Marks: &marks, Version: &version}}
+	mockService.On("SaveMarksGrid", uint(3), "marks", cells).Return((*services.MarksGrid)(nil), &services.MarksGridError{
+		Conflict: true,
+		Cells:    []services.CellError{{StudentID: 2, Error: "marks were changed since they were read"}},
+	})
+
+	router := gin.Default()
+	router.PUT("/classes/:id/marks", controller.SaveMarksGrid)
+	req, _ := http.NewRequest("PUT", "/classes/3/marks?assessment=marks",
+		bytes.NewBufferString(`{"rows":[{"student_id":2,"marks":71,"version":"2026-03-02T09:30:00Z"}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+type MockAppealService struct {
+	mock.Mock
+}
+
+func (m *MockAppealService) GetAppealByID(id uint) (*models.Appeal, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Appeal), args.Error(1)
+}
+
+func (m *MockAppealService) GetSchoolAppeals(schoolID uint, overdueOnly bool) ([]models.Appeal, error) {
+	args := m.Called(schoolID, overdueOnly)
+	return args.Get(0).([]models.Appeal), args.Error(1)
+}
+
+func (m *MockAppealService) GetStudentAppeals(studentID uint) ([]models.Appeal, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]models.Appeal), args.Error(1)
+}
+
+func (m *MockAppealService) CreateAppeal(appeal *models.Appeal) error {
+	args := m.Called(appeal)
+	return args.Error(0)
+}
+
+func (m *MockAppealService) AssignAppeal(id uint, reviewer string) (*models.Appeal, error) {
+	args := m.Called(id, reviewer)
+	return args.Get(0).(*models.Appeal), args.Error(1)
+}
+
+func (m *MockAppealService) ResolveAppeal(id uint, resolution services.AppealResolution) (*models.Appeal, error) {
+	args := m.Called(id, resolution)
+	return args.Get(0).(*models.Appeal), args.Error(1)
+}
+
+func (m *MockAppealService) GetMarkChanges(studentID uint) ([]models.MarkChange, error) {
+	args := m.Called(studentID)
+	return args.Get(0).([]models.MarkChange), args.Error(1)
+}
+
+func TestAppealController_CreateAppeal(t *testing.T) {
+	mockService := new(MockAppealService)
+	controller := controllers.NewAppealController(mockService)
+
+	guardianID := uint(9)
+	mockService.On("CreateAppeal", &models.Appeal{StudentID: 5, ExamID: 4, GuardianID: &guardianID, Reason: "Question 3 was not marked"}).
+		Run(func(args mock.Arguments) {
+			appeal := args.Get(0).(*models.Appeal)
+			appeal.Status = models.AppealOpen
+			appeal.OriginalMarks = 52
+		}).Return(nil)
+
+	router := gin.Default()
+	router.POST("/students/:id/appeals", controller.CreateAppeal)
+	req, _ := http.NewRequest("POST", "/students/5/appeals",
+		bytes.NewBufferString(`{"exam_id":4,"guardian_id":9,"reason":"Question 3 was not marked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var body models.Appeal
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, models.AppealOpen, body.Status)
+	assert.Equal(t, 52, body.OriginalMarks)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAppealController_GetSchoolAppeals_Overdue(t *testing.T) {
+	mockService := new(MockAppealService)
+	controller := controllers.NewAppealController(mockService)
+
+	appeals := []models.Appeal{{SchoolID: 2, ExamID: 4, StudentID: 5, Status: models.AppealInReview, Overdue: true}}
+	mockService.On("GetSchoolAppeals", uint(2), true).Return(appeals, nil)
+
+	router := gin.Default()
+	router.GET("/schools/:id/appeals", controller.GetSchoolAppeals)
+	req, _ := http.NewRequest("GET", "/schools/2/appeals?overdue=true", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var body []models.Appeal
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Len(t, body, 1)
+	assert.True(t, body[0].Overdue)
+
+	req, _ = http.NewRequest("GET", "/schools/2/appeals?overdue=soon", nil)
+	resp = httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusBadRequest, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestAppealController_ResolveAppeal_Conflict(t *testing.T) {
+	mockService := new(MockAppealService)
+	controller := controllers.NewAppealController(mockService)
+
+	revised := 61
+	resolution := services.AppealResolution{Outcome: models.AppealUpheld, RevisedMarks: &revised, Note: "Question 3 remarked"}
+	mockService.On("ResolveAppeal", uint(7), resolution).Return((*models.Appeal)(nil), repository.ErrConflict)
+
+	router := gin.Default()
+	router.POST("/appeals/:id/resolve", controller.ResolveAppeal)
+	req, _ := http.NewRequest("POST", "/appeals/7/resolve",
+		bytes.NewBufferString(`{"outcome":"upheld","revised_marks":61,"note":"Question 3 remarked"}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestStudentController_CreateStudent_ClassFull(t *testing.T) {
+	mockService := new(MockStudentService)
+	controller := controllers.NewStudentController(mockService)
+
+	mockService.On("CreateStudent", &models.Student{Name: "Kavya", ClassID: 3}).Return(repository.ErrClassFull)
+
+	router := gin.Default()
+	router.POST("/students", controller.CreateStudent)
+	req, _ := http.NewRequest("POST", "/students", bytes.NewBufferString(`{"name":"Kavya","class_id":3}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusConflict, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
+
+type MockWaitlistService struct {
+	mock.Mock
+}
+
+func (m *MockWaitlistService) GetWaitlist(classID uint) ([]models.WaitlistEntry, error) {
+	args := m.Called(classID)
+	return args.Get(0).([]models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistService) AddToWaitlist(classID, studentID uint) (*models.WaitlistEntry, error) {
+	args := m.Called(classID, studentID)
+	return args.Get(0).(*models.WaitlistEntry), args.Error(1)
+}
+
+func (m *MockWaitlistService) RemoveFromWaitlist(classID, studentID uint) error {
+	args := m.Called(classID, studentID)
+	return args.Error(0)
+}
+
+func TestWaitlistController_AddToWaitlist(t *testing.T) {
+	mockService := new(MockWaitlistService)
+	controller := controllers.NewWaitlistController(mockService)
+
+	mockService.On("AddToWaitlist", uint(3), uint(8)).Return(&models.WaitlistEntry{ID: 1, ClassID: 3, StudentID: 8}, nil)
+
+	router := gin.Default()
+	router.POST("/classes/:id/waitlist", controller.AddToWaitlist)
+	req, _ := http.NewRequest("POST", "/classes/3/waitlist", bytes.NewBufferString(`{"student_id":8}`))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	var body models.WaitlistEntry
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&body))
+	assert.Equal(t, uint(8), body.StudentID)
+
+	mockService.AssertExpectations(t)
+}
+
+func TestWaitlistController_RemoveFromWaitlist_NotWaiting(t *testing.T) {
+	mockService := new(MockWaitlistService)
+	controller := controllers.NewWaitlistController(mockService)
+
+	mockService.On("RemoveFromWaitlist", uint(3), uint(8)).Return(gorm.ErrRecordNotFound)
+
+	router := gin.Default()
+	router.DELETE("/classes/:id/waitlist/:student_id", controller.RemoveFromWaitlist)
+	req, _ := http.NewRequest("DELETE", "/classes/3/waitlist/8", nil)
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusNotFound, resp.Code)
+
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,140 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"stu/events"
+	"stu/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultHeartbeat = 15 * time.Second
+	replayBatchSize  = 500
+	reconnectDelayMs = 3000
+)
+
+// EventController streams student events to clients as Server-Sent
+// Events. SSE IDs are outbox positions, which follow the order events
+// commit in, so a client that reconnects with Last-Event-ID is sent what
+// it missed before the live stream resumes.
+type EventController struct {
+	service   services.EventStreamService
+	heartbeat time.Duration
+}
+
+// NewEventController returns a controller that sends a heartbeat comment
+// after every heartbeat interval; zero uses 15 seconds.
+func NewEventController(service services.EventStreamService, heartbeat time.Duration) *EventController {
+	if heartbeat <= 0 {
+		heartbeat = defaultHeartbeat
+	}
+	return &EventController{
+		service:   service,
+		heartbeat: heartbeat,
+	}
+}
+
+func (ec *EventController) StreamStudentEvents(c *gin.Context) {
+	var filter services.EventFilter
+	for param, target := range map[string]*uint{"school_id": &filter.SchoolID, "class_id": &filter.ClassID} {
+		if value, ok := c.GetQuery(param); ok {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + param})
+				return
+			}
+			*target = uint(id)
+		}
+	}
+	var lastPosition uint64
+	resume := c.GetHeader("Last-Event-ID")
+	if resume != "" {
+		var err error
+		if lastPosition, err = strconv.ParseUint(resume, 10, 64); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid Last-Event-ID"})
+			return
+		}
+	}
+
+	// Subscribe before replaying so nothing published in between is
+	// lost. The relay publishes events in position order once they are
+	// positioned, and a position is only visible after every lower one, so
+	// live events at or below the last replayed position were replayed.
+	stream, cancel := ec.service.SubscribeStudentEvents(filter)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	fmt.Fprintf(c.Writer, "retry: %d\n\n", reconnectDelayMs)
+
+	if resume != "" {
+		for {
+			batch, err := ec.service.StudentEventsSince(lastPosition, filter, replayBatchSize)
+			if err != nil {
+				// The status is already sent; ending the stream makes
+				// the client reconnect and retry the replay.
+				log.Printf("Replaying events after %d failed: %v", lastPosition, err)
+				return
+			}
+			for _, event := range batch {
+				if !writeEvent(c, event) {
+					return
+				}
+				lastPosition = event.Position
+			}
+			if len(batch) < replayBatchSize {
+				break
+			}
+		}
+	}
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(ec.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-stream:
+			if !ok {
+				// The subscriber fell behind; the client resumes from
+				// its Last-Event-ID on reconnect.
+				return
+			}
+			if event.Position <= lastPosition {
+				continue
+			}
+			if !writeEvent(c, event) {
+				return
+			}
+			lastPosition = event.Position
+			c.Writer.Flush()
+		case <-ticker.C:
+			if _, err := fmt.Fprint(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		}
+	}
+}
+
+// writeEvent writes event as an SSE message and reports whether the
+// client is still connected.
+func writeEvent(c *gin.Context, event events.Event) bool {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("Encoding event %d failed: %v", event.ID, err)
+		return true
+	}
+	_, err = fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.Position, event.Type, data)
+	return err == nil
+}
@@ -0,0 +1,275 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"stu/models"
+	"stu/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExamController struct {
+	service services.ExamService
+}
+
+func NewExamController(service services.ExamService) *ExamController {
+	return &ExamController{
+		service: service,
+	}
+}
+
+// examInput is the body of exam requests; the date is optional.
+type examInput struct {
+	TermID   uint   `json:"term_id"`
+	Subject  string `json:"subject"`
+	Name     string `json:"name"`
+	Date     string `json:"date"`
+	MaxMarks int    `json:"max_marks"`
+	Weight   int    `json:"weight"`
+}
+
+func (e examInput) exam() (models.Exam, error) {
+	exam := models.Exam{TermID: e.TermID, Subject: e.Subject, Name: e.Name, MaxMarks: e.MaxMarks, Weight: e.Weight}
+	if e.Date != "" {
+		date, err := time.Parse(dateLayout, e.Date)
+		if err != nil {
+			return exam, errors.New("date must look like 2006-01-02")
+		}
+		exam.Date = date
+	}
+	return exam, nil
+}
+
+func (ec *ExamController) GetClassExams(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	exams, err := ec.service.GetClassExams(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, exams)
+}
+
+func (ec *ExamController) GetExamByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	exam, err := ec.service.GetExamByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Exam not found"})
+		return
+	}
+	c.JSON(http.StatusOK, exam)
+}
+
+func (ec *ExamController) CreateExam(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	var input examInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	exam, err := input.exam()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	exam.ClassID = uint(id)
+	err = ec.service.CreateExam(&exam)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, exam)
+}
+
+func (ec *ExamController) UpdateExam(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	var input examInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	exam, err := input.exam()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	exam.ID = uint(id)
+	err = ec.service.UpdateExam(&exam)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, exam)
+}
+
+func (ec *ExamController) DeleteExam(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	err = ec.service.DeleteExam(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Exam deleted successfully"})
+}
+
+func (ec *ExamController) GetExamResults(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	results, err := ec.service.GetExamResults(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (ec *ExamController) RecordResults(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	var input struct {
+		Results []services.ResultEntry `json:"results"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	results, err := ec.service.RecordResults(uint(id), input.Results)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+func (ec *ExamController) GetStudentScores(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	breakdown, err := ec.service.GetStudentScores(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, breakdown)
+}
+
+func (ec *ExamController) SubmitMarks(c *gin.Context)  { ec.transitionMarks(c, services.MarksSubmit) }
+func (ec *ExamController) ApproveMarks(c *gin.Context) { ec.transitionMarks(c, services.MarksApprove) }
+func (ec *ExamController) ReturnMarks(c *gin.Context)  { ec.transitionMarks(c, services.MarksReturn) }
+func (ec *ExamController) PublishMarks(c *gin.Context) { ec.transitionMarks(c, services.MarksPublish) }
+func (ec *ExamController) ReopenMarks(c *gin.Context)  { ec.transitionMarks(c, services.MarksReopen) }
+
+// transitionMarks applies a workflow action to the exam's marks. The body
+// names who acts and, for returns and reopening, why.
+func (ec *ExamController) transitionMarks(c *gin.Context, action string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	var input struct {
+		Actor  string `json:"actor"`
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	exam, err := ec.service.TransitionMarks(uint(id), action, input.Actor, input.Reason)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, exam)
+}
+
+func (ec *ExamController) GetMarksHistory(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid exam ID"})
+		return
+	}
+	changes, err := ec.service.GetMarksHistory(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
+
+func (ec *ExamController) GetMarksGrid(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	grid, err := ec.service.GetMarksGrid(uint(id), c.Query("assessment"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, grid)
+}
+
+// SaveMarksGrid answers invalid cells with 400 and cells changed by
+// someone else with 409, listing them under "cells".
+func (ec *ExamController) SaveMarksGrid(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	var input struct {
+		Rows []services.GridCell `json:"rows"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	grid, err := ec.service.SaveMarksGrid(uint(id), c.Query("assessment"), input.Rows)
+	var gridErr *services.MarksGridError
+	if errors.As(err, &gridErr) {
+		status := http.StatusBadRequest
+		if gridErr.Conflict {
+			status = http.StatusConflict
+		}
+		c.JSON(status, gin.H{"error": gridErr.Error(), "cells": gridErr.Cells})
+		return
+	}
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, grid)
+}
@@ -0,0 +1,296 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"strconv"
+	"stu/fees"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FeeController struct {
+	service services.FeeService
+}
+
+func NewFeeController(service services.FeeService) *FeeController {
+	return &FeeController{
+		service: service,
+	}
+}
+
+func (fc *FeeController) GetFeeItems(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	items, err := fc.service.GetFeeItems(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, items)
+}
+
+func (fc *FeeController) GetFeeItemByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fee item ID"})
+		return
+	}
+	item, err := fc.service.GetFeeItemByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fee item not found"})
+		return
+	}
+	c.JSON(http.StatusOK, item)
+}
+
+func (fc *FeeController) CreateFeeItem(c *gin.Context) {
+	var newItem models.FeeItem
+	if err := c.ShouldBindJSON(&newItem); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	err := fc.service.CreateFeeItem(&newItem)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, newItem)
+}
+
+func (fc *FeeController) UpdateFeeItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fee item ID"})
+		return
+	}
+	var updatedItem models.FeeItem
+	if err := c.ShouldBindJSON(&updatedItem); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	updatedItem.ID = uint(id)
+	err = fc.service.UpdateFeeItem(&updatedItem)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updatedItem)
+}
+
+func (fc *FeeController) DeleteFeeItem(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fee item ID"})
+		return
+	}
+	err = fc.service.DeleteFeeItem(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Fee item deleted successfully"})
+}
+
+func (fc *FeeController) GetStudentDiscounts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	discounts, err := fc.service.GetStudentDiscounts(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, discounts)
+}
+
+func (fc *FeeController) CreateDiscount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	var discount models.Discount
+	if err := c.ShouldBindJSON(&discount); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	discount.StudentID = uint(id)
+	err = fc.service.CreateDiscount(&discount)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, discount)
+}
+
+func (fc *FeeController) DeleteDiscount(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid discount ID"})
+		return
+	}
+	err = fc.service.DeleteDiscount(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Discount deleted successfully"})
+}
+
+func (fc *FeeController) GenerateInvoices(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	var input struct {
+		ClassID uint   `json:"class_id"`
+		DueDate string `json:"due_date"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	opts := services.InvoiceOptions{ClassID: input.ClassID}
+	if input.DueDate != "" {
+		if opts.DueDate, err = time.Parse(dateLayout, input.DueDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "due_date must look like 2006-01-02"})
+			return
+		}
+	}
+	invoices, err := fc.service.GenerateInvoices(uint(id), opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, invoices)
+}
+
+func (fc *FeeController) GetInvoices(c *gin.Context) {
+	filter, err := parseInvoiceFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	invoices, err := fc.service.GetInvoices(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, invoices)
+}
+
+func (fc *FeeController) GetInvoiceByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+	invoice, err := fc.service.GetInvoiceByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invoice not found"})
+		return
+	}
+	c.JSON(http.StatusOK, invoice)
+}
+
+// PrintInvoice serves the invoice as an HTML page for printing.
+func (fc *FeeController) PrintInvoice(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+	doc, err := fc.service.GetInvoiceDocument(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	var page bytes.Buffer
+	if err := fees.WriteHTML(&page, *doc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
+
+func (fc *FeeController) RecordPayment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid invoice ID"})
+		return
+	}
+	var payment models.Payment
+	if err := c.ShouldBindJSON(&payment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	payment.InvoiceID = uint(id)
+	err = fc.service.RecordPayment(&payment)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, payment)
+}
+
+func (fc *FeeController) GetStudentLedger(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	entries, err := fc.service.GetStudentLedger(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+func (fc *FeeController) GetOutstandingBalances(c *gin.Context) {
+	filter, err := parseInvoiceFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	balances, err := fc.service.GetOutstandingBalances(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// parseInvoiceFilter reads the optional student_id, term_id, school_id
+// and class_id query parameters.
+func parseInvoiceFilter(c *gin.Context) (repository.InvoiceFilter, error) {
+	var filter repository.InvoiceFilter
+	params := map[string]*uint{
+		"student_id": &filter.StudentID,
+		"term_id":    &filter.TermID,
+		"school_id":  &filter.SchoolID,
+		"class_id":   &filter.ClassID,
+	}
+	for param, target := range params {
+		if value, ok := c.GetQuery(param); ok {
+			id, err := strconv.ParseUint(value, 10, 64)
+			if err != nil {
+				return filter, errors.New("Invalid " + param)
+			}
+			*target = uint(id)
+		}
+	}
+	return filter, nil
+}
@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/models"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GuardianController struct {
+	service services.GuardianService
+}
+
+func NewGuardianController(service services.GuardianService) *GuardianController {
+	return &GuardianController{
+		service: service,
+	}
+}
+
+func (gc *GuardianController) GetAllGuardians(c *gin.Context) {
+	if offset, limit, paged, err := parsePage(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	} else if paged {
+		guardians, total, err := gc.service.ListGuardians(offset, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+		c.JSON(http.StatusOK, guardians)
+		return
+	}
+	guardians, err := gc.service.GetAllGuardians()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, guardians)
+}
+
+func (gc *GuardianController) GetGuardianByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	guardian, err := gc.service.GetGuardianByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Guardian not found"})
+		return
+	}
+	c.JSON(http.StatusOK, guardian)
+}
+
+func (gc *GuardianController) CreateGuardian(c *gin.Context) {
+	var newGuardian models.Guardian
+	if err := c.ShouldBindJSON(&newGuardian); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	err := gc.service.CreateGuardian(&newGuardian)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, newGuardian)
+}
+
+func (gc *GuardianController) UpdateGuardian(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	var updatedGuardian models.Guardian
+	if err := c.ShouldBindJSON(&updatedGuardian); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	updatedGuardian.ID = uint(id)
+	err = gc.service.UpdateGuardian(&updatedGuardian)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updatedGuardian)
+}
+
+func (gc *GuardianController) DeleteGuardian(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	err = gc.service.DeleteGuardian(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Guardian deleted successfully"})
+}
+
+func (gc *GuardianController) GetGuardianStudents(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	links, err := gc.service.GetGuardianStudents(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, links)
+}
+
+func (gc *GuardianController) GetStudentContacts(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	contacts, err := gc.service.GetStudentContacts(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, contacts)
+}
+
+func (gc *GuardianController) LinkGuardian(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	var link models.StudentGuardian
+	if err := c.ShouldBindJSON(&link); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	link.StudentID = uint(id)
+	err = gc.service.LinkGuardian(&link)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, link)
+}
+
+func (gc *GuardianController) UpdateLink(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	guardianID, err := strconv.ParseUint(c.Param("guardian_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	var link models.StudentGuardian
+	if err := c.ShouldBindJSON(&link); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	link.StudentID = uint(id)
+	link.GuardianID = uint(guardianID)
+	err = gc.service.UpdateLink(&link)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, link)
+}
+
+func (gc *GuardianController) UnlinkGuardian(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	guardianID, err := strconv.ParseUint(c.Param("guardian_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid guardian ID"})
+		return
+	}
+	err = gc.service.UnlinkGuardian(uint(id), uint(guardianID))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Guardian unlinked successfully"})
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PromotionController struct {
+	service services.PromotionService
+}
+
+func NewPromotionController(service services.PromotionService) *PromotionController {
+	return &PromotionController{
+		service: service,
+	}
+}
+
+func (pc *PromotionController) GetPromotionPlans(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	plans, err := pc.service.GetPromotionPlans(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plans)
+}
+
+func (pc *PromotionController) GetPromotionPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan ID"})
+		return
+	}
+	plan, err := pc.service.GetPromotionPlan(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Promotion plan not found"})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// DraftPromotionPlan proposes a plan; pass_mark defaults to
+// services.DefaultPassMark.
+func (pc *PromotionController) DraftPromotionPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	var input struct {
+		AcademicYearID uint                        `json:"academic_year_id"`
+		PassMark       *int                        `json:"pass_mark"`
+		Progression    []services.ClassProgression `json:"progression"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	rules := services.PromotionRules{
+		AcademicYearID: input.AcademicYearID,
+		PassMark:       services.DefaultPassMark,
+		Progression:    input.Progression,
+	}
+	if input.PassMark != nil {
+		rules.PassMark = *input.PassMark
+	}
+	plan, err := pc.service.DraftPromotionPlan(uint(id), rules)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, plan)
+}
+
+func (pc *PromotionController) OverridePromotion(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan ID"})
+		return
+	}
+	studentID, err := strconv.ParseUint(c.Param("student_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid student ID"})
+		return
+	}
+	var input struct {
+		Outcome   string `json:"outcome"`
+		ToClassID uint   `json:"to_class_id"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	entry, err := pc.service.OverridePromotion(uint(id), uint(studentID), input.Outcome, input.ToClassID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+func (pc *PromotionController) ApplyPromotionPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan ID"})
+		return
+	}
+	plan, err := pc.service.ApplyPromotionPlan(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+func (pc *PromotionController) DeletePromotionPlan(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plan ID"})
+		return
+	}
+	err = pc.service.DeletePromotionPlan(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Promotion plan deleted successfully"})
+}
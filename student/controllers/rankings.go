@@ -0,0 +1,86 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RankingController serves leaderboards of students ranked by marks, or by
+// the marks of a term with term_id, or by their score in a subject with
+// subject. Results are paged with offset and limit, the first page by
+// default.
+type RankingController struct {
+	service services.RankingService
+}
+
+func NewRankingController(service services.RankingService) *RankingController {
+	return &RankingController{
+		service: service,
+	}
+}
+
+func (rc *RankingController) GetClassRanking(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	rc.respond(c, func(opts services.RankingOptions) ([]services.RankedStudent, int64, error) {
+		return rc.service.GetClassRanking(uint(id), opts)
+	})
+}
+
+func (rc *RankingController) GetSchoolRanking(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	rc.respond(c, func(opts services.RankingOptions) ([]services.RankedStudent, int64, error) {
+		return rc.service.GetSchoolRanking(uint(id), opts)
+	})
+}
+
+func (rc *RankingController) GetDistrictRanking(c *gin.Context) {
+	rc.respond(c, rc.service.GetDistrictRanking)
+}
+
+// rankingFilter reads the term_id and subject that restrict a ranking.
+func rankingFilter(c *gin.Context) (services.RankingOptions, error) {
+	opts := services.RankingOptions{Subject: c.Query("subject")}
+	if value, ok := c.GetQuery("term_id"); ok {
+		termID, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return opts, err
+		}
+		opts.TermID = uint(termID)
+	}
+	return opts, nil
+}
+
+func (rc *RankingController) respond(c *gin.Context, rank func(services.RankingOptions) ([]services.RankedStudent, int64, error)) {
+	offset, limit, paged, err := parsePage(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	}
+	if !paged {
+		limit = defaultPageLimit
+	}
+	opts, err := rankingFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid term ID"})
+		return
+	}
+	opts.Method, opts.Offset, opts.Limit = c.Query("method"), offset, limit
+	ranked, total, err := rank(opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+	c.JSON(http.StatusOK, ranked)
+}
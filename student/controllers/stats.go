@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatsController serves the marks statistics of classes and schools.
+type StatsController struct {
+	service services.StatsService
+}
+
+func NewStatsController(service services.StatsService) *StatsController {
+	return &StatsController{
+		service: service,
+	}
+}
+
+func (sc *StatsController) GetClassStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	opts, err := parseStatsOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := sc.service.GetClassStats(uint(id), opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func (sc *StatsController) GetSchoolStats(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid school ID"})
+		return
+	}
+	opts, err := parseStatsOptions(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	result, err := sc.service.GetSchoolStats(uint(id), opts)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// parseStatsOptions reads the pass_mark, bucket_size and buckets (comma
+// separated lower edges) query parameters.
+func parseStatsOptions(c *gin.Context) (services.StatsOptions, error) {
+	opts := services.StatsOptions{PassMark: services.DefaultPassMark}
+	var err error
+	if value, ok := c.GetQuery("pass_mark"); ok {
+		if opts.PassMark, err = strconv.Atoi(value); err != nil {
+			return opts, errors.New("invalid pass_mark")
+		}
+	}
+	if value, ok := c.GetQuery("bucket_size"); ok {
+		if opts.BucketSize, err = strconv.Atoi(value); err != nil || opts.BucketSize < 1 {
+			return opts, errors.New("invalid bucket_size")
+		}
+	}
+	if value, ok := c.GetQuery("buckets"); ok {
+		for _, edge := range strings.Split(value, ",") {
+			n, err := strconv.Atoi(strings.TrimSpace(edge))
+			if err != nil {
+				return opts, errors.New("invalid buckets")
+			}
+			opts.Buckets = append(opts.Buckets, n)
+		}
+	}
+	return opts, nil
+}
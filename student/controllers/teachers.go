@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"stu/models"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TeacherController struct {
+	service services.TeacherService
+}
+
+func NewTeacherController(service services.TeacherService) *TeacherController {
+	return &TeacherController{
+		service: service,
+	}
+}
+
+func (tc *TeacherController) GetAllTeachers(c *gin.Context) {
+	if offset, limit, paged, err := parsePage(c); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters"})
+		return
+	} else if paged {
+		teachers, total, err := tc.service.ListTeachers(offset, limit)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("X-Total-Count", strconv.FormatInt(total, 10))
+		c.JSON(http.StatusOK, teachers)
+		return
+	}
+	teachers, err := tc.service.GetAllTeachers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, teachers)
+}
+
+func (tc *TeacherController) GetTeacherByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+	teacher, err := tc.service.GetTeacherByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Teacher not found"})
+		return
+	}
+	c.JSON(http.StatusOK, teacher)
+}
+
+func (tc *TeacherController) CreateTeacher(c *gin.Context) {
+	var newTeacher models.Teacher
+	if err := c.ShouldBindJSON(&newTeacher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	err := tc.service.CreateTeacher(&newTeacher)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, newTeacher)
+}
+
+func (tc *TeacherController) UpdateTeacher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+	var updatedTeacher models.Teacher
+	if err := c.ShouldBindJSON(&updatedTeacher); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	updatedTeacher.ID = uint(id)
+	err = tc.service.UpdateTeacher(&updatedTeacher)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updatedTeacher)
+}
+
+func (tc *TeacherController) DeleteTeacher(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+	err = tc.service.DeleteTeacher(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Teacher deleted successfully"})
+}
+
+func (tc *TeacherController) GetTeacherClasses(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+	assignments, err := tc.service.GetTeacherClasses(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (tc *TeacherController) GetClassTeachers(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid class ID"})
+		return
+	}
+	assignments, err := tc.service.GetClassTeachers(uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
+
+func (tc *TeacherController) CreateAssignment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+	var assignment models.TeacherAssignment
+	if err := c.ShouldBindJSON(&assignment); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	assignment.TeacherID = uint(id)
+	err = tc.service.AssignTeacher(&assignment)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, assignment)
+}
+
+func (tc *TeacherController) DeleteAssignment(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID"})
+		return
+	}
+	assignmentID, err := strconv.ParseUint(c.Param("assignment_id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid assignment ID"})
+		return
+	}
+	err = tc.service.UnassignTeacher(uint(id), uint(assignmentID))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Assignment deleted successfully"})
+}
@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errorStatus maps a service error to the response status: 400 for
+// validation errors, 404 for missing records, 409 for conflicting writes,
+// full classes and computed marks, and 500 otherwise.
+func errorStatus(err error) int {
+	var validationErr *services.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return http.StatusNotFound
+	}
+	if errors.Is(err, repository.ErrConflict) || errors.Is(err, repository.ErrClassFull) ||
+		errors.Is(err, repository.ErrMarksComputed) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+type WebhookController struct {
+	service services.WebhookService
+}
+
+func NewWebhookController(service services.WebhookService) *WebhookController {
+	return &WebhookController{
+		service: service,
+	}
+}
+
+func (wc *WebhookController) GetAllWebhooks(c *gin.Context) {
+	subscriptions, err := wc.service.GetAllWebhooks()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, subscriptions)
+}
+
+func (wc *WebhookController) GetWebhookByID(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+	subscription, err := wc.service.GetWebhookByID(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return
+	}
+	c.JSON(http.StatusOK, subscription)
+}
+
+func (wc *WebhookController) CreateWebhook(c *gin.Context) {
+	var newSubscription models.WebhookSubscription
+	if err := c.ShouldBindJSON(&newSubscription); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	err := wc.service.CreateWebhook(&newSubscription)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, newSubscription)
+}
+
+func (wc *WebhookController) UpdateWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+	var updatedSubscription models.WebhookSubscription
+	if err := c.ShouldBindJSON(&updatedSubscription); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	updatedSubscription.ID = uint(id)
+	err = wc.service.UpdateWebhook(&updatedSubscription)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updatedSubscription)
+}
+
+func (wc *WebhookController) DeleteWebhook(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid webhook ID"})
+		return
+	}
+	err = wc.service.DeleteWebhook(uint(id))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// GetWebhookDeliveries lists queued deliveries; ?status=dead returns the
+// dead-letter list.
+func (wc *WebhookController) GetWebhookDeliveries(c *gin.Context) {
+	deliveries, err := wc.service.GetWebhookDeliveries(c.Query("status"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
+
+func (wc *WebhookController) ReplayWebhookDelivery(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+	delivery, err := wc.service.ReplayWebhookDelivery(uint(id))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Delivery not found"})
+		return
+	}
+	c.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,49 @@
+package events
+
+import (
+	"errors"
+	"sync"
+)
+
+// Bus fans events out to every subscriber; each subscriber sees events in
+// the order they were published. Subscribers are called synchronously and
+// must not block.
+type Bus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers map[int]Publisher
+}
+
+func NewBus() *Bus {
+	return &Bus{
+		subscribers: map[int]Publisher{},
+	}
+}
+
+// Subscribe registers p and returns a function that removes it again.
+func (b *Bus) Subscribe(p Publisher) (unsubscribe func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	id := b.nextID
+	b.nextID++
+	b.subscribers[id] = p
+	return func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		delete(b.subscribers, id)
+	}
+}
+
+// Publish hands event to every subscriber, even when some of them fail, and
+// returns their errors joined.
+func (b *Bus) Publish(event Event) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	var errs []error
+	for _, subscriber := range b.subscribers {
+		if err := subscriber.Publish(event); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
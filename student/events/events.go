@@ -0,0 +1,95 @@
+// Package events defines the domain events emitted when schools, classes
+// and students change, and an in-process bus to fan them out.
+package events
+
+import (
+	"encoding/json"
+	"stu/models"
+	"time"
+)
+
+const (
+	SchoolCreated = "school.created"
+	SchoolUpdated = "school.updated"
+	SchoolDeleted = "school.deleted"
+
+	ClassCreated = "class.created"
+	ClassUpdated = "class.updated"
+	ClassDeleted = "class.deleted"
+
+	StudentCreated      = "student.created"
+	StudentUpdated      = "student.updated"
+	StudentMarksChanged = "student.marks_changed"
+	StudentDeleted      = "student.deleted"
+	StudentGraduated    = "student.graduated"
+)
+
+// Types lists every event type, for validating subscriptions.
+var Types = []string{
+	SchoolCreated, SchoolUpdated, SchoolDeleted,
+	ClassCreated, ClassUpdated, ClassDeleted,
+	StudentCreated, StudentUpdated, StudentMarksChanged, StudentDeleted, StudentGraduated,
+}
+
+// Event describes a change to one entity. SchoolID and ClassID scope the
+// event for subscribers that only care about part of the data. Position is
+// the outbox position of a stored event, which orders events by commit.
+type Event struct {
+	ID         uint64          `json:"id"`
+	Position   uint64          `json:"position,omitempty"`
+	Type       string          `json:"type"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uint            `json:"entity_id"`
+	SchoolID   uint            `json:"school_id,omitempty"`
+	ClassID    uint            `json:"class_id,omitempty"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// New builds an event whose Data is the JSON encoding of data.
+func New(eventType, entityType string, entityID uint, data interface{}) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{
+		Type:       eventType,
+		EntityType: entityType,
+		EntityID:   entityID,
+		OccurredAt: time.Now().UTC(),
+		Data:       raw,
+	}, nil
+}
+
+// FromOutbox converts a stored outbox row into an event.
+func FromOutbox(row models.OutboxEvent) Event {
+	var position uint64
+	if row.Position != nil {
+		position = *row.Position
+	}
+	return Event{
+		ID:         row.ID,
+		Position:   position,
+		Type:       row.Type,
+		EntityType: row.EntityType,
+		EntityID:   row.EntityID,
+		SchoolID:   row.SchoolID,
+		ClassID:    row.ClassID,
+		OccurredAt: row.OccurredAt,
+		Data:       json.RawMessage(row.Data),
+	}
+}
+
+// Publisher receives events after the change they describe has been saved.
+// An error means the event was not handled and should be published again;
+// publishers see events again after such retries and must tolerate them.
+type Publisher interface {
+	Publish(event Event) error
+}
+
+// PublisherFunc adapts a function to the Publisher interface.
+type PublisherFunc func(event Event) error
+
+func (f PublisherFunc) Publish(event Event) error {
+	return f(event)
+}
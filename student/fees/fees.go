@@ -0,0 +1,123 @@
+// Package fees prices invoices, builds their ledger postings and renders
+// them for printing. Amounts are integers in the currency's minor unit.
+package fees
+
+import (
+	"fmt"
+	"strings"
+	"stu/models"
+)
+
+// Price charges each fee item and applies the discounts in order. A
+// discount never takes more than what is left to pay, so the total is
+// never negative, and a discount of a fee item applies to and never takes
+// more than what is left of that item's charge. Discount lines have
+// negative amounts.
+func Price(items []models.FeeItem, discounts []models.Discount) (lines []models.InvoiceLine, subtotal, discount int64) {
+	// charged is what is left of each item's charge after its discounts.
+	charged := make(map[uint]int64, len(items))
+	for _, item := range items {
+		lines = append(lines, models.InvoiceLine{FeeItemID: item.ID, Description: item.Name, Amount: item.Amount})
+		charged[item.ID] += item.Amount
+		subtotal += item.Amount
+	}
+	remaining := subtotal
+	for _, d := range discounts {
+		base, limit := subtotal, remaining
+		if d.FeeItemID != 0 {
+			base = charged[d.FeeItemID]
+			limit = min(base, remaining)
+		}
+		if base == 0 {
+			continue
+		}
+		off := min(base*int64(d.Percent)/100+d.Amount, limit)
+		if off <= 0 {
+			continue
+		}
+		if d.FeeItemID != 0 {
+			charged[d.FeeItemID] -= off
+		}
+		lines = append(lines, models.InvoiceLine{DiscountID: d.ID, Description: discountLabel(d), Amount: -off})
+		remaining -= off
+		discount += off
+	}
+	return lines, subtotal, discount
+}
+
+func discountLabel(d models.Discount) string {
+	kind := "Discount"
+	if d.Kind == models.DiscountKindScholarship {
+		kind = "Scholarship"
+	}
+	if d.Name == "" {
+		return kind
+	}
+	return kind + ": " + d.Name
+}
+
+// InvoiceEntries posts an invoice: the student owes the total, the fees
+// are income and the discounts reduce it.
+func InvoiceEntries(invoice models.Invoice) []models.LedgerEntry {
+	journal := "invoice:" + invoice.Number
+	entries := []models.LedgerEntry{
+		{Account: models.AccountReceivable, StudentID: invoice.StudentID, Debit: invoice.Total},
+		{Account: models.AccountFeeIncome, Credit: invoice.Subtotal},
+	}
+	if invoice.Discount != 0 {
+		entries = append(entries, models.LedgerEntry{Account: models.AccountDiscounts, Debit: invoice.Discount})
+	}
+	for i := range entries {
+		entries[i].Journal = journal
+		entries[i].InvoiceID = invoice.ID
+		entries[i].Memo = "Invoice " + invoice.Number
+	}
+	return entries
+}
+
+// PaymentEntries posts a payment against the invoice it settles.
+func PaymentEntries(payment models.Payment) []models.LedgerEntry {
+	journal := fmt.Sprintf("payment:%d", payment.ID)
+	memo := "Payment"
+	if payment.Reference != "" {
+		memo += " " + payment.Reference
+	}
+	entries := []models.LedgerEntry{
+		{Account: models.AccountCash, Debit: payment.Amount},
+		{Account: models.AccountReceivable, StudentID: payment.StudentID, Credit: payment.Amount},
+	}
+	for i := range entries {
+		entries[i].Journal = journal
+		entries[i].InvoiceID = payment.InvoiceID
+		entries[i].PaymentID = payment.ID
+		entries[i].Memo = memo
+	}
+	return entries
+}
+
+// Balanced reports whether the debits of the entries equal their credits.
+func Balanced(entries []models.LedgerEntry) bool {
+	var sum int64
+	for _, entry := range entries {
+		sum += entry.Debit - entry.Credit
+	}
+	return sum == 0
+}
+
+// FormatAmount formats an amount in minor units with two decimals and
+// thousands separators, e.g. 123456 as "1,234.56".
+func FormatAmount(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	whole := fmt.Sprint(amount / 100)
+	var b strings.Builder
+	for i, digit := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(digit)
+	}
+	return fmt.Sprintf("%s%s.%02d", sign, b.String(), amount%100)
+}
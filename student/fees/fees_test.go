@@ -0,0 +1,117 @@
+package fees_test
+
+import (
+	"bytes"
+	"stu/fees"
+	"stu/models"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func feeItem(id uint, name string, amount int64) models.FeeItem {
+	item := models.FeeItem{Name: name, Amount: amount}
+	item.ID = id
+	return item
+}
+
+func discount(id, feeItemID uint, kind string, percent int, amount int64) models.Discount {
+	d := models.Discount{FeeItemID: feeItemID, Kind: kind, Name: "Sibling", Percent: percent, Amount: amount}
+	d.ID = id
+	return d
+}
+
+func TestPrice_AppliesDiscountsInOrder(t *testing.T) {
+	items := []models.FeeItem{feeItem(1, "Tuition", 50000), feeItem(2, "Transport", 10000)}
+	discounts := []models.Discount{
+		discount(1, 0, models.DiscountKindDiscount, 10, 0),
+		discount(2, 2, models.DiscountKindScholarship, 50, 500),
+	}
+
+	lines, subtotal, off := fees.Price(items, discounts)
+
+	assert.Equal(t, int64(60000), subtotal)
+	assert.Equal(t, int64(6000+5500), off)
+	assert.Equal(t, 4, len(lines))
+	assert.Equal(t, int64(-6000), lines[2].Amount)
+	assert.Equal(t, "Discount: Sibling", lines[2].Description)
+	assert.Equal(t, int64(-5500), lines[3].Amount)
+	assert.Equal(t, "Scholarship: Sibling", lines[3].Description)
+}
+
+func TestPrice_NeverDiscountsBelowZero(t *testing.T) {
+	items := []models.FeeItem{feeItem(1, "Tuition", 50000)}
+	discounts := []models.Discount{
+		discount(1, 0, models.DiscountKindScholarship, 80, 0),
+		discount(2, 0, models.DiscountKindDiscount, 0, 20000),
+		discount(3, 9, models.DiscountKindDiscount, 100, 0),
+	}
+
+	lines, subtotal, off := fees.Price(items, discounts)
+
+	assert.Equal(t, subtotal, off)
+	assert.Equal(t, 3, len(lines))
+	assert.Equal(t, int64(-10000), lines[2].Amount)
+}
+
+func TestPrice_CapsItemDiscountsAtTheItem(t *testing.T) {
+	items := []models.FeeItem{feeItem(1, "Tuition", 50000), feeItem(2, "Transport", 10000)}
+	discounts := []models.Discount{
+		discount(1, 2, models.DiscountKindDiscount, 50, 0),
+		discount(2, 2, models.DiscountKindScholarship, 50, 0),
+		discount(3, 2, models.DiscountKindDiscount, 0, 20000),
+	}
+
+	lines, _, off := fees.Price(items, discounts)
+
+	// The second half applies to what the first left; the fixed amount
+	// only takes the rest of the transport fee, not part of the tuition.
+	assert.Equal(t, int64(10000), off)
+	assert.Equal(t, 5, len(lines))
+	assert.Equal(t, int64(-5000), lines[2].Amount)
+	assert.Equal(t, int64(-2500), lines[3].Amount)
+	assert.Equal(t, int64(-2500), lines[4].Amount)
+}
+
+func TestEntries_AreBalanced(t *testing.T) {
+	invoice := models.Invoice{ID: 3, Number: "INV-2-5", StudentID: 5, Subtotal: 60000, Discount: 11500, Total: 48500}
+	entries := fees.InvoiceEntries(invoice)
+	assert.True(t, fees.Balanced(entries))
+	assert.Equal(t, 3, len(entries))
+	assert.Equal(t, int64(48500), entries[0].Debit)
+	assert.Equal(t, uint(5), entries[0].StudentID)
+
+	payment := models.Payment{ID: 7, InvoiceID: 3, StudentID: 5, Amount: 20000}
+	entries = fees.PaymentEntries(payment)
+	assert.True(t, fees.Balanced(entries))
+	assert.Equal(t, models.AccountReceivable, entries[1].Account)
+	assert.Equal(t, int64(20000), entries[1].Credit)
+}
+
+func TestFormatAmount(t *testing.T) {
+	assert.Equal(t, "0.05", fees.FormatAmount(5))
+	assert.Equal(t, "1,234.56", fees.FormatAmount(123456))
+	assert.Equal(t, "-1,000,000.00", fees.FormatAmount(-100000000))
+}
+
+func TestWriteHTML_EscapesAndFormats(t *testing.T) {
+	var buf bytes.Buffer
+	doc := fees.Document{
+		Invoice: models.Invoice{
+			Number:  "INV-2-5",
+			DueDate: time.Date(2024, 7, 1, 0, 0, 0, 0, time.UTC),
+			Lines:   []models.InvoiceLine{{Description: "Tuition <term 1>", Amount: 50000}},
+			Total:   50000,
+			Balance: 50000,
+		},
+		Student: models.Student{Name: "Ada"},
+		School:  models.School{Name: "Hillside"},
+	}
+
+	assert.Nil(t, fees.WriteHTML(&buf, doc))
+	html := buf.String()
+	assert.Contains(t, html, "Tuition &lt;term 1&gt;")
+	assert.Contains(t, html, "500.00")
+	assert.Contains(t, html, "1 July 2024")
+}
@@ -0,0 +1,61 @@
+package fees
+
+import (
+	"html/template"
+	"io"
+	"stu/models"
+	"time"
+)
+
+// Document is what a printed invoice shows.
+type Document struct {
+	Invoice models.Invoice
+	Student models.Student
+	School  models.School
+	Class   models.Class
+	Term    models.Term
+}
+
+var invoiceTemplate = template.Must(template.New("invoice").Funcs(template.FuncMap{
+	"amount": FormatAmount,
+	"date":   func(t time.Time) string { return t.Format("2 January 2006") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Invoice {{.Invoice.Number}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+td, th { padding: 0.3em 0.6em; border-bottom: 1px solid #ccc; text-align: left; }
+td.amount, th.amount { text-align: right; }
+tfoot td { font-weight: bold; }
+</style>
+</head>
+<body>
+<h1>{{.School.Name}}</h1>
+<h2>Invoice {{.Invoice.Number}}</h2>
+<p>
+{{.Student.Name}}{{with .Class.ClassName}}, class {{.}}{{end}}<br>
+{{with .Student.Address}}{{.Street}}{{if .City}}, {{.City}}{{end}}{{if .State}}, {{.State}}{{end}}{{end}}
+</p>
+<p>Term: {{.Term.Name}}<br>Due: {{date .Invoice.DueDate}}</p>
+<table>
+<thead><tr><th>Description</th><th class="amount">Amount</th></tr></thead>
+<tbody>
+{{range .Invoice.Lines}}<tr><td>{{.Description}}</td><td class="amount">{{amount .Amount}}</td></tr>
+{{end}}</tbody>
+<tfoot>
+<tr><td>Total</td><td class="amount">{{amount .Invoice.Total}}</td></tr>
+<tr><td>Paid</td><td class="amount">{{amount .Invoice.Paid}}</td></tr>
+<tr><td>Balance due</td><td class="amount">{{amount .Invoice.Balance}}</td></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+// WriteHTML renders the invoice as a printable HTML page.
+func WriteHTML(w io.Writer, doc Document) error {
+	return invoiceTemplate.Execute(w, doc)
+}
@@ -0,0 +1,44 @@
+// Package gql serves a GraphQL API over the services layer so clients can
+// fetch schools with their classes and students in one round trip.
+package gql
+
+import (
+	"encoding/json"
+	"net/http"
+	"stu/services"
+
+	"github.com/gin-gonic/gin"
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type Handler struct {
+	schema   *graphql.Schema
+	resolver *Resolver
+}
+
+func NewHandler(schools services.SchoolService, classes services.ClassService, students services.StudentService) *Handler {
+	resolver := NewResolver(schools, classes, students)
+	return &Handler{
+		schema:   graphql.MustParseSchema(schemaSDL, resolver),
+		resolver: resolver,
+	}
+}
+
+type request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Serve executes a GraphQL request. Each request gets its own loaders so
+// batching and caching never leak between requests.
+func (h *Handler) Serve(c *gin.Context) {
+	var req request
+	if err := json.NewDecoder(c.Request.Body).Decode(&req); err != nil || req.Query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+	ctx := withLoaders(c.Request.Context(), newLoaders(h.resolver.schools, h.resolver.classes, h.resolver.students))
+	resp := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	c.JSON(http.StatusOK, resp)
+}
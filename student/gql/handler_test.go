@@ -0,0 +1,245 @@
+package gql_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"stu/gql"
+	"stu/models"
+	"stu/services"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+// MockService is a mock implementation of the school, class and student
+// services for testing purposes.
+type MockService struct {
+	mock.Mock
+}
+
+func (m *MockService) GetAllSchools() ([]models.School, error) {
+	args := m.Called()
+	return args.Get(0).([]models.School), args.Error(1)
+}
+
+func (m *MockService) ListSchools(offset, limit int) ([]models.School, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.School), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockService) GetSchoolByID(id uint) (*models.School, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.School), args.Error(1)
+}
+
+func (m *MockService) GetSchoolsByIDs(ids []uint) ([]models.School, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.School), args.Error(1)
+}
+
+func (m *MockService) CreateSchool(school *models.School) error {
+	args := m.Called(school)
+	return args.Error(0)
+}
+
+func (m *MockService) UpdateSchool(school *models.School) error {
+	args := m.Called(school)
+	return args.Error(0)
+}
+
+func (m *MockService) DeleteSchool(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockService) GetAllClasses() ([]models.Class, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Class), args.Error(1)
+}
+
+func (m *MockService) ListClasses(offset, limit int) ([]models.Class, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.Class), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockService) GetClassByID(id uint) (*models.Class, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Class), args.Error(1)
+}
+
+func (m *MockService) GetClassesByIDs(ids []uint) ([]models.Class, error) {
+	args := m.Called(ids)
+	return args.Get(0).([]models.Class), args.Error(1)
+}
+
+func (m *MockService) GetClassesBySchoolIDs(schoolIDs []uint) ([]models.Class, error) {
+	args := m.Called(schoolIDs)
+	return args.Get(0).([]models.Class), args.Error(1)
+}
+
+func (m *MockService) CreateClass(class *models.Class) error {
+	args := m.Called(class)
+	return args.Error(0)
+}
+
+func (m *MockService) UpdateClass(class *models.Class) error {
+	args := m.Called(class)
+	return args.Error(0)
+}
+
+func (m *MockService) DeleteClass(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockService) GetAllStudents() ([]models.Student, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockService) ListStudents(offset, limit int) ([]models.Student, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.Student), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockService) GetStudentByID(id uint) (*models.Student, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Student), args.Error(1)
+}
+
+func (m *MockService) GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error) {
+	args := m.Called(classIDs)
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockService) CreateStudent(student *models.Student) error {
+	args := m.Called(student)
+	return args.Error(0)
+}
+
+func (m *MockService) UpdateStudent(student *models.Student) error {
+	args := m.Called(student)
+	return args.Error(0)
+}
+
+func (m *MockService) DeleteStudent(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockService) SearchStudents(query string, limit int) ([]services.StudentMatch, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]services.StudentMatch), args.Error(1)
+}
+
+func school(id uint, name string) models.School {
+	s := models.School{Name: name}
+	s.ID = id
+	return s
+}
+
+func class(id, schoolID uint, name string) models.Class {
+	c := models.Class{ClassName: name, SchoolID: schoolID}
+	c.ID = id
+	return c
+}
+
+func student(id, classID uint, name string, marks int) models.Student {
+	s := models.Student{Name: name, Marks: marks, ClassID: classID}
+	s.ID = id
+	return s
+}
+
+func execute(t *testing.T, mockService *MockService, query string, variables map[string]interface{}) map[string]interface{} {
+	result := post(t, mockService, query, variables)
+	assert.Nil(t, result["errors"])
+	return result
+}
+
+func post(t *testing.T, mockService *MockService, query string, variables map[string]interface{}) map[string]interface{} {
+	router := gin.Default()
+	router.POST("/graphql", gql.NewHandler(mockService, mockService, mockService).Serve)
+	body, _ := json.Marshal(map[string]interface{}{"query": query, "variables": variables})
+	req, _ := http.NewRequest("POST", "/graphql", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var result map[string]interface{}
+	assert.Nil(t, json.NewDecoder(resp.Body).Decode(&result))
+	return result
+}
+
+func TestGraphQL_NestedQueryIsBatched(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("GetAllSchools").Return([]models.School{school(1, "North"), school(2, "South")}, nil)
+	mockService.On("GetClassesBySchoolIDs", mock.Anything).Return([]models.Class{
+		class(10, 1, "Grade 1-A"), class(11, 1, "Grade 2-A"), class(20, 2, "Grade 1-A"),
+	}, nil)
+	mockService.On("GetStudentsByClassIDs", mock.Anything).Return([]models.Student{
+		student(100, 10, "Jahnavi", 91), student(101, 11, "Rahul", 78), student(102, 20, "Priya", 85),
+	}, nil)
+
+	result := execute(t, mockService, `{ schools { name classes { className students { name marks } } } }`, nil)
+
+	schools := result["data"].(map[string]interface{})["schools"].([]interface{})
+	assert.Len(t, schools, 2)
+	north := schools[0].(map[string]interface{})
+	assert.Equal(t, "North", north["name"])
+	northClasses := north["classes"].([]interface{})
+	assert.Len(t, northClasses, 2)
+	students := northClasses[0].(map[string]interface{})["students"].([]interface{})
+	assert.Equal(t, map[string]interface{}{"name": "Jahnavi", "marks": float64(91)}, students[0])
+
+	mockService.AssertNumberOfCalls(t, "GetClassesBySchoolIDs", 1)
+	mockService.AssertNumberOfCalls(t, "GetStudentsByClassIDs", 1)
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQL_UpdateStudentKeepsOtherFields(t *testing.T) {
+	mockService := new(MockService)
+	existing := student(5, 10, "Jahnavi", 70)
+	existing.Address = models.Address{City: "Vizag"}
+	mockService.On("GetStudentByID", uint(5)).Return(&existing, nil)
+	mockService.On("UpdateStudent", mock.MatchedBy(func(s *models.Student) bool {
+		return s.Marks == 95 && s.Name == "Jahnavi" && s.Address.City == "Vizag"
+	})).Return(nil)
+
+	result := execute(t, mockService,
+		`mutation($id: ID!) { updateStudent(id: $id, input: {marks: 95}) { id marks address { city } } }`,
+		map[string]interface{}{"id": "5"})
+
+	updated := result["data"].(map[string]interface{})["updateStudent"].(map[string]interface{})
+	assert.Equal(t, "5", updated["id"])
+	assert.Equal(t, float64(95), updated["marks"])
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQL_MissingStudentIsNull(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("GetStudentByID", uint(9)).Return((*models.Student)(nil), gorm.ErrRecordNotFound)
+
+	result := execute(t, mockService, `{ student(id: "9") { name } }`, nil)
+
+	assert.Nil(t, result["data"].(map[string]interface{})["student"])
+	mockService.AssertExpectations(t)
+}
+
+func TestGraphQL_LookupFailureIsAnError(t *testing.T) {
+	mockService := new(MockService)
+	mockService.On("GetStudentByID", uint(9)).Return((*models.Student)(nil), errors.New("connection refused"))
+
+	result := post(t, mockService, `{ student(id: "9") { name } }`, nil)
+
+	errs := result["errors"].([]interface{})
+	assert.Len(t, errs, 1)
+	assert.Contains(t, errs[0].(map[string]interface{})["message"], "connection refused")
+	mockService.AssertExpectations(t)
+}
@@ -0,0 +1,132 @@
+package gql
+
+import (
+	"context"
+	"stu/models"
+	"stu/services"
+	"sync"
+)
+
+// Loader batches lookups by key DataLoader-style. Parent resolvers Register
+// the keys their children will need; the first Load then fetches every
+// registered key in one call and later Loads are served from the cache.
+type Loader[K comparable, V any] struct {
+	fetch func(keys []K) (map[K]V, error)
+
+	mu      sync.Mutex
+	pending map[K]struct{}
+	cache   map[K]V
+}
+
+func NewLoader[K comparable, V any](fetch func(keys []K) (map[K]V, error)) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:   fetch,
+		pending: map[K]struct{}{},
+		cache:   map[K]V{},
+	}
+}
+
+// Register queues keys to be fetched with the next batch.
+func (l *Loader[K, V]) Register(keys ...K) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, key := range keys {
+		if _, ok := l.cache[key]; !ok {
+			l.pending[key] = struct{}{}
+		}
+	}
+}
+
+// Load returns the value for key, fetching it together with every pending
+// key if it is not cached yet. Keys the fetch does not return load as the
+// zero value.
+func (l *Loader[K, V]) Load(key K) (V, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if value, ok := l.cache[key]; ok {
+		return value, nil
+	}
+
+	l.pending[key] = struct{}{}
+	keys := make([]K, 0, len(l.pending))
+	for k := range l.pending {
+		keys = append(keys, k)
+	}
+	l.pending = map[K]struct{}{}
+
+	values, err := l.fetch(keys)
+	if err != nil {
+		var zero V
+		return zero, err
+	}
+	for _, k := range keys {
+		l.cache[k] = values[k]
+	}
+	return l.cache[key], nil
+}
+
+// loaders holds the per-request loaders used by the resolvers.
+type loaders struct {
+	schoolByID      *Loader[uint, *models.School]
+	classByID       *Loader[uint, *models.Class]
+	classesBySchool *Loader[uint, []models.Class]
+	studentsByClass *Loader[uint, []models.Student]
+}
+
+func newLoaders(schools services.SchoolService, classes services.ClassService, students services.StudentService) *loaders {
+	l := &loaders{}
+	l.studentsByClass = NewLoader(func(classIDs []uint) (map[uint][]models.Student, error) {
+		rows, err := students.GetStudentsByClassIDs(classIDs)
+		if err != nil {
+			return nil, err
+		}
+		byClass := make(map[uint][]models.Student, len(classIDs))
+		for _, row := range rows {
+			byClass[row.ClassID] = append(byClass[row.ClassID], row)
+		}
+		return byClass, nil
+	})
+	// Fetching the classes of every school registers all of those classes
+	// for the student batch, however the executor orders the resolvers.
+	l.classesBySchool = NewLoader(func(schoolIDs []uint) (map[uint][]models.Class, error) {
+		rows, err := classes.GetClassesBySchoolIDs(schoolIDs)
+		if err != nil {
+			return nil, err
+		}
+		bySchool := make(map[uint][]models.Class, len(schoolIDs))
+		for _, row := range rows {
+			bySchool[row.SchoolID] = append(bySchool[row.SchoolID], row)
+			l.studentsByClass.Register(row.ID)
+		}
+		return bySchool, nil
+	})
+	l.schoolByID = NewLoader(func(ids []uint) (map[uint]*models.School, error) {
+		rows, err := schools.GetSchoolsByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]*models.School, len(rows))
+		for i := range rows {
+			byID[rows[i].ID] = &rows[i]
+		}
+		return byID, nil
+	})
+	l.classByID = NewLoader(func(ids []uint) (map[uint]*models.Class, error) {
+		rows, err := classes.GetClassesByIDs(ids)
+		if err != nil {
+			return nil, err
+		}
+		byID := make(map[uint]*models.Class, len(rows))
+		for i := range rows {
+			byID[rows[i].ID] = &rows[i]
+		}
+		return byID, nil
+	})
+	return l
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
@@ -0,0 +1,208 @@
+package gql
+
+import (
+	"context"
+	"stu/models"
+
+	graphql "github.com/graph-gophers/graphql-go"
+)
+
+type schoolInput struct {
+	Name *string
+}
+
+func (in schoolInput) apply(school *models.School) {
+	if in.Name != nil {
+		school.Name = *in.Name
+	}
+}
+
+type classInput struct {
+	ClassID   *int32
+	ClassName *string
+	SchoolID  *graphql.ID
+}
+
+func (in classInput) apply(class *models.Class) error {
+	if in.ClassID != nil {
+		class.ClassID = uint(*in.ClassID)
+	}
+	if in.ClassName != nil {
+		class.ClassName = *in.ClassName
+	}
+	if in.SchoolID != nil {
+		id, err := parseID(*in.SchoolID)
+		if err != nil {
+			return err
+		}
+		class.SchoolID = id
+	}
+	return nil
+}
+
+type addressInput struct {
+	Street *string
+	City   *string
+	State  *string
+}
+
+type studentInput struct {
+	StudentID *int32
+	Name      *string
+	Marks     *int32
+	Address   *addressInput
+	ClassID   *graphql.ID
+}
+
+func (in studentInput) apply(student *models.Student) error {
+	if in.StudentID != nil {
+		student.StudentID = int(*in.StudentID)
+	}
+	if in.Name != nil {
+		student.Name = *in.Name
+	}
+	if in.Marks != nil {
+		student.Marks = int(*in.Marks)
+	}
+	if in.Address != nil {
+		if in.Address.Street != nil {
+			student.Address.Street = *in.Address.Street
+		}
+		if in.Address.City != nil {
+			student.Address.City = *in.Address.City
+		}
+		if in.Address.State != nil {
+			student.Address.State = *in.Address.State
+		}
+	}
+	if in.ClassID != nil {
+		id, err := parseID(*in.ClassID)
+		if err != nil {
+			return err
+		}
+		student.ClassID = id
+	}
+	return nil
+}
+
+// School mutations
+
+func (r *Resolver) CreateSchool(ctx context.Context, args struct{ Input schoolInput }) (*schoolResolver, error) {
+	var school models.School
+	args.Input.apply(&school)
+	if err := r.schools.CreateSchool(&school); err != nil {
+		return nil, err
+	}
+	return r.schoolResolvers(ctx, []models.School{school})[0], nil
+}
+
+func (r *Resolver) UpdateSchool(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input schoolInput
+}) (*schoolResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	school, err := r.schools.GetSchoolByID(id)
+	if err != nil {
+		return nil, err
+	}
+	args.Input.apply(school)
+	if err := r.schools.UpdateSchool(school); err != nil {
+		return nil, err
+	}
+	return r.schoolResolvers(ctx, []models.School{*school})[0], nil
+}
+
+func (r *Resolver) DeleteSchool(args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	return true, r.schools.DeleteSchool(id)
+}
+
+// Class mutations
+
+func (r *Resolver) CreateClass(ctx context.Context, args struct{ Input classInput }) (*classResolver, error) {
+	var class models.Class
+	if err := args.Input.apply(&class); err != nil {
+		return nil, err
+	}
+	if err := r.classes.CreateClass(&class); err != nil {
+		return nil, err
+	}
+	return r.classResolvers(ctx, []models.Class{class})[0], nil
+}
+
+func (r *Resolver) UpdateClass(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input classInput
+}) (*classResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	class, err := r.classes.GetClassByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := args.Input.apply(class); err != nil {
+		return nil, err
+	}
+	if err := r.classes.UpdateClass(class); err != nil {
+		return nil, err
+	}
+	return r.classResolvers(ctx, []models.Class{*class})[0], nil
+}
+
+func (r *Resolver) DeleteClass(args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	return true, r.classes.DeleteClass(id)
+}
+
+// Student mutations
+
+func (r *Resolver) CreateStudent(ctx context.Context, args struct{ Input studentInput }) (*studentResolver, error) {
+	var student models.Student
+	if err := args.Input.apply(&student); err != nil {
+		return nil, err
+	}
+	if err := r.students.CreateStudent(&student); err != nil {
+		return nil, err
+	}
+	return r.studentResolvers(ctx, []models.Student{student})[0], nil
+}
+
+func (r *Resolver) UpdateStudent(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input studentInput
+}) (*studentResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	student, err := r.students.GetStudentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := args.Input.apply(student); err != nil {
+		return nil, err
+	}
+	if err := r.students.UpdateStudent(student); err != nil {
+		return nil, err
+	}
+	return r.studentResolvers(ctx, []models.Student{*student})[0], nil
+}
+
+func (r *Resolver) DeleteStudent(args struct{ ID graphql.ID }) (bool, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return false, err
+	}
+	return true, r.students.DeleteStudent(id)
+}
@@ -0,0 +1,249 @@
+package gql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"stu/models"
+	"stu/services"
+	"time"
+
+	graphql "github.com/graph-gophers/graphql-go"
+	"gorm.io/gorm"
+)
+
+// Resolver is the root resolver for queries and mutations.
+type Resolver struct {
+	schools  services.SchoolService
+	classes  services.ClassService
+	students services.StudentService
+}
+
+func NewResolver(schools services.SchoolService, classes services.ClassService, students services.StudentService) *Resolver {
+	return &Resolver{
+		schools:  schools,
+		classes:  classes,
+		students: students,
+	}
+}
+
+// loaders returns the request's loaders, or fresh ones when the schema is
+// executed without going through Handler.
+func (r *Resolver) loaders(ctx context.Context) *loaders {
+	if l, ok := ctx.Value(loadersKey{}).(*loaders); ok {
+		return l
+	}
+	return newLoaders(r.schools, r.classes, r.students)
+}
+
+func parseID(id graphql.ID) (uint, error) {
+	v, err := strconv.ParseUint(string(id), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q", id)
+	}
+	return uint(v), nil
+}
+
+// notFoundAsNull resolves a missing record to null and keeps every other
+// error.
+func notFoundAsNull(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	return err
+}
+
+func formatID(id uint) graphql.ID {
+	return graphql.ID(strconv.FormatUint(uint64(id), 10))
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
+
+// Query resolvers
+
+func (r *Resolver) Schools(ctx context.Context) ([]*schoolResolver, error) {
+	schools, err := r.schools.GetAllSchools()
+	if err != nil {
+		return nil, err
+	}
+	return r.schoolResolvers(ctx, schools), nil
+}
+
+func (r *Resolver) School(ctx context.Context, args struct{ ID graphql.ID }) (*schoolResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	school, err := r.schools.GetSchoolByID(id)
+	if err != nil {
+		return nil, notFoundAsNull(err)
+	}
+	return r.schoolResolvers(ctx, []models.School{*school})[0], nil
+}
+
+func (r *Resolver) Classes(ctx context.Context) ([]*classResolver, error) {
+	classes, err := r.classes.GetAllClasses()
+	if err != nil {
+		return nil, err
+	}
+	return r.classResolvers(ctx, classes), nil
+}
+
+func (r *Resolver) Class(ctx context.Context, args struct{ ID graphql.ID }) (*classResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	class, err := r.classes.GetClassByID(id)
+	if err != nil {
+		return nil, notFoundAsNull(err)
+	}
+	return r.classResolvers(ctx, []models.Class{*class})[0], nil
+}
+
+func (r *Resolver) Students(ctx context.Context) ([]*studentResolver, error) {
+	students, err := r.students.GetAllStudents()
+	if err != nil {
+		return nil, err
+	}
+	return r.studentResolvers(ctx, students), nil
+}
+
+func (r *Resolver) Student(ctx context.Context, args struct{ ID graphql.ID }) (*studentResolver, error) {
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, err
+	}
+	student, err := r.students.GetStudentByID(id)
+	if err != nil {
+		return nil, notFoundAsNull(err)
+	}
+	return r.studentResolvers(ctx, []models.Student{*student})[0], nil
+}
+
+// The *Resolvers helpers wrap rows and register the keys their fields will
+// load, so each level of a nested query costs one batched call.
+
+func (r *Resolver) schoolResolvers(ctx context.Context, schools []models.School) []*schoolResolver {
+	l := r.loaders(ctx)
+	resolvers := make([]*schoolResolver, len(schools))
+	for i := range schools {
+		l.classesBySchool.Register(schools[i].ID)
+		resolvers[i] = &schoolResolver{root: r, loaders: l, school: schools[i]}
+	}
+	return resolvers
+}
+
+func (r *Resolver) classResolvers(ctx context.Context, classes []models.Class) []*classResolver {
+	l := r.loaders(ctx)
+	resolvers := make([]*classResolver, len(classes))
+	for i := range classes {
+		l.studentsByClass.Register(classes[i].ID)
+		if classes[i].SchoolID != 0 {
+			l.schoolByID.Register(classes[i].SchoolID)
+		}
+		resolvers[i] = &classResolver{root: r, loaders: l, class: classes[i]}
+	}
+	return resolvers
+}
+
+func (r *Resolver) studentResolvers(ctx context.Context, students []models.Student) []*studentResolver {
+	l := r.loaders(ctx)
+	resolvers := make([]*studentResolver, len(students))
+	for i := range students {
+		if students[i].ClassID != 0 {
+			l.classByID.Register(students[i].ClassID)
+		}
+		resolvers[i] = &studentResolver{root: r, loaders: l, student: students[i]}
+	}
+	return resolvers
+}
+
+type schoolResolver struct {
+	root    *Resolver
+	loaders *loaders
+	school  models.School
+}
+
+func (s *schoolResolver) ID() graphql.ID    { return formatID(s.school.ID) }
+func (s *schoolResolver) Name() string      { return s.school.Name }
+func (s *schoolResolver) CreatedAt() string { return formatTime(s.school.CreatedAt) }
+func (s *schoolResolver) UpdatedAt() string { return formatTime(s.school.UpdatedAt) }
+
+func (s *schoolResolver) Classes(ctx context.Context) ([]*classResolver, error) {
+	classes, err := s.loaders.classesBySchool.Load(s.school.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.root.classResolvers(ctx, classes), nil
+}
+
+type classResolver struct {
+	root    *Resolver
+	loaders *loaders
+	class   models.Class
+}
+
+func (c *classResolver) ID() graphql.ID    { return formatID(c.class.ID) }
+func (c *classResolver) ClassID() int32    { return int32(c.class.ClassID) }
+func (c *classResolver) ClassName() string { return c.class.ClassName }
+func (c *classResolver) CreatedAt() string { return formatTime(c.class.CreatedAt) }
+func (c *classResolver) UpdatedAt() string { return formatTime(c.class.UpdatedAt) }
+
+func (c *classResolver) School(ctx context.Context) (*schoolResolver, error) {
+	if c.class.SchoolID == 0 {
+		return nil, nil
+	}
+	school, err := c.loaders.schoolByID.Load(c.class.SchoolID)
+	if err != nil || school == nil {
+		return nil, err
+	}
+	return c.root.schoolResolvers(ctx, []models.School{*school})[0], nil
+}
+
+func (c *classResolver) Students(ctx context.Context) ([]*studentResolver, error) {
+	students, err := c.loaders.studentsByClass.Load(c.class.ID)
+	if err != nil {
+		return nil, err
+	}
+	return c.root.studentResolvers(ctx, students), nil
+}
+
+type studentResolver struct {
+	root    *Resolver
+	loaders *loaders
+	student models.Student
+}
+
+func (s *studentResolver) ID() graphql.ID    { return formatID(s.student.ID) }
+func (s *studentResolver) StudentID() int32  { return int32(s.student.StudentID) }
+func (s *studentResolver) Name() string      { return s.student.Name }
+func (s *studentResolver) Marks() int32      { return int32(s.student.Marks) }
+func (s *studentResolver) CreatedAt() string { return formatTime(s.student.CreatedAt) }
+func (s *studentResolver) UpdatedAt() string { return formatTime(s.student.UpdatedAt) }
+
+func (s *studentResolver) Address() *addressResolver {
+	return &addressResolver{address: s.student.Address}
+}
+
+func (s *studentResolver) Class(ctx context.Context) (*classResolver, error) {
+	if s.student.ClassID == 0 {
+		return nil, nil
+	}
+	class, err := s.loaders.classByID.Load(s.student.ClassID)
+	if err != nil || class == nil {
+		return nil, err
+	}
+	return s.root.classResolvers(ctx, []models.Class{*class})[0], nil
+}
+
+type addressResolver struct {
+	address models.Address
+}
+
+func (a *addressResolver) Street() string { return a.address.Street }
+func (a *addressResolver) City() string   { return a.address.City }
+func (a *addressResolver) State() string  { return a.address.State }
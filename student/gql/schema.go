@@ -0,0 +1,89 @@
+package gql
+
+const schemaSDL = `
+schema {
+	query: Query
+	mutation: Mutation
+}
+
+type Query {
+	schools: [School!]!
+	school(id: ID!): School
+	classes: [Class!]!
+	class(id: ID!): Class
+	students: [Student!]!
+	student(id: ID!): Student
+}
+
+type Mutation {
+	createSchool(input: SchoolInput!): School!
+	updateSchool(id: ID!, input: SchoolInput!): School!
+	deleteSchool(id: ID!): Boolean!
+	createClass(input: ClassInput!): Class!
+	updateClass(id: ID!, input: ClassInput!): Class!
+	deleteClass(id: ID!): Boolean!
+	createStudent(input: StudentInput!): Student!
+	updateStudent(id: ID!, input: StudentInput!): Student!
+	deleteStudent(id: ID!): Boolean!
+}
+
+type School {
+	id: ID!
+	name: String!
+	classes: [Class!]!
+	createdAt: String!
+	updatedAt: String!
+}
+
+type Class {
+	id: ID!
+	classId: Int!
+	className: String!
+	school: School
+	students: [Student!]!
+	createdAt: String!
+	updatedAt: String!
+}
+
+type Student {
+	id: ID!
+	studentId: Int!
+	name: String!
+	marks: Int!
+	address: Address!
+	class: Class
+	createdAt: String!
+	updatedAt: String!
+}
+
+type Address {
+	street: String!
+	city: String!
+	state: String!
+}
+
+# Update mutations only change the fields that are present in the input.
+input SchoolInput {
+	name: String
+}
+
+input ClassInput {
+	classId: Int
+	className: String
+	schoolId: ID
+}
+
+input StudentInput {
+	studentId: Int
+	name: String
+	marks: Int
+	address: AddressInput
+	classId: ID
+}
+
+input AddressInput {
+	street: String
+	city: String
+	state: String
+}
+`
@@ -0,0 +1,83 @@
+package grpcapi
+
+import (
+	"stu/grpcapi/pb"
+	"stu/models"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func schoolToProto(s *models.School) *pb.School {
+	return &pb.School{
+		Id:        uint64(s.ID),
+		Name:      s.Name,
+		ClassId:   uint64(s.ClassID),
+		CreatedAt: timestamppb.New(s.CreatedAt),
+		UpdatedAt: timestamppb.New(s.UpdatedAt),
+	}
+}
+
+func schoolFromProto(s *pb.School) *models.School {
+	school := &models.School{
+		Name:    s.GetName(),
+		ClassID: uint(s.GetClassId()),
+	}
+	school.ID = uint(s.GetId())
+	return school
+}
+
+func classToProto(c *models.Class) *pb.Class {
+	return &pb.Class{
+		Id:        uint64(c.ID),
+		ClassId:   uint64(c.ClassID),
+		ClassName: c.ClassName,
+		StudentId: uint64(c.StudentID),
+		SchoolId:  uint64(c.SchoolID),
+		CreatedAt: timestamppb.New(c.CreatedAt),
+		UpdatedAt: timestamppb.New(c.UpdatedAt),
+	}
+}
+
+func classFromProto(c *pb.Class) *models.Class {
+	class := &models.Class{
+		ClassID:   uint(c.GetClassId()),
+		ClassName: c.GetClassName(),
+		StudentID: uint(c.GetStudentId()),
+		SchoolID:  uint(c.GetSchoolId()),
+	}
+	class.ID = uint(c.GetId())
+	return class
+}
+
+func studentToProto(s *models.Student) *pb.Student {
+	return &pb.Student{
+		Id:        uint64(s.ID),
+		StudentId: int64(s.StudentID),
+		Name:      s.Name,
+		Marks:     int32(s.Marks),
+		Address: &pb.Address{
+			Street: s.Address.Street,
+			City:   s.Address.City,
+			State:  s.Address.State,
+		},
+		CreatedAt: timestamppb.New(s.CreatedAt),
+		UpdatedAt: timestamppb.New(s.UpdatedAt),
+		ClassId:   uint64(s.ClassID),
+	}
+}
+
+func studentFromProto(s *pb.Student) *models.Student {
+	student := &models.Student{
+		StudentID: int(s.GetStudentId()),
+		Name:      s.GetName(),
+		Marks:     int(s.GetMarks()),
+		Address: models.Address{
+			Street: s.GetAddress().GetStreet(),
+			City:   s.GetAddress().GetCity(),
+			State:  s.GetAddress().GetState(),
+		},
+		ClassID: uint(s.GetClassId()),
+	}
+	student.ID = uint(s.GetId())
+	return student
+}
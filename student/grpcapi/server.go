@@ -0,0 +1,239 @@
+// Package grpcapi serves the school, class and student services over gRPC.
+// The protobuf definitions live in proto/stu/v1; regenerate the pb package
+// with `buf generate` from the module root.
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"stu/grpcapi/pb"
+	"stu/repository"
+	"stu/services"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+	defaultBatchSize = 500
+)
+
+// NewServer returns a gRPC server with the school, class and student
+// services registered.
+func NewServer(
+	schoolService services.SchoolService,
+	classService services.ClassService,
+	studentService services.StudentService,
+	opts ...grpc.ServerOption,
+) *grpc.Server {
+	server := grpc.NewServer(opts...)
+	pb.RegisterSchoolServiceServer(server, &schoolServer{service: schoolService})
+	pb.RegisterClassServiceServer(server, &classServer{service: classService})
+	pb.RegisterStudentServiceServer(server, &studentServer{service: studentService})
+	return server
+}
+
+// toStatus maps service errors onto gRPC status codes.
+func toStatus(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	if errors.Is(err, repository.ErrMarksComputed) || errors.Is(err, repository.ErrClassFull) {
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+func pageBounds(req *pb.PageRequest) (offset, limit int, err error) {
+	if req.GetOffset() < 0 || req.GetLimit() < 0 {
+		return 0, 0, status.Error(codes.InvalidArgument, "offset and limit must not be negative")
+	}
+	limit = int(req.GetLimit())
+	if limit == 0 {
+		limit = defaultPageLimit
+	}
+	return int(req.GetOffset()), min(limit, maxPageLimit), nil
+}
+
+type schoolServer struct {
+	pb.UnimplementedSchoolServiceServer
+	service services.SchoolService
+}
+
+func (s *schoolServer) ListSchools(ctx context.Context, req *pb.PageRequest) (*pb.ListSchoolsResponse, error) {
+	offset, limit, err := pageBounds(req)
+	if err != nil {
+		return nil, err
+	}
+	schools, total, err := s.service.ListSchools(offset, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListSchoolsResponse{Total: total}
+	for i := range schools {
+		resp.Schools = append(resp.Schools, schoolToProto(&schools[i]))
+	}
+	return resp, nil
+}
+
+func (s *schoolServer) GetSchool(ctx context.Context, req *pb.GetByIDRequest) (*pb.School, error) {
+	school, err := s.service.GetSchoolByID(uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return schoolToProto(school), nil
+}
+
+func (s *schoolServer) CreateSchool(ctx context.Context, req *pb.School) (*pb.School, error) {
+	school := schoolFromProto(req)
+	school.ID = 0
+	if err := s.service.CreateSchool(school); err != nil {
+		return nil, toStatus(err)
+	}
+	return schoolToProto(school), nil
+}
+
+func (s *schoolServer) UpdateSchool(ctx context.Context, req *pb.School) (*pb.School, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	school := schoolFromProto(req)
+	if err := s.service.UpdateSchool(school); err != nil {
+		return nil, toStatus(err)
+	}
+	return schoolToProto(school), nil
+}
+
+func (s *schoolServer) DeleteSchool(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeleteSchool(uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+type classServer struct {
+	pb.UnimplementedClassServiceServer
+	service services.ClassService
+}
+
+func (s *classServer) ListClasses(ctx context.Context, req *pb.PageRequest) (*pb.ListClassesResponse, error) {
+	offset, limit, err := pageBounds(req)
+	if err != nil {
+		return nil, err
+	}
+	classes, total, err := s.service.ListClasses(offset, limit)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	resp := &pb.ListClassesResponse{Total: total}
+	for i := range classes {
+		resp.Classes = append(resp.Classes, classToProto(&classes[i]))
+	}
+	return resp, nil
+}
+
+func (s *classServer) GetClass(ctx context.Context, req *pb.GetByIDRequest) (*pb.Class, error) {
+	class, err := s.service.GetClassByID(uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return classToProto(class), nil
+}
+
+func (s *classServer) CreateClass(ctx context.Context, req *pb.Class) (*pb.Class, error) {
+	class := classFromProto(req)
+	class.ID = 0
+	if err := s.service.CreateClass(class); err != nil {
+		return nil, toStatus(err)
+	}
+	return classToProto(class), nil
+}
+
+func (s *classServer) UpdateClass(ctx context.Context, req *pb.Class) (*pb.Class, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	class := classFromProto(req)
+	if err := s.service.UpdateClass(class); err != nil {
+		return nil, toStatus(err)
+	}
+	return classToProto(class), nil
+}
+
+func (s *classServer) DeleteClass(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeleteClass(uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+type studentServer struct {
+	pb.UnimplementedStudentServiceServer
+	service services.StudentService
+}
+
+// ListStudents pages through the students table batch by batch, so memory
+// use stays bounded no matter how many rows there are.
+func (s *studentServer) ListStudents(req *pb.ListStudentsRequest, stream pb.StudentService_ListStudentsServer) error {
+	batchSize := int(req.GetBatchSize())
+	if batchSize <= 0 {
+		batchSize = defaultBatchSize
+	}
+	for offset := 0; ; offset += batchSize {
+		if err := stream.Context().Err(); err != nil {
+			return status.FromContextError(err).Err()
+		}
+		students, _, err := s.service.ListStudents(offset, batchSize)
+		if err != nil {
+			return toStatus(err)
+		}
+		for i := range students {
+			if err := stream.Send(studentToProto(&students[i])); err != nil {
+				return err
+			}
+		}
+		if len(students) < batchSize {
+			return nil
+		}
+	}
+}
+
+func (s *studentServer) GetStudent(ctx context.Context, req *pb.GetByIDRequest) (*pb.Student, error) {
+	student, err := s.service.GetStudentByID(uint(req.GetId()))
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return studentToProto(student), nil
+}
+
+func (s *studentServer) CreateStudent(ctx context.Context, req *pb.Student) (*pb.Student, error) {
+	student := studentFromProto(req)
+	student.ID = 0
+	if err := s.service.CreateStudent(student); err != nil {
+		return nil, toStatus(err)
+	}
+	return studentToProto(student), nil
+}
+
+func (s *studentServer) UpdateStudent(ctx context.Context, req *pb.Student) (*pb.Student, error) {
+	if req.GetId() == 0 {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	student := studentFromProto(req)
+	if err := s.service.UpdateStudent(student); err != nil {
+		return nil, toStatus(err)
+	}
+	return studentToProto(student), nil
+}
+
+func (s *studentServer) DeleteStudent(ctx context.Context, req *pb.DeleteRequest) (*emptypb.Empty, error) {
+	if err := s.service.DeleteStudent(uint(req.GetId())); err != nil {
+		return nil, toStatus(err)
+	}
+	return &emptypb.Empty{}, nil
+}
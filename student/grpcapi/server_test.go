@@ -0,0 +1,135 @@
+package grpcapi_test
+
+import (
+	"context"
+	"io"
+	"net"
+	"stu/grpcapi"
+	"stu/grpcapi/pb"
+	"stu/models"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"gorm.io/gorm"
+)
+
+// MockStudentService is a mock implementation of StudentService for testing purposes.
+type MockStudentService struct {
+	mock.Mock
+}
+
+func (m *MockStudentService) GetAllStudents() ([]models.Student, error) {
+	args := m.Called()
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentService) ListStudents(offset, limit int) ([]models.Student, int64, error) {
+	args := m.Called(offset, limit)
+	return args.Get(0).([]models.Student), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockStudentService) GetStudentByID(id uint) (*models.Student, error) {
+	args := m.Called(id)
+	return args.Get(0).(*models.Student), args.Error(1)
+}
+
+func (m *MockStudentService) GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error) {
+	args := m.Called(classIDs)
+	return args.Get(0).([]models.Student), args.Error(1)
+}
+
+func (m *MockStudentService) CreateStudent(student *models.Student) error {
+	args := m.Called(student)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) UpdateStudent(student *models.Student) error {
+	args := m.Called(student)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) DeleteStudent(id uint) error {
+	args := m.Called(id)
+	return args.Error(0)
+}
+
+func (m *MockStudentService) SearchStudents(query string, limit int) ([]services.StudentMatch, error) {
+	args := m.Called(query, limit)
+	return args.Get(0).([]services.StudentMatch), args.Error(1)
+}
+
+func dial(t *testing.T, studentService *MockStudentService) pb.StudentServiceClient {
+	listener := bufconn.Listen(1 << 20)
+	server := grpcapi.NewServer(nil, nil, studentService)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.Nil(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewStudentServiceClient(conn)
+}
+
+func TestStudentServer_ListStudentsStreamsInBatches(t *testing.T) {
+	mockService := new(MockStudentService)
+	mockService.On("ListStudents", 0, 2).Return([]models.Student{{Name: "A"}, {Name: "B"}}, int64(3), nil)
+	mockService.On("ListStudents", 2, 2).Return([]models.Student{{Name: "C"}}, int64(3), nil)
+	client := dial(t, mockService)
+
+	stream, err := client.ListStudents(context.Background(), &pb.ListStudentsRequest{BatchSize: 2})
+	assert.Nil(t, err)
+	var names []string
+	for {
+		student, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.Nil(t, err)
+		names = append(names, student.GetName())
+	}
+
+	assert.Equal(t, []string{"A", "B", "C"}, names)
+	mockService.AssertExpectations(t)
+}
+
+func TestStudentServer_GetStudentNotFound(t *testing.T) {
+	mockService := new(MockStudentService)
+	mockService.On("GetStudentByID", uint(9)).Return((*models.Student)(nil), gorm.ErrRecordNotFound)
+	client := dial(t, mockService)
+
+	_, err := client.GetStudent(context.Background(), &pb.GetByIDRequest{Id: 9})
+
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	mockService.AssertExpectations(t)
+}
+
+func TestStudentServer_CreateStudent(t *testing.T) {
+	mockService := new(MockStudentService)
+	mockService.On("CreateStudent", mock.AnythingOfType("*models.Student")).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Student).ID = 11
+	}).Return(nil)
+	client := dial(t, mockService)
+
+	created, err := client.CreateStudent(context.Background(), &pb.Student{
+		Name:    "Jahnavi",
+		Marks:   90,
+		Address: &pb.Address{City: "Vizag"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(11), created.GetId())
+	assert.Equal(t, "Vizag", created.GetAddress().GetCity())
+	mockService.AssertExpectations(t)
+}
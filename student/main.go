@@ -1,40 +1,52 @@
 package main
 
 import (
+	"fmt"
 	"log"
+	"os"
 	"stu/controllers"
-	"stu/models"
-	"stu/repository"
-	"stu/routes"
-	"stu/services"
 	"testing"
-
-	"gorm.io/driver/postgres"
-	"gorm.io/gorm"
 )
 
+const usage = `Usage: stu <command> [flags]
+
+Commands:
+  serve     start the HTTP server (default)
+  migrate   apply or revert schema migrations: up | down [steps] | status
+  seed      generate synthetic schools, classes and students
+  import    load schools, classes or students from a CSV or JSON file
+  export    write schools, classes or students to a CSV or JSON file
+`
+
+var commands = map[string]func(app *App, args []string) error{
+	"serve":   runServe,
+	"migrate": runMigrate,
+	"seed":    runSeed,
+	"import":  runImport,
+	"export":  runExport,
+}
+
 func main() {
-	dsn := "host=localhost user=postgres password=jahnavi@2003 dbname=stu port=5432 sslmode=disable"
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	name, args := "serve", os.Args[1:]
+	if len(args) > 0 {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" || name == "-h" || name == "--help" {
+		fmt.Print(usage)
+		return
+	}
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	app, err := NewApp()
 	if err != nil {
 		log.Fatal("Failed to connect to the database: ", err)
 	}
-
-	db.AutoMigrate(&models.School{}, &models.Class{}, &models.Student{})
-
-	repo := repository.NewRepository(db)
-	schoolService := services.NewService(repo)
-	classService := services.NewService(repo)
-	studentService := services.NewService(repo)
-
-	schoolController := controllers.NewSchoolController(schoolService)
-	classController := controllers.NewClassController(classService)
-	studentController := controllers.NewStudentController(studentService)
-
-	router := routes.SetupRouter(schoolController, classController, studentController)
-
-	if err := router.Run(":8080"); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
+	if err := command(app, args); err != nil {
+		log.Fatalf("%s: %v", name, err)
 	}
 }
 
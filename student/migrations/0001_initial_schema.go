@@ -0,0 +1,48 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// The initial schema matches what AutoMigrate produced before migrations
+// existed, so databases created that way adopt it without changes.
+
+type school0001 struct {
+	gorm.Model
+	Name    string
+	ClassID uint
+}
+
+func (school0001) TableName() string { return "schools" }
+
+type class0001 struct {
+	gorm.Model
+	ClassID   uint
+	ClassName string
+	StudentID uint
+}
+
+func (class0001) TableName() string { return "classes" }
+
+type student0001 struct {
+	gorm.Model
+	StudentID int
+	Name      string
+	Marks     int
+	Street    string
+	City      string
+	State     string
+}
+
+func (student0001) TableName() string { return "students" }
+
+func init() {
+	register(Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&school0001{}, &class0001{}, &student0001{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&student0001{}, &class0001{}, &school0001{})
+		},
+	})
+}
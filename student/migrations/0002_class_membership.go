@@ -0,0 +1,43 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Classes belong to a school and students to a class, so nested queries can
+// walk school -> classes -> students.
+
+type class0002 struct {
+	SchoolID uint `gorm:"index"`
+}
+
+func (class0002) TableName() string { return "classes" }
+
+type student0002 struct {
+	ClassID uint `gorm:"index"`
+}
+
+func (student0002) TableName() string { return "students" }
+
+func init() {
+	register(Migration{
+		Version: 2,
+		Name:    "class_membership",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&class0002{}, "SchoolID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().CreateIndex(&class0002{}, "SchoolID"); err != nil {
+				return err
+			}
+			if err := tx.Migrator().AddColumn(&student0002{}, "ClassID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&student0002{}, "ClassID")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&student0002{}, "ClassID"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&class0002{}, "SchoolID")
+		},
+	})
+}
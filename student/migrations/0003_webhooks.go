@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type webhookSubscription0003 struct {
+	gorm.Model
+	URL      string
+	Secret   string
+	SchoolID uint   `gorm:"index"`
+	Events   string `gorm:"type:text"`
+	Active   bool
+}
+
+func (webhookSubscription0003) TableName() string { return "webhook_subscriptions" }
+
+type webhookDelivery0003 struct {
+	gorm.Model
+	SubscriptionID uint `gorm:"index"`
+	EventType      string
+	Payload        string `gorm:"type:text"`
+	Status         string `gorm:"index"`
+	Attempts       int
+	NextAttemptAt  time.Time `gorm:"index"`
+	LastStatusCode int
+	LastError      string
+	DeliveredAt    *time.Time
+}
+
+func (webhookDelivery0003) TableName() string { return "webhook_deliveries" }
+
+func init() {
+	register(Migration{
+		Version: 3,
+		Name:    "webhooks",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&webhookSubscription0003{}, &webhookDelivery0003{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&webhookDelivery0003{}, &webhookSubscription0003{})
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type outboxEvent0004 struct {
+	ID          uint64 `gorm:"primaryKey"`
+	Type        string
+	EntityType  string `gorm:"index:idx_outbox_entity"`
+	EntityID    uint   `gorm:"index:idx_outbox_entity"`
+	SchoolID    uint   `gorm:"index"`
+	ClassID     uint   `gorm:"index"`
+	Data        string `gorm:"type:text"`
+	OccurredAt  time.Time
+	PublishedAt *time.Time `gorm:"index"`
+}
+
+func (outboxEvent0004) TableName() string { return "outbox_events" }
+
+func init() {
+	register(Migration{
+		Version: 4,
+		Name:    "outbox",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&outboxEvent0004{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&outboxEvent0004{})
+		},
+	})
+}
@@ -0,0 +1,28 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type rateLimitBucket0005 struct {
+	Key       string `gorm:"primaryKey"`
+	Tokens    float64
+	UpdatedAt time.Time `gorm:"index"`
+}
+
+func (rateLimitBucket0005) TableName() string { return "rate_limit_buckets" }
+
+func init() {
+	register(Migration{
+		Version: 5,
+		Name:    "rate_limits",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&rateLimitBucket0005{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&rateLimitBucket0005{})
+		},
+	})
+}
@@ -0,0 +1,45 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// The search vector weighs name and student ID above the address. Other
+// databases skip this migration and search without an index.
+const studentSearchVector0006 = `setweight(to_tsvector('simple', coalesce(name, '') || ' ' || coalesce(student_id::text, '')), 'A') ||
+	setweight(to_tsvector('simple', coalesce(street, '') || ' ' || coalesce(city, '') || ' ' || coalesce(state, '')), 'B')`
+
+func init() {
+	register(Migration{
+		Version: 6,
+		Name:    "student_search",
+		Up: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != "postgres" {
+				return nil
+			}
+			for _, statement := range []string{
+				`CREATE EXTENSION IF NOT EXISTS pg_trgm`,
+				`ALTER TABLE students ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (` + studentSearchVector0006 + `) STORED`,
+				`CREATE INDEX idx_students_search_vector ON students USING gin (search_vector)`,
+				`CREATE INDEX idx_students_name_trgm ON students USING gin (name gin_trgm_ops)`,
+			} {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Dialector.Name() != "postgres" {
+				return nil
+			}
+			for _, statement := range []string{
+				`DROP INDEX IF EXISTS idx_students_name_trgm`,
+				`ALTER TABLE students DROP COLUMN IF EXISTS search_vector`,
+			} {
+				if err := tx.Exec(statement).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+}
@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type attendanceRecord0007 struct {
+	ID        uint      `gorm:"primaryKey"`
+	StudentID uint      `gorm:"uniqueIndex:idx_attendance_slot,priority:1"`
+	ClassID   uint      `gorm:"index"`
+	Date      time.Time `gorm:"type:date;uniqueIndex:idx_attendance_slot,priority:2;index"`
+	Period    int       `gorm:"uniqueIndex:idx_attendance_slot,priority:3"`
+	Status    string
+	Note      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (attendanceRecord0007) TableName() string { return "attendance_records" }
+
+func init() {
+	register(Migration{
+		Version: 7,
+		Name:    "attendance",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&attendanceRecord0007{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&attendanceRecord0007{})
+		},
+	})
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type teacher0008 struct {
+	gorm.Model
+	Name           string
+	Email          string `gorm:"index"`
+	Phone          string
+	SchoolID       uint   `gorm:"index"`
+	Qualifications string `gorm:"type:text"`
+}
+
+func (teacher0008) TableName() string { return "teachers" }
+
+type teacherAssignment0008 struct {
+	ID        uint   `gorm:"primaryKey"`
+	TeacherID uint   `gorm:"uniqueIndex:idx_teacher_assignment,priority:1"`
+	ClassID   uint   `gorm:"uniqueIndex:idx_teacher_assignment,priority:2;index"`
+	Role      string `gorm:"uniqueIndex:idx_teacher_assignment,priority:3"`
+	Subject   string `gorm:"uniqueIndex:idx_teacher_assignment,priority:4"`
+	CreatedAt time.Time
+}
+
+func (teacherAssignment0008) TableName() string { return "teacher_assignments" }
+
+func init() {
+	register(Migration{
+		Version: 8,
+		Name:    "teachers",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&teacher0008{}, &teacherAssignment0008{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&teacherAssignment0008{}, &teacher0008{})
+		},
+	})
+}
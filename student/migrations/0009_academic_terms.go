@@ -0,0 +1,60 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type academicYear0009 struct {
+	gorm.Model
+	SchoolID  uint `gorm:"index"`
+	Name      string
+	StartDate time.Time `gorm:"type:date"`
+	EndDate   time.Time `gorm:"type:date"`
+}
+
+func (academicYear0009) TableName() string { return "academic_years" }
+
+type term0009 struct {
+	gorm.Model
+	AcademicYearID uint `gorm:"index"`
+	SchoolID       uint `gorm:"index"`
+	Name           string
+	StartDate      time.Time `gorm:"type:date"`
+	EndDate        time.Time `gorm:"type:date"`
+	Active         bool
+}
+
+func (term0009) TableName() string { return "terms" }
+
+type enrollment0009 struct {
+	ID        uint `gorm:"primaryKey"`
+	StudentID uint `gorm:"uniqueIndex:idx_enrollment,priority:1"`
+	TermID    uint `gorm:"uniqueIndex:idx_enrollment,priority:2;index"`
+	ClassID   uint `gorm:"index"`
+	Marks     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (enrollment0009) TableName() string { return "enrollments" }
+
+func init() {
+	register(Migration{
+		Version: 9,
+		Name:    "academic_terms",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().CreateTable(&academicYear0009{}, &term0009{}, &enrollment0009{}); err != nil {
+				return err
+			}
+			if tx.Dialector.Name() == "postgres" {
+				return tx.Exec(`CREATE UNIQUE INDEX idx_terms_active ON terms (school_id) WHERE active AND deleted_at IS NULL`).Error
+			}
+			return nil
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&enrollment0009{}, &term0009{}, &academicYear0009{})
+		},
+	})
+}
@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type promotionPlan0010 struct {
+	ID             uint `gorm:"primaryKey"`
+	SchoolID       uint `gorm:"index"`
+	AcademicYearID uint `gorm:"index"`
+	PassMark       int
+	Status         string
+	AppliedAt      *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+func (promotionPlan0010) TableName() string { return "promotion_plans" }
+
+type promotionEntry0010 struct {
+	ID          uint `gorm:"primaryKey"`
+	PlanID      uint `gorm:"uniqueIndex:idx_promotion_entry,priority:1"`
+	StudentID   uint `gorm:"uniqueIndex:idx_promotion_entry,priority:2"`
+	FromClassID uint
+	ToClassID   uint
+	Marks       float64
+	Outcome     string
+	Overridden  bool
+}
+
+func (promotionEntry0010) TableName() string { return "promotion_entries" }
+
+func init() {
+	register(Migration{
+		Version: 10,
+		Name:    "promotions",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&promotionPlan0010{}, &promotionEntry0010{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&promotionEntry0010{}, &promotionPlan0010{})
+		},
+	})
+}
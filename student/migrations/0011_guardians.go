@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type guardian0011 struct {
+	gorm.Model
+	Name          string
+	Email         string `gorm:"index"`
+	Phones        string `gorm:"type:text"`
+	AddressStreet string
+	AddressCity   string
+	AddressState  string
+}
+
+func (guardian0011) TableName() string { return "guardians" }
+
+type studentGuardian0011 struct {
+	ID           uint `gorm:"primaryKey"`
+	StudentID    uint `gorm:"uniqueIndex:idx_student_guardian,priority:1"`
+	GuardianID   uint `gorm:"uniqueIndex:idx_student_guardian,priority:2;index"`
+	Relationship string
+	Priority     int
+	HasCustody   bool
+	CanPickup    bool
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+func (studentGuardian0011) TableName() string { return "student_guardians" }
+
+func init() {
+	register(Migration{
+		Version: 11,
+		Name:    "guardians",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&guardian0011{}, &studentGuardian0011{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&studentGuardian0011{}, &guardian0011{})
+		},
+	})
+}
@@ -0,0 +1,99 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type feeItem0012 struct {
+	gorm.Model
+	SchoolID uint `gorm:"index"`
+	ClassID  uint `gorm:"index"`
+	Name     string
+	Amount   int64
+}
+
+func (feeItem0012) TableName() string { return "fee_items" }
+
+type discount0012 struct {
+	gorm.Model
+	StudentID uint `gorm:"index"`
+	FeeItemID uint
+	Kind      string
+	Name      string
+	Percent   int
+	Amount    int64
+}
+
+func (discount0012) TableName() string { return "discounts" }
+
+type invoice0012 struct {
+	ID        uint      `gorm:"primaryKey"`
+	Number    string    `gorm:"uniqueIndex"`
+	StudentID uint      `gorm:"uniqueIndex:idx_invoice_term,priority:1"`
+	TermID    uint      `gorm:"uniqueIndex:idx_invoice_term,priority:2;index"`
+	SchoolID  uint      `gorm:"index"`
+	ClassID   uint      `gorm:"index"`
+	DueDate   time.Time `gorm:"type:date"`
+	Subtotal  int64
+	Discount  int64
+	Total     int64
+	CreatedAt time.Time
+}
+
+func (invoice0012) TableName() string { return "invoices" }
+
+type invoiceLine0012 struct {
+	ID          uint `gorm:"primaryKey"`
+	InvoiceID   uint `gorm:"index"`
+	FeeItemID   uint
+	DiscountID  uint
+	Description string
+	Amount      int64
+}
+
+func (invoiceLine0012) TableName() string { return "invoice_lines" }
+
+type payment0012 struct {
+	ID        uint `gorm:"primaryKey"`
+	InvoiceID uint `gorm:"index"`
+	StudentID uint `gorm:"index"`
+	Amount    int64
+	Method    string
+	Reference string
+	PaidAt    time.Time
+	CreatedAt time.Time
+}
+
+func (payment0012) TableName() string { return "payments" }
+
+type ledgerEntry0012 struct {
+	ID        uint   `gorm:"primaryKey"`
+	Journal   string `gorm:"index"`
+	Account   string `gorm:"index"`
+	StudentID uint   `gorm:"index"`
+	InvoiceID uint   `gorm:"index"`
+	PaymentID uint
+	Debit     int64
+	Credit    int64
+	Memo      string
+	CreatedAt time.Time
+}
+
+func (ledgerEntry0012) TableName() string { return "ledger_entries" }
+
+func init() {
+	register(Migration{
+		Version: 12,
+		Name:    "fees",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&feeItem0012{}, &discount0012{}, &invoice0012{},
+				&invoiceLine0012{}, &payment0012{}, &ledgerEntry0012{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&ledgerEntry0012{}, &payment0012{}, &invoiceLine0012{},
+				&invoice0012{}, &discount0012{}, &feeItem0012{})
+		},
+	})
+}
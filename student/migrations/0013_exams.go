@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type exam0013 struct {
+	gorm.Model
+	ClassID  uint `gorm:"index"`
+	TermID   uint `gorm:"index"`
+	Subject  string
+	Name     string
+	Date     time.Time `gorm:"type:date"`
+	MaxMarks int
+	Weight   int
+}
+
+func (exam0013) TableName() string { return "exams" }
+
+type examResult0013 struct {
+	ID        uint `gorm:"primaryKey"`
+	ExamID    uint `gorm:"uniqueIndex:idx_exam_result,priority:1"`
+	StudentID uint `gorm:"uniqueIndex:idx_exam_result,priority:2;index"`
+	Marks     int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func (examResult0013) TableName() string { return "exam_results" }
+
+func init() {
+	register(Migration{
+		Version: 13,
+		Name:    "exams",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&exam0013{}, &examResult0013{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&examResult0013{}, &exam0013{})
+		},
+	})
+}
@@ -0,0 +1,48 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Exam marks move through draft, submitted, approved and published;
+// existing exams start as drafts.
+
+type exam0014 struct {
+	Status string `gorm:"not null;default:draft"`
+}
+
+func (exam0014) TableName() string { return "exams" }
+
+type examStatusChange0014 struct {
+	ID        uint `gorm:"primaryKey"`
+	ExamID    uint `gorm:"index"`
+	Action    string
+	From      string
+	To        string
+	Actor     string
+	Reason    string
+	CreatedAt time.Time
+}
+
+func (examStatusChange0014) TableName() string { return "exam_status_changes" }
+
+func init() {
+	register(Migration{
+		Version: 14,
+		Name:    "marks_publishing",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&exam0014{}, "Status"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&examStatusChange0014{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&examStatusChange0014{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&exam0014{}, "Status")
+		},
+	})
+}
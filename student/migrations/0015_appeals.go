@@ -0,0 +1,54 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type appeal0015 struct {
+	gorm.Model
+	SchoolID      uint `gorm:"index"`
+	ExamID        uint `gorm:"index"`
+	StudentID     uint `gorm:"index"`
+	GuardianID    *uint
+	Reason        string
+	Status        string `gorm:"index"`
+	Reviewer      string
+	AssignedAt    *time.Time
+	DueAt         time.Time
+	OriginalMarks int
+	Outcome       string
+	RevisedMarks  *int
+	Resolution    string
+	ResolvedAt    *time.Time
+}
+
+func (appeal0015) TableName() string { return "appeals" }
+
+type markChange0015 struct {
+	ID        uint `gorm:"primaryKey"`
+	ExamID    uint `gorm:"index"`
+	StudentID uint `gorm:"index"`
+	From      int
+	To        int
+	Actor     string
+	Reason    string
+	AppealID  *uint
+	CreatedAt time.Time
+}
+
+func (markChange0015) TableName() string { return "mark_changes" }
+
+func init() {
+	register(Migration{
+		Version: 15,
+		Name:    "appeals",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().CreateTable(&appeal0015{}, &markChange0015{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&markChange0015{}, &appeal0015{})
+		},
+	})
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Classes get a capacity, zero meaning unlimited, and a waitlist of
+// students waiting for a seat.
+
+type class0016 struct {
+	Capacity int `gorm:"not null;default:0"`
+}
+
+func (class0016) TableName() string { return "classes" }
+
+type waitlistEntry0016 struct {
+	ID        uint `gorm:"primaryKey"`
+	ClassID   uint `gorm:"uniqueIndex:idx_waitlist_entry,priority:1"`
+	StudentID uint `gorm:"uniqueIndex:idx_waitlist_entry,priority:2;index"`
+	CreatedAt time.Time
+}
+
+func (waitlistEntry0016) TableName() string { return "waitlist_entries" }
+
+func init() {
+	register(Migration{
+		Version: 16,
+		Name:    "class_capacity",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&class0016{}, "Capacity"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateTable(&waitlistEntry0016{})
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropTable(&waitlistEntry0016{}); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&class0016{}, "Capacity")
+		},
+	})
+}
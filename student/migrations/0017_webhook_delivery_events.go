@@ -0,0 +1,32 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Webhook deliveries record the outbox event they carry, so an event the
+// relay publishes again is not queued twice for the same subscription.
+
+type webhookDelivery0017 struct {
+	SubscriptionID uint    `gorm:"uniqueIndex:idx_webhook_delivery_event,priority:1"`
+	EventID        *uint64 `gorm:"uniqueIndex:idx_webhook_delivery_event,priority:2"`
+}
+
+func (webhookDelivery0017) TableName() string { return "webhook_deliveries" }
+
+func init() {
+	register(Migration{
+		Version: 17,
+		Name:    "webhook_delivery_events",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&webhookDelivery0017{}, "EventID"); err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&webhookDelivery0017{}, "idx_webhook_delivery_event")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&webhookDelivery0017{}, "idx_webhook_delivery_event"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&webhookDelivery0017{}, "EventID")
+		},
+	})
+}
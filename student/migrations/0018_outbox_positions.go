@@ -0,0 +1,35 @@
+package migrations
+
+import "gorm.io/gorm"
+
+// Outbox events get a position assigned after they commit, which readers
+// use as their cursor instead of the ID taken when the event was written.
+// Existing events keep their ID as their position.
+
+type outboxEvent0018 struct {
+	Position *uint64 `gorm:"uniqueIndex:idx_outbox_events_position"`
+}
+
+func (outboxEvent0018) TableName() string { return "outbox_events" }
+
+func init() {
+	register(Migration{
+		Version: 18,
+		Name:    "outbox_positions",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&outboxEvent0018{}, "Position"); err != nil {
+				return err
+			}
+			if err := tx.Exec("UPDATE outbox_events SET position = id").Error; err != nil {
+				return err
+			}
+			return tx.Migrator().CreateIndex(&outboxEvent0018{}, "idx_outbox_events_position")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropIndex(&outboxEvent0018{}, "idx_outbox_events_position"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropColumn(&outboxEvent0018{}, "Position")
+		},
+	})
+}
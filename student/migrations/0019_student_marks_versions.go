@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Students record when their marks last changed, which versions the marks
+// in the marks grid. Existing students take their last update.
+
+type student0019 struct {
+	MarksUpdatedAt *time.Time
+}
+
+func (student0019) TableName() string { return "students" }
+
+func init() {
+	register(Migration{
+		Version: 19,
+		Name:    "student_marks_versions",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AddColumn(&student0019{}, "MarksUpdatedAt"); err != nil {
+				return err
+			}
+			return tx.Exec("UPDATE students SET marks_updated_at = updated_at").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&student0019{}, "MarksUpdatedAt")
+		},
+	})
+}
@@ -0,0 +1,29 @@
+package migrations
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Graduates keep their student record: promotions clear their class and
+// record when they graduated instead of deleting them.
+
+type student0020 struct {
+	GraduatedAt *time.Time
+}
+
+func (student0020) TableName() string { return "students" }
+
+func init() {
+	register(Migration{
+		Version: 20,
+		Name:    "student_graduations",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AddColumn(&student0020{}, "GraduatedAt")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&student0020{}, "GraduatedAt")
+		},
+	})
+}
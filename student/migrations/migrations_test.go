@@ -0,0 +1,18 @@
+package migrations
+
+import "testing"
+
+func TestAllOrderedAndComplete(t *testing.T) {
+	all := All()
+	if len(all) == 0 {
+		t.Fatal("expected at least one migration")
+	}
+	for i, m := range all {
+		if m.Up == nil || m.Down == nil {
+			t.Errorf("migration %d_%s must define both Up and Down", m.Version, m.Name)
+		}
+		if i > 0 && all[i-1].Version >= m.Version {
+			t.Errorf("migration %d is not ordered after %d", m.Version, all[i-1].Version)
+		}
+	}
+}
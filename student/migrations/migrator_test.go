@@ -0,0 +1,174 @@
+package migrations
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type widget struct {
+	ID   uint
+	Name string
+}
+
+type gadget struct {
+	ID uint
+}
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrations.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func createTable(model interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error { return tx.Migrator().CreateTable(model) }
+}
+
+func dropTable(model interface{}) func(tx *gorm.DB) error {
+	return func(tx *gorm.DB) error { return tx.Migrator().DropTable(model) }
+}
+
+func testMigrations() []Migration {
+	return []Migration{
+		{Version: 1, Name: "widgets", Up: createTable(&widget{}), Down: dropTable(&widget{})},
+		{Version: 2, Name: "gadgets", Up: createTable(&gadget{}), Down: dropTable(&gadget{})},
+	}
+}
+
+func appliedVersions(t *testing.T, m *Migrator) []int64 {
+	t.Helper()
+	statuses, err := m.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var versions []int64
+	for _, status := range statuses {
+		if status.Applied {
+			versions = append(versions, status.Version)
+		}
+	}
+	return versions
+}
+
+func TestMigratorUpAppliesPendingInOrder(t *testing.T) {
+	db := openTestDB(t)
+	m := &Migrator{db: db, migrations: testMigrations()}
+
+	n, err := m.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 2 {
+		t.Errorf("applied %d migrations, want 2", n)
+	}
+	if !db.Migrator().HasTable(&widget{}) || !db.Migrator().HasTable(&gadget{}) {
+		t.Error("expected both tables to exist")
+	}
+	if got := appliedVersions(t, m); len(got) != 2 {
+		t.Errorf("applied versions = %v, want [1 2]", got)
+	}
+}
+
+func TestMigratorUpSkipsApplied(t *testing.T) {
+	db := openTestDB(t)
+	calls := 0
+	migrations := testMigrations()
+	up := migrations[0].Up
+	migrations[0].Up = func(tx *gorm.DB) error {
+		calls++
+		return up(tx)
+	}
+	m := &Migrator{db: db, migrations: migrations}
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := m.Up()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 || calls != 1 {
+		t.Errorf("second Up applied %d migrations and ran the first %d times, want 0 and 1", n, calls)
+	}
+
+	m.migrations = append(m.migrations, Migration{
+		Version: 3,
+		Name:    "widget_names",
+		Up:      func(tx *gorm.DB) error { return tx.Create(&widget{Name: "seeded"}).Error },
+		Down:    func(tx *gorm.DB) error { return tx.Where("name = ?", "seeded").Delete(&widget{}).Error },
+	})
+	if n, err = m.Up(); err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 || calls != 1 {
+		t.Errorf("Up after adding a migration applied %d and ran the first %d times, want 1 and 1", n, calls)
+	}
+}
+
+func TestMigratorUpRollsBackFailedMigration(t *testing.T) {
+	db := openTestDB(t)
+	failure := errors.New("boom")
+	migrations := testMigrations()
+	migrations[1].Up = func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&gadget{}); err != nil {
+			return err
+		}
+		return failure
+	}
+	m := &Migrator{db: db, migrations: migrations}
+
+	n, err := m.Up()
+	if !errors.Is(err, failure) {
+		t.Fatalf("Up error = %v, want %v", err, failure)
+	}
+	if n != 1 {
+		t.Errorf("applied %d migrations, want 1", n)
+	}
+	if db.Migrator().HasTable(&gadget{}) {
+		t.Error("the failed migration's table should have been rolled back")
+	}
+	if got := appliedVersions(t, m); len(got) != 1 || got[0] != 1 {
+		t.Errorf("applied versions = %v, want [1]", got)
+	}
+
+	m.migrations = testMigrations()
+	if n, err = m.Up(); err != nil || n != 1 {
+		t.Errorf("retrying applied %d migrations with error %v, want 1 and nil", n, err)
+	}
+}
+
+func TestMigratorDownRevertsNewestFirst(t *testing.T) {
+	db := openTestDB(t)
+	m := &Migrator{db: db, migrations: testMigrations()}
+	if _, err := m.Up(); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := m.Down(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1 {
+		t.Errorf("reverted %d migrations, want 1", n)
+	}
+	if db.Migrator().HasTable(&gadget{}) || !db.Migrator().HasTable(&widget{}) {
+		t.Error("expected only the newest migration to be reverted")
+	}
+	if got := appliedVersions(t, m); len(got) != 1 || got[0] != 1 {
+		t.Errorf("applied versions = %v, want [1]", got)
+	}
+
+	if n, err = m.Down(5); err != nil || n != 1 {
+		t.Errorf("Down(5) reverted %d migrations with error %v, want 1 and nil", n, err)
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AcademicYear is a school year of a school, e.g. "2024-25".
+type AcademicYear struct {
+	gorm.Model
+	SchoolID  uint      `gorm:"index" json:"school_id"`
+	Name      string    `json:"name"`
+	StartDate time.Time `gorm:"type:date" json:"start_date"`
+	EndDate   time.Time `gorm:"type:date" json:"end_date"`
+}
+
+// Term divides an academic year. Each school has at most one active term,
+// the one current marks and class membership are recorded against.
+type Term struct {
+	gorm.Model
+	AcademicYearID uint      `gorm:"index" json:"academic_year_id"`
+	SchoolID       uint      `gorm:"index" json:"school_id"`
+	Name           string    `json:"name"`
+	StartDate      time.Time `gorm:"type:date" json:"start_date"`
+	EndDate        time.Time `gorm:"type:date" json:"end_date"`
+	Active         bool      `json:"active"`
+}
+
+// Enrollment is a student's class and marks in a term. The enrollment in
+// the active term follows the student record; those of earlier terms keep
+// their history.
+type Enrollment struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	StudentID uint      `gorm:"uniqueIndex:idx_enrollment,priority:1" json:"student_id"`
+	TermID    uint      `gorm:"uniqueIndex:idx_enrollment,priority:2;index" json:"term_id"`
+	ClassID   uint      `gorm:"index" json:"class_id"`
+	Marks     int       `json:"marks"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Term      *Term     `gorm:"-" json:"term,omitempty"`
+}
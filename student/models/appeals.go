@@ -0,0 +1,53 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Appeal contests a student's published marks in an exam. It is open until
+// a reviewer is assigned, in review until they resolve it, and due by
+// DueAt.
+type Appeal struct {
+	gorm.Model
+	SchoolID  uint `gorm:"index" json:"school_id"`
+	ExamID    uint `gorm:"index" json:"exam_id"`
+	StudentID uint `gorm:"index" json:"student_id"`
+	// GuardianID is set when a guardian rather than the student appealed.
+	GuardianID    *uint      `json:"guardian_id"`
+	Reason        string     `json:"reason"`
+	Status        string     `gorm:"index" json:"status"`
+	Reviewer      string     `json:"reviewer"`
+	AssignedAt    *time.Time `json:"assigned_at"`
+	DueAt         time.Time  `json:"due_at"`
+	OriginalMarks int        `json:"original_marks"`
+	Outcome       string     `json:"outcome"`
+	RevisedMarks  *int       `json:"revised_marks"`
+	Resolution    string     `json:"resolution"`
+	ResolvedAt    *time.Time `json:"resolved_at"`
+	// Overdue is computed when the appeal is read.
+	Overdue bool `gorm:"-" json:"overdue"`
+}
+
+const (
+	AppealOpen     = "open"
+	AppealInReview = "in_review"
+	AppealResolved = "resolved"
+
+	AppealUpheld   = "upheld"
+	AppealRejected = "rejected"
+)
+
+// MarkChange is the audit record of a change to published marks.
+type MarkChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ExamID    uint      `gorm:"index" json:"exam_id"`
+	StudentID uint      `gorm:"index" json:"student_id"`
+	From      int       `json:"from"`
+	To        int       `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	AppealID  *uint     `json:"appeal_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
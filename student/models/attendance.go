@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceLate    = "late"
+	AttendanceExcused = "excused"
+)
+
+// AttendanceStatuses lists the valid AttendanceRecord statuses.
+var AttendanceStatuses = []string{AttendancePresent, AttendanceAbsent, AttendanceLate, AttendanceExcused}
+
+// AttendanceRecord is a student's attendance in a class for one period of
+// a day; period 0 records the whole day. Recording the same student, date
+// and period again replaces the record.
+type AttendanceRecord struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	StudentID uint      `gorm:"uniqueIndex:idx_attendance_slot,priority:1" json:"student_id"`
+	ClassID   uint      `gorm:"index" json:"class_id"`
+	Date      time.Time `gorm:"type:date;uniqueIndex:idx_attendance_slot,priority:2;index" json:"date"`
+	Period    int       `gorm:"uniqueIndex:idx_attendance_slot,priority:3" json:"period"`
+	Status    string    `json:"status"`
+	Note      string    `json:"note"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Exam is an assessed component of a subject taught to a class, such as a
+// midterm, a final or coursework. Weight is its percentage of the subject
+// score; the weights of a class's exams in one subject and term add up to
+// at most 100.
+type Exam struct {
+	gorm.Model
+	ClassID  uint      `gorm:"index" json:"class_id"`
+	TermID   uint      `gorm:"index" json:"term_id"`
+	Subject  string    `json:"subject"`
+	Name     string    `json:"name"`
+	Date     time.Time `gorm:"type:date" json:"date"`
+	MaxMarks int       `json:"max_marks"`
+	Weight   int       `json:"weight"`
+	// Status is where the exam's marks are in the publishing workflow.
+	// Students only see published marks.
+	Status string `gorm:"not null;default:draft" json:"status"`
+}
+
+const (
+	MarksDraft     = "draft"
+	MarksSubmitted = "submitted"
+	MarksApproved  = "approved"
+	MarksPublished = "published"
+)
+
+// ExamStatusChange records a step of an exam's marks through the
+// publishing workflow.
+type ExamStatusChange struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ExamID    uint      `gorm:"index" json:"exam_id"`
+	Action    string    `json:"action"`
+	From      string    `json:"from"`
+	To        string    `json:"to"`
+	Actor     string    `json:"actor"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ExamResult is a student's marks in an exam, out of its MaxMarks.
+type ExamResult struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ExamID    uint      `gorm:"uniqueIndex:idx_exam_result,priority:1" json:"exam_id"`
+	StudentID uint      `gorm:"uniqueIndex:idx_exam_result,priority:2;index" json:"student_id"`
+	Marks     int       `json:"marks"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
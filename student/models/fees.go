@@ -0,0 +1,101 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Amounts in the fees subsystem are integers in the currency's minor unit,
+// e.g. cents.
+
+const (
+	DiscountKindDiscount    = "discount"
+	DiscountKindScholarship = "scholarship"
+)
+
+// Ledger accounts. Receivable entries carry the student they are owed by.
+const (
+	AccountReceivable = "receivable"
+	AccountFeeIncome  = "fee_income"
+	AccountDiscounts  = "discounts"
+	AccountCash       = "cash"
+)
+
+// FeeItem is a line of a school's fee structure, charged every term to
+// the students of ClassID, or of every class when ClassID is zero.
+type FeeItem struct {
+	gorm.Model
+	SchoolID uint   `gorm:"index" json:"school_id"`
+	ClassID  uint   `gorm:"index" json:"class_id"`
+	Name     string `json:"name"`
+	Amount   int64  `json:"amount"`
+}
+
+// Discount reduces the fees of a student: by Percent of what is left of
+// the fee item's charge, or of all fees when FeeItemID is zero, plus a
+// fixed Amount.
+type Discount struct {
+	gorm.Model
+	StudentID uint   `gorm:"index" json:"student_id"`
+	FeeItemID uint   `json:"fee_item_id"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	Percent   int    `json:"percent"`
+	Amount    int64  `json:"amount"`
+}
+
+// Invoice bills a student for a term. Paid and Balance are computed from
+// the payments when the invoice is read.
+type Invoice struct {
+	ID        uint          `gorm:"primaryKey" json:"id"`
+	Number    string        `gorm:"uniqueIndex" json:"number"`
+	StudentID uint          `gorm:"uniqueIndex:idx_invoice_term,priority:1" json:"student_id"`
+	TermID    uint          `gorm:"uniqueIndex:idx_invoice_term,priority:2;index" json:"term_id"`
+	SchoolID  uint          `gorm:"index" json:"school_id"`
+	ClassID   uint          `gorm:"index" json:"class_id"`
+	DueDate   time.Time     `gorm:"type:date" json:"due_date"`
+	Subtotal  int64         `json:"subtotal"`
+	Discount  int64         `json:"discount"`
+	Total     int64         `json:"total"`
+	CreatedAt time.Time     `json:"created_at"`
+	Lines     []InvoiceLine `gorm:"-" json:"lines,omitempty"`
+	Paid      int64         `gorm:"-" json:"paid"`
+	Balance   int64         `gorm:"-" json:"balance"`
+}
+
+// InvoiceLine is a fee charged by an invoice, or a negative discount line.
+type InvoiceLine struct {
+	ID          uint   `gorm:"primaryKey" json:"id"`
+	InvoiceID   uint   `gorm:"index" json:"invoice_id"`
+	FeeItemID   uint   `json:"fee_item_id,omitempty"`
+	DiscountID  uint   `json:"discount_id,omitempty"`
+	Description string `json:"description"`
+	Amount      int64  `json:"amount"`
+}
+
+type Payment struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	InvoiceID uint      `gorm:"index" json:"invoice_id"`
+	StudentID uint      `gorm:"index" json:"student_id"`
+	Amount    int64     `json:"amount"`
+	Method    string    `json:"method"`
+	Reference string    `json:"reference"`
+	PaidAt    time.Time `json:"paid_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LedgerEntry is one side of a posting. The entries of a posting share a
+// Journal reference and their debits equal their credits.
+type LedgerEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Journal   string    `gorm:"index" json:"journal"`
+	Account   string    `gorm:"index" json:"account"`
+	StudentID uint      `gorm:"index" json:"student_id,omitempty"`
+	InvoiceID uint      `gorm:"index" json:"invoice_id,omitempty"`
+	PaymentID uint      `json:"payment_id,omitempty"`
+	Debit     int64     `json:"debit"`
+	Credit    int64     `json:"credit"`
+	Memo      string    `json:"memo"`
+	CreatedAt time.Time `json:"created_at"`
+}
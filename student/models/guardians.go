@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Guardian is a parent, guardian or emergency contact. One guardian can be
+// linked to several students, so siblings share their parents.
+type Guardian struct {
+	gorm.Model
+	Name    string     `json:"name"`
+	Email   string     `gorm:"index" json:"email"`
+	Phones  StringList `gorm:"type:text" json:"phones"`
+	Address Address    `gorm:"embedded;embeddedPrefix:address_" json:"address"`
+}
+
+// StudentGuardian links a guardian to a student. Priority orders the
+// student's emergency contacts, lowest first.
+type StudentGuardian struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	StudentID    uint      `gorm:"uniqueIndex:idx_student_guardian,priority:1" json:"student_id"`
+	GuardianID   uint      `gorm:"uniqueIndex:idx_student_guardian,priority:2;index" json:"guardian_id"`
+	Relationship string    `json:"relationship"`
+	Priority     int       `json:"priority"`
+	HasCustody   bool      `json:"has_custody"`
+	CanPickup    bool      `json:"can_pickup"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+	Guardian     *Guardian `gorm:"-" json:"guardian,omitempty"`
+}
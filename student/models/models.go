@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -18,7 +19,20 @@ type Class struct {
 	ClassID   uint      `json:"class_id"`
 	ClassName string    `json:"class_name"`
 	StudentID uint      `json:"student_id"`
+	SchoolID  uint      `gorm:"index" json:"school_id"`
 	Students  []Student `gorm:"-" json:"-"`
+	// Capacity is the maximum number of students; zero means unlimited.
+	Capacity int `gorm:"not null;default:0" json:"capacity"`
+	// Occupancy is computed when classes are read through the service.
+	Occupancy *Occupancy `gorm:"-" json:"occupancy,omitempty"`
+}
+
+// Occupancy counts a class's students and the students waiting for a
+// seat. Available is left out for classes without a capacity.
+type Occupancy struct {
+	Enrolled   int64  `json:"enrolled"`
+	Waitlisted int64  `json:"waitlisted"`
+	Available  *int64 `json:"available,omitempty"`
 }
 
 type Student struct {
@@ -27,6 +41,24 @@ type Student struct {
 	Name      string  `json:"name"`
 	Marks     int     `json:"marks"`
 	Address   Address `gorm:"embedded;type:jsonb" json:"address"`
+	ClassID   uint    `gorm:"index" json:"class_id"`
+	// MarksUpdatedAt is when the marks last changed; it versions them in
+	// the marks grid, so other edits of the student do not conflict.
+	MarksUpdatedAt *time.Time `json:"marks_updated_at"`
+	// GraduatedAt is when a promotion graduated the student, who then
+	// belongs to no class.
+	GraduatedAt *time.Time `json:"graduated_at,omitempty"`
+	// Percentile is computed when a single student is read.
+	Percentile *Percentile `gorm:"-" json:"percentile,omitempty"`
+}
+
+// Percentile places a student's marks among the students of their class,
+// their school and the whole district, from 0 to 100: the share of
+// students with lower marks, counting ties as half.
+type Percentile struct {
+	Class    *float64 `json:"class,omitempty"`
+	School   *float64 `json:"school,omitempty"`
+	District float64  `json:"district"`
 }
 
 type Address struct {
@@ -0,0 +1,21 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event written in the same transaction as the
+// change it describes. IDs are taken when events are written, so they can
+// commit out of order; Position is assigned once an event has committed and
+// orders events by commit. The relay publishes unpublished rows in Position
+// order.
+type OutboxEvent struct {
+	ID          uint64     `gorm:"primaryKey" json:"id"`
+	Position    *uint64    `gorm:"uniqueIndex" json:"position"`
+	Type        string     `json:"type"`
+	EntityType  string     `gorm:"index:idx_outbox_entity" json:"entity_type"`
+	EntityID    uint       `gorm:"index:idx_outbox_entity" json:"entity_id"`
+	SchoolID    uint       `gorm:"index" json:"school_id"`
+	ClassID     uint       `gorm:"index" json:"class_id"`
+	Data        string     `gorm:"type:text" json:"data"`
+	OccurredAt  time.Time  `json:"occurred_at"`
+	PublishedAt *time.Time `gorm:"index" json:"published_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+const (
+	PromotionDraft   = "draft"
+	PromotionApplied = "applied"
+
+	OutcomePromoted  = "promoted"
+	OutcomeRepeated  = "repeated"
+	OutcomeGraduated = "graduated"
+)
+
+// PromotionPlan proposes where every student of a school goes at the end
+// of an academic year. Plans are drafted, reviewed and then applied once.
+type PromotionPlan struct {
+	ID             uint             `gorm:"primaryKey" json:"id"`
+	SchoolID       uint             `gorm:"index" json:"school_id"`
+	AcademicYearID uint             `gorm:"index" json:"academic_year_id"`
+	PassMark       int              `json:"pass_mark"`
+	Status         string           `json:"status"`
+	AppliedAt      *time.Time       `json:"applied_at"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+	Entries        []PromotionEntry `gorm:"-" json:"entries,omitempty"`
+}
+
+// PromotionEntry is the outcome proposed for one student. ToClassID is
+// the student's class after the plan is applied; it is zero for
+// graduates, who leave the school roll.
+type PromotionEntry struct {
+	ID          uint    `gorm:"primaryKey" json:"id"`
+	PlanID      uint    `gorm:"uniqueIndex:idx_promotion_entry,priority:1" json:"plan_id"`
+	StudentID   uint    `gorm:"uniqueIndex:idx_promotion_entry,priority:2" json:"student_id"`
+	FromClassID uint    `json:"from_class_id"`
+	ToClassID   uint    `json:"to_class_id"`
+	Marks       float64 `json:"marks"`
+	Outcome     string  `json:"outcome"`
+	Overridden  bool    `json:"overridden"`
+}
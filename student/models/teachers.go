@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	RoleHomeroom = "homeroom"
+	RoleSubject  = "subject"
+)
+
+// Teacher is a member of staff who can be assigned to classes.
+type Teacher struct {
+	gorm.Model
+	Name           string     `json:"name"`
+	Email          string     `gorm:"index" json:"email"`
+	Phone          string     `json:"phone"`
+	SchoolID       uint       `gorm:"index" json:"school_id"`
+	Qualifications StringList `gorm:"type:text" json:"qualifications"`
+}
+
+// TeacherAssignment assigns a teacher to a class, either as its homeroom
+// teacher or to teach a subject. A class has at most one homeroom teacher.
+type TeacherAssignment struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	TeacherID uint      `gorm:"uniqueIndex:idx_teacher_assignment,priority:1" json:"teacher_id"`
+	ClassID   uint      `gorm:"uniqueIndex:idx_teacher_assignment,priority:2;index" json:"class_id"`
+	Role      string    `gorm:"uniqueIndex:idx_teacher_assignment,priority:3" json:"role"`
+	Subject   string    `gorm:"uniqueIndex:idx_teacher_assignment,priority:4" json:"subject"`
+	CreatedAt time.Time `json:"created_at"`
+	Class     *Class    `gorm:"-" json:"class,omitempty"`
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// WaitlistEntry queues a student for a seat in a full class. Seats go to
+// the entries in the order they were created.
+type WaitlistEntry struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	ClassID   uint      `gorm:"uniqueIndex:idx_waitlist_entry,priority:1" json:"class_id"`
+	StudentID uint      `gorm:"uniqueIndex:idx_waitlist_entry,priority:2;index" json:"student_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
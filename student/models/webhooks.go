@@ -0,0 +1,84 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookSubscription registers a URL to receive signed event payloads.
+// A zero SchoolID receives events from every school and empty Events
+// receives every event type.
+type WebhookSubscription struct {
+	gorm.Model
+	URL      string     `json:"url"`
+	Secret   string     `json:"secret,omitempty"`
+	SchoolID uint       `gorm:"index" json:"school_id"`
+	Events   StringList `gorm:"type:text" json:"events"`
+	Active   bool       `json:"active"`
+}
+
+// Matches reports whether an event of eventType scoped to schoolID should
+// be delivered to the subscription.
+func (w *WebhookSubscription) Matches(eventType string, schoolID uint) bool {
+	if !w.Active {
+		return false
+	}
+	if w.SchoolID != 0 && w.SchoolID != schoolID {
+		return false
+	}
+	return len(w.Events) == 0 || slices.Contains(w.Events, eventType)
+}
+
+// WebhookDelivery is one queued attempt to deliver an event to a
+// subscription. Deliveries that exhaust their retries are marked dead and
+// stay in the table as the dead-letter list until replayed. EventID is the
+// outbox ID of the event, so an event is queued once per subscription.
+type WebhookDelivery struct {
+	gorm.Model
+	SubscriptionID uint       `gorm:"index;uniqueIndex:idx_webhook_delivery_event,priority:1" json:"subscription_id"`
+	EventID        *uint64    `gorm:"uniqueIndex:idx_webhook_delivery_event,priority:2" json:"event_id"`
+	EventType      string     `json:"event_type"`
+	Payload        string     `gorm:"type:text" json:"payload"`
+	Status         string     `gorm:"index" json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastStatusCode int        `json:"last_status_code"`
+	LastError      string     `json:"last_error"`
+	DeliveredAt    *time.Time `json:"delivered_at"`
+}
+
+// StringList is stored as a comma-separated text column.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	return strings.Join(l, ","), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	var s string
+	switch v := value.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into StringList", value)
+	}
+	*l = nil
+	if s != "" {
+		*l = strings.Split(s, ",")
+	}
+	return nil
+}
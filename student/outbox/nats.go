@@ -0,0 +1,106 @@
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"stu/events"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// StreamName is the JetStream stream events are published to.
+	StreamName = "STU_EVENTS"
+	// SubjectPrefix is followed by the event type, e.g.
+	// "stu.events.student.created".
+	SubjectPrefix = "stu.events."
+)
+
+// StartEmbeddedNATS runs a local NATS server with JetStream enabled,
+// storing streams under storeDir. Callers should Shutdown it on exit.
+func StartEmbeddedNATS(storeDir string) (*server.Server, error) {
+	ns, err := server.NewServer(&server.Options{
+		Host:      "127.0.0.1",
+		Port:      server.RANDOM_PORT,
+		JetStream: true,
+		StoreDir:  storeDir,
+		NoSigs:    true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	go ns.Start()
+	if !ns.ReadyForConnections(10 * time.Second) {
+		ns.Shutdown()
+		return nil, errors.New("embedded NATS server did not start")
+	}
+	return ns, nil
+}
+
+// NATSSink publishes events to a JetStream stream and waits for the
+// server's acknowledgement. The event ID is sent as the message ID so
+// JetStream drops the duplicates a retried batch may produce.
+type NATSSink struct {
+	conn *nats.Conn
+	js   nats.JetStreamContext
+}
+
+func NewNATSSink(url string) (*NATSSink, error) {
+	conn, err := nats.Connect(url)
+	if err != nil {
+		return nil, err
+	}
+	js, err := conn.JetStream()
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	if _, err := js.StreamInfo(StreamName); errors.Is(err, nats.ErrStreamNotFound) {
+		_, err = js.AddStream(&nats.StreamConfig{
+			Name:       StreamName,
+			Subjects:   []string{SubjectPrefix + ">"},
+			Duplicates: 10 * time.Minute,
+		})
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+	} else if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return &NATSSink{
+		conn: conn,
+		js:   js,
+	}, nil
+}
+
+func (s *NATSSink) Name() string {
+	return "nats"
+}
+
+func (s *NATSSink) Publish(ctx context.Context, event events.Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, 10*time.Second)
+		defer cancel()
+	}
+	_, err = s.js.Publish(SubjectPrefix+event.Type, data,
+		nats.MsgId(strconv.FormatUint(event.ID, 10)),
+		nats.Context(ctx),
+	)
+	return err
+}
+
+func (s *NATSSink) Close() error {
+	s.conn.Close()
+	return nil
+}
@@ -0,0 +1,81 @@
+// Package outbox relays domain events written to the outbox table by the
+// repository to pluggable sinks. Events are published in ID order, and a
+// batch is only marked published once every sink has accepted it, so
+// delivery is at-least-once and ordered per entity.
+package outbox
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"stu/events"
+	"stu/models"
+	"stu/repository"
+	"time"
+)
+
+// Sink receives relayed events. Publish must return an error unless the
+// event has been durably handed over; the relay then retries the batch.
+type Sink interface {
+	Name() string
+	Publish(ctx context.Context, event events.Event) error
+}
+
+type Options struct {
+	BatchSize    int
+	PollInterval time.Duration
+}
+
+type Relay struct {
+	repo  repository.OutboxRepository
+	sinks []Sink
+	opts  Options
+}
+
+func NewRelay(repo repository.OutboxRepository, sinks []Sink, opts Options) *Relay {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 100
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = 500 * time.Millisecond
+	}
+	return &Relay{
+		repo:  repo,
+		sinks: sinks,
+		opts:  opts,
+	}
+}
+
+// Run relays events until ctx is cancelled. A full batch is followed
+// immediately by the next one; otherwise it waits PollInterval.
+func (r *Relay) Run(ctx context.Context) {
+	for {
+		count, err := r.ProcessOnce(ctx)
+		if err != nil {
+			log.Printf("outbox: %v", err)
+		}
+		if count == r.opts.BatchSize && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(r.opts.PollInterval):
+		}
+	}
+}
+
+// ProcessOnce relays one batch and returns how many events it contained.
+func (r *Relay) ProcessOnce(ctx context.Context) (int, error) {
+	return r.repo.ProcessOutbox(r.opts.BatchSize, func(batch []models.OutboxEvent) error {
+		for _, row := range batch {
+			event := events.FromOutbox(row)
+			for _, sink := range r.sinks {
+				if err := sink.Publish(ctx, event); err != nil {
+					return fmt.Errorf("publish event %d to %s: %w", event.ID, sink.Name(), err)
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,193 @@
+package outbox_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"stu/events"
+	"stu/models"
+	"stu/outbox"
+	"stu/repository"
+	"testing"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+)
+
+// memoryOutbox is an in-memory OutboxRepository for testing purposes.
+type memoryOutbox struct {
+	rows      []models.OutboxEvent
+	published map[uint64]bool
+}
+
+func newMemoryOutbox(types ...string) *memoryOutbox {
+	m := &memoryOutbox{published: map[uint64]bool{}}
+	for i, eventType := range types {
+		m.rows = append(m.rows, models.OutboxEvent{
+			ID: uint64(i + 1), Type: eventType, EntityType: "student", EntityID: 1, Data: `{}`,
+		})
+	}
+	return m
+}
+
+func (m *memoryOutbox) ProcessOutbox(limit int, publish func([]models.OutboxEvent) error) (int, error) {
+	var batch []models.OutboxEvent
+	for _, row := range m.rows {
+		if !m.published[row.ID] && len(batch) < limit {
+			batch = append(batch, row)
+		}
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+	if err := publish(batch); err != nil {
+		return 0, err
+	}
+	for _, row := range batch {
+		m.published[row.ID] = true
+	}
+	return len(batch), nil
+}
+
+func (m *memoryOutbox) GetOutboxEventsAfter(afterID uint64, filter repository.OutboxFilter, limit int) ([]models.OutboxEvent, error) {
+	var rows []models.OutboxEvent
+	for _, row := range m.rows {
+		if row.ID > afterID && len(rows) < limit {
+			rows = append(rows, row)
+		}
+	}
+	return rows, nil
+}
+
+// recordingSink remembers event IDs and fails while failures > 0.
+type recordingSink struct {
+	ids      []uint64
+	failures int
+}
+
+func (s *recordingSink) Name() string { return "recording" }
+
+func (s *recordingSink) Publish(ctx context.Context, event events.Event) error {
+	if s.failures > 0 {
+		s.failures--
+		return errors.New("sink unavailable")
+	}
+	s.ids = append(s.ids, event.ID)
+	return nil
+}
+
+func TestRelay_PublishesInOrder(t *testing.T) {
+	repo := newMemoryOutbox(events.StudentCreated, events.StudentUpdated, events.StudentMarksChanged)
+	sink := &recordingSink{}
+	relay := outbox.NewRelay(repo, []outbox.Sink{sink}, outbox.Options{BatchSize: 2})
+
+	first, err := relay.ProcessOnce(context.Background())
+	assert.Nil(t, err)
+	second, err := relay.ProcessOnce(context.Background())
+	assert.Nil(t, err)
+
+	assert.Equal(t, 2, first)
+	assert.Equal(t, 1, second)
+	assert.Equal(t, []uint64{1, 2, 3}, sink.ids)
+}
+
+func TestRelay_RetriesFailedBatch(t *testing.T) {
+	repo := newMemoryOutbox(events.StudentCreated, events.StudentDeleted)
+	sink := &recordingSink{failures: 1}
+	relay := outbox.NewRelay(repo, []outbox.Sink{sink}, outbox.Options{})
+
+	_, err := relay.ProcessOnce(context.Background())
+	assert.NotNil(t, err)
+	assert.False(t, repo.published[1])
+
+	count, err := relay.ProcessOnce(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []uint64{1, 2}, sink.ids)
+}
+
+func TestBusSink_FailingSubscriberLeavesEventUnpublished(t *testing.T) {
+	repo := newMemoryOutbox(events.StudentCreated)
+	bus := events.NewBus()
+	failures := 1
+	var handled []uint64
+	bus.Subscribe(events.PublisherFunc(func(event events.Event) error {
+		if failures > 0 {
+			failures--
+			return errors.New("queue unavailable")
+		}
+		handled = append(handled, event.ID)
+		return nil
+	}))
+	relay := outbox.NewRelay(repo, []outbox.Sink{outbox.NewBusSink(bus)}, outbox.Options{})
+
+	_, err := relay.ProcessOnce(context.Background())
+	assert.NotNil(t, err)
+	assert.False(t, repo.published[1])
+
+	count, err := relay.ProcessOnce(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, []uint64{1}, handled)
+}
+
+func TestFileSink_AppendsJSONLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	sink, err := outbox.NewFileSink(path)
+	assert.Nil(t, err)
+	relay := outbox.NewRelay(newMemoryOutbox(events.ClassCreated, events.ClassDeleted), []outbox.Sink{sink}, outbox.Options{})
+
+	_, err = relay.ProcessOnce(context.Background())
+	assert.Nil(t, err)
+	assert.Nil(t, sink.Close())
+
+	file, err := os.Open(path)
+	assert.Nil(t, err)
+	defer file.Close()
+	var types []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var event events.Event
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &event))
+		types = append(types, event.Type)
+	}
+	assert.Equal(t, []string{events.ClassCreated, events.ClassDeleted}, types)
+}
+
+func TestNATSSink_PublishesToEmbeddedServer(t *testing.T) {
+	ns, err := outbox.StartEmbeddedNATS(t.TempDir())
+	assert.Nil(t, err)
+	defer ns.Shutdown()
+	sink, err := outbox.NewNATSSink(ns.ClientURL())
+	assert.Nil(t, err)
+	defer sink.Close()
+
+	repo := newMemoryOutbox(events.StudentCreated, events.StudentMarksChanged)
+	relay := outbox.NewRelay(repo, []outbox.Sink{sink}, outbox.Options{})
+	_, err = relay.ProcessOnce(context.Background())
+	assert.Nil(t, err)
+
+	// Publishing the same events again is deduplicated by message ID.
+	for _, row := range repo.rows {
+		assert.Nil(t, sink.Publish(context.Background(), events.FromOutbox(row)))
+	}
+
+	conn, err := nats.Connect(ns.ClientURL())
+	assert.Nil(t, err)
+	defer conn.Close()
+	js, err := conn.JetStream()
+	assert.Nil(t, err)
+	info, err := js.StreamInfo(outbox.StreamName)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(2), info.State.Msgs)
+
+	sub, err := js.SubscribeSync(outbox.SubjectPrefix+">", nats.DeliverAll())
+	assert.Nil(t, err)
+	msg, err := sub.NextMsg(time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, outbox.SubjectPrefix+events.StudentCreated, msg.Subject)
+}
@@ -0,0 +1,59 @@
+package ratelimit
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries a client's API key; a bearer token in the
+// Authorization header is accepted as well.
+const APIKeyHeader = "X-API-Key"
+
+// APIKeys maps the digest of each API key to the client it belongs to.
+type APIKeys map[[sha256.Size]byte]string
+
+// ParseAPIKeys parses a comma separated list of "client=key" entries, e.g.
+// "dashboard=k3y,importer=s3cret".
+func ParseAPIKeys(spec string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		client, key, ok := strings.Cut(entry, "=")
+		client, key = strings.TrimSpace(client), strings.TrimSpace(key)
+		if !ok || client == "" || key == "" {
+			return nil, fmt.Errorf("API key %q must look like client=key", entry)
+		}
+		digest := sha256.Sum256([]byte(key))
+		if _, ok := keys[digest]; ok {
+			return nil, fmt.Errorf("API key of %q is already used by another client", client)
+		}
+		keys[digest] = client
+	}
+	return keys, nil
+}
+
+// Identify is middleware that stores the client whose API key the request
+// carries under ClientContextKey, so the limiter counts the request against
+// that client. Requests without a known key are counted by IP address.
+// Keys are looked up by digest so the lookup time does not depend on how
+// much of a key was guessed right.
+func Identify(keys APIKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if key == "" {
+			key, _ = strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		}
+		if key != "" {
+			if client, ok := keys[sha256.Sum256([]byte(key))]; ok {
+				c.Set(ClientContextKey, client)
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,111 @@
+package ratelimit
+
+import (
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultGroup names the limit applied to groups without their own.
+const DefaultGroup = "default"
+
+// ClientContextKey is the gin context key under which Identify, or other
+// authentication middleware running before the limiter, stores the ID of
+// the client it authenticated.
+const ClientContextKey = "ratelimit.client"
+
+// KeyFunc identifies the client a request is counted against.
+type KeyFunc func(c *gin.Context) string
+
+type Options struct {
+	// Limits maps a group to its limit. A request to POST /students/ is
+	// counted against the first of "POST students", "students", "POST"
+	// and DefaultGroup that is configured, and is not limited if none is.
+	Limits map[string]Limit
+	// Key defaults to ClientKey.
+	Key KeyFunc
+}
+
+type Limiter struct {
+	store  Store
+	limits map[string]Limit
+	key    KeyFunc
+}
+
+func NewLimiter(store Store, opts Options) *Limiter {
+	if opts.Key == nil {
+		opts.Key = ClientKey
+	}
+	return &Limiter{
+		store:  store,
+		limits: opts.Limits,
+		key:    opts.Key,
+	}
+}
+
+// Handler is the gin middleware. It answers 429 Too Many Requests once a
+// client's bucket is empty and reports the bucket state in the RateLimit
+// headers. If the store fails the request is let through.
+func (l *Limiter) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		group, limit, ok := l.limitFor(c)
+		if !ok {
+			c.Next()
+			return
+		}
+		result, err := l.store.Take(c.Request.Context(), group+"|"+l.key(c), limit)
+		if err != nil {
+			log.Printf("Rate limiting %s failed: %v", group, err)
+			c.Next()
+			return
+		}
+
+		window := float64(limit.Burst) / limit.Rate
+		c.Header("RateLimit-Policy", strconv.Itoa(limit.Burst)+";w="+strconv.Itoa(int(math.Ceil(window))))
+		c.Header("RateLimit-Limit", strconv.Itoa(limit.Burst))
+		c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(result.Reset))
+		if !result.Allowed {
+			c.Header("Retry-After", ceilSeconds(result.RetryAfter))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "rate limit exceeded"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// limitFor returns the configured group that applies to the request and
+// its limit.
+func (l *Limiter) limitFor(c *gin.Context) (string, Limit, bool) {
+	path := c.FullPath()
+	if path == "" {
+		path = c.Request.URL.Path
+	}
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	for _, name := range []string{c.Request.Method + " " + group, group, c.Request.Method, DefaultGroup} {
+		if limit, ok := l.limits[name]; ok {
+			return name, limit, true
+		}
+	}
+	return "", Limit{}, false
+}
+
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
+
+// ClientKey identifies clients by the ID stored under ClientContextKey,
+// else by IP address. Credentials in the request that nothing has verified
+// are ignored, as a client could otherwise get a fresh bucket with every
+// made-up key.
+func ClientKey(c *gin.Context) string {
+	if client := c.GetString(ClientContextKey); client != "" {
+		return "client:" + client
+	}
+	return "ip:" + c.ClientIP()
+}
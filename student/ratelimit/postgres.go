@@ -0,0 +1,85 @@
+package ratelimit
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PostgresStore keeps buckets in the rate_limit_buckets table so every
+// replica enforces the same limits. Refills are computed from the database
+// clock, so replica clock skew does not matter.
+type PostgresStore struct {
+	db *gorm.DB
+}
+
+func NewPostgresStore(db *gorm.DB) *PostgresStore {
+	return &PostgresStore{
+		db: db,
+	}
+}
+
+type bucketRow struct {
+	Tokens  float64
+	Elapsed float64
+}
+
+func (s *PostgresStore) Take(ctx context.Context, key string, limit Limit) (Result, error) {
+	var result Result
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Create the bucket full, then lock it; this serialises
+		// concurrent requests of the same client across replicas.
+		err := tx.Exec(
+			`INSERT INTO rate_limit_buckets (key, tokens, updated_at) VALUES (?, ?, now())
+			 ON CONFLICT (key) DO NOTHING`,
+			key, limit.Burst,
+		).Error
+		if err != nil {
+			return err
+		}
+		var row bucketRow
+		err = tx.Raw(
+			`SELECT tokens, GREATEST(EXTRACT(EPOCH FROM now() - updated_at), 0) AS elapsed
+			 FROM rate_limit_buckets WHERE key = ? FOR UPDATE`,
+			key,
+		).Scan(&row).Error
+		if err != nil {
+			return err
+		}
+		var tokens float64
+		tokens, result = take(row.Tokens, seconds(row.Elapsed), limit)
+		return tx.Exec(
+			`UPDATE rate_limit_buckets SET tokens = ?, updated_at = now() WHERE key = ?`,
+			tokens, key,
+		).Error
+	})
+	return result, err
+}
+
+// Prune deletes buckets untouched for longer than idle. A bucket idle
+// long enough to refill completely behaves like a missing one.
+func (s *PostgresStore) Prune(ctx context.Context, idle time.Duration) (int64, error) {
+	result := s.db.WithContext(ctx).Exec(
+		`DELETE FROM rate_limit_buckets WHERE updated_at < now() - make_interval(secs => ?)`,
+		idle.Seconds(),
+	)
+	return result.RowsAffected, result.Error
+}
+
+// Run prunes buckets idle for a day every hour until ctx is cancelled.
+func (s *PostgresStore) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Hour)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := s.Prune(ctx, 24*time.Hour); err != nil && ctx.Err() == nil {
+				log.Printf("Pruning rate limit buckets failed: %v", err)
+			}
+		}
+	}
+}
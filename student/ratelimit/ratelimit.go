@@ -0,0 +1,83 @@
+// Package ratelimit throttles API clients with token buckets. Every
+// client gets one bucket per route group; a bucket holds up to Burst
+// tokens, refills at Rate tokens per second and each request takes one.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit configures a token bucket.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+// Result is the state of a bucket after a request tried to take a token.
+type Result struct {
+	Allowed   bool
+	Remaining int
+	// Reset is the time until the bucket is full again.
+	Reset time.Duration
+	// RetryAfter is the time until the next token, set when not allowed.
+	RetryAfter time.Duration
+}
+
+// Store keeps buckets. Implementations must take tokens atomically so
+// concurrent requests of one client cannot overdraw its bucket.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// take refills a bucket that held tokens elapsed ago and takes one token
+// if available, returning the new token count and the result.
+func take(tokens float64, elapsed time.Duration, limit Limit) (float64, Result) {
+	tokens = math.Min(float64(limit.Burst), tokens+elapsed.Seconds()*limit.Rate)
+	result := Result{Allowed: tokens >= 1}
+	if result.Allowed {
+		tokens--
+	} else {
+		result.RetryAfter = seconds((1 - tokens) / limit.Rate)
+	}
+	result.Remaining = int(tokens)
+	result.Reset = seconds((float64(limit.Burst) - tokens) / limit.Rate)
+	return tokens, result
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// ParseLimits parses a comma separated list of "group=rate:burst"
+// entries, e.g. "default=50:100,POST=5:20,POST students=2:10". A group is
+// the first path segment of a route, an HTTP method or both; rate is in
+// requests per second.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		group, value, ok := strings.Cut(entry, "=")
+		rateText, burstText, ok2 := strings.Cut(value, ":")
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("rate limit %q must look like group=rate:burst", entry)
+		}
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateText), 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("rate limit %q has an invalid rate", entry)
+		}
+		burst, err := strconv.Atoi(strings.TrimSpace(burstText))
+		if err != nil || burst < 1 {
+			return nil, fmt.Errorf("rate limit %q has an invalid burst", entry)
+		}
+		limits[strings.Join(strings.Fields(group), " ")] = Limit{Rate: rate, Burst: burst}
+	}
+	return limits, nil
+}
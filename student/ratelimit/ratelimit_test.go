@@ -0,0 +1,165 @@
+package ratelimit_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"stu/ratelimit"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ratelimit.ParseLimits("default=50:100, POST=5:20,POST  students=0.5:2")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]ratelimit.Limit{
+		"default":       {Rate: 50, Burst: 100},
+		"POST":          {Rate: 5, Burst: 20},
+		"POST students": {Rate: 0.5, Burst: 2},
+	}, limits)
+
+	for _, spec := range []string{"students", "students=1", "students=x:2", "students=1:0"} {
+		_, err := ratelimit.ParseLimits(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestMemoryStore_TakesAndRefills(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	limit := ratelimit.Limit{Rate: 100, Burst: 2}
+
+	first, _ := store.Take(ctx, "a", limit)
+	second, _ := store.Take(ctx, "a", limit)
+	third, _ := store.Take(ctx, "a", limit)
+	assert.True(t, first.Allowed)
+	assert.Equal(t, 1, first.Remaining)
+	assert.True(t, second.Allowed)
+	assert.False(t, third.Allowed)
+	assert.True(t, third.RetryAfter > 0 && third.RetryAfter <= 10*time.Millisecond)
+
+	other, _ := store.Take(ctx, "b", limit)
+	assert.True(t, other.Allowed)
+
+	time.Sleep(20 * time.Millisecond)
+	refilled, _ := store.Take(ctx, "a", limit)
+	assert.True(t, refilled.Allowed)
+}
+
+// newLimitedRouter only knows the API keys key-1 and key-2, and trusts no
+// proxies.
+func newLimitedRouter(limits map[string]ratelimit.Limit) *gin.Engine {
+	keys, err := ratelimit.ParseAPIKeys("one=key-1, two=key-2")
+	if err != nil {
+		panic(err)
+	}
+	router := gin.New()
+	router.SetTrustedProxies(nil)
+	router.Use(ratelimit.Identify(keys))
+	router.Use(ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Options{Limits: limits}).Handler())
+	router.GET("/students/", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/students/", func(c *gin.Context) { c.Status(http.StatusCreated) })
+	return router
+}
+
+func request(router *gin.Engine, method, apiKey string) *httptest.ResponseRecorder {
+	req, _ := http.NewRequest(method, "/students/", nil)
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	resp := httptest.NewRecorder()
+	router.ServeHTTP(resp, req)
+	return resp
+}
+
+func TestLimiter_RejectsWithHeaders(t *testing.T) {
+	router := newLimitedRouter(map[string]ratelimit.Limit{
+		"default": {Rate: 100, Burst: 10},
+		"POST":    {Rate: 0.5, Burst: 1},
+	})
+
+	resp := request(router, "POST", "key-1")
+	assert.Equal(t, http.StatusCreated, resp.Code)
+	assert.Equal(t, "1", resp.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "0", resp.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "2", resp.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "1;w=2", resp.Header().Get("RateLimit-Policy"))
+
+	resp = request(router, "POST", "key-1")
+	assert.Equal(t, http.StatusTooManyRequests, resp.Code)
+	assert.Equal(t, "2", resp.Header().Get("Retry-After"))
+
+	// Other clients and other groups have their own buckets.
+	assert.Equal(t, http.StatusCreated, request(router, "POST", "key-2").Code)
+	resp = request(router, "GET", "key-1")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "10", resp.Header().Get("RateLimit-Limit"))
+}
+
+func TestLimiter_UnverifiedKeysShareTheIPBucket(t *testing.T) {
+	router := newLimitedRouter(map[string]ratelimit.Limit{"POST": {Rate: 0.5, Burst: 1}})
+
+	assert.Equal(t, http.StatusCreated, request(router, "POST", "made-up-1").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "POST", "made-up-2").Code)
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "POST", "").Code)
+	assert.Equal(t, http.StatusCreated, request(router, "POST", "key-1").Code)
+}
+
+func TestLimiter_BearerTokensIdentifyClients(t *testing.T) {
+	router := newLimitedRouter(map[string]ratelimit.Limit{"POST": {Rate: 0.5, Burst: 1}})
+	bearer := func(token string) int {
+		req, _ := http.NewRequest("POST", "/students/", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, bearer("key-1"))
+	assert.Equal(t, http.StatusTooManyRequests, request(router, "POST", "key-1").Code)
+	assert.Equal(t, http.StatusCreated, bearer("key-2"))
+}
+
+func TestLimiter_ForwardedForFromUntrustedPeersIsIgnored(t *testing.T) {
+	router := newLimitedRouter(map[string]ratelimit.Limit{"POST": {Rate: 0.5, Burst: 1}})
+	forwarded := func(ip string) int {
+		req, _ := http.NewRequest("POST", "/students/", nil)
+		req.RemoteAddr = "192.0.2.1:4000"
+		req.Header.Set("X-Forwarded-For", ip)
+		resp := httptest.NewRecorder()
+		router.ServeHTTP(resp, req)
+		return resp.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, forwarded("198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, forwarded("198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, forwarded("198.51.100.3"))
+}
+
+func TestParseAPIKeys(t *testing.T) {
+	_, err := ratelimit.ParseAPIKeys("dashboard=k3y, importer=s3cret")
+	assert.Nil(t, err)
+
+	for _, spec := range []string{"dashboard", "dashboard=", "=k3y", "a=k3y,b=k3y"} {
+		_, err := ratelimit.ParseAPIKeys(spec)
+		assert.NotNil(t, err, spec)
+	}
+}
+
+func TestLimiter_MostSpecificGroupWins(t *testing.T) {
+	router := newLimitedRouter(map[string]ratelimit.Limit{
+		"POST":          {Rate: 100, Burst: 10},
+		"POST students": {Rate: 1, Burst: 3},
+	})
+
+	resp := request(router, "POST", "")
+	assert.Equal(t, "3", resp.Header().Get("RateLimit-Limit"))
+
+	// Without a default limit other requests are not limited.
+	resp = request(router, "GET", "")
+	assert.Equal(t, http.StatusOK, resp.Code)
+	assert.Equal(t, "", resp.Header().Get("RateLimit-Limit"))
+}
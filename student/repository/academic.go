@@ -0,0 +1,282 @@
+package repository
+
+import (
+	"stu/events"
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AcademicRepository interface {
+	// Academic year methods
+	GetAcademicYearsBySchoolID(schoolID uint) ([]models.AcademicYear, error)
+	GetAcademicYearByID(id uint) (*models.AcademicYear, error)
+	CreateAcademicYear(year *models.AcademicYear) error
+	UpdateAcademicYear(year *models.AcademicYear) error
+	DeleteAcademicYear(id uint) error
+
+	// Term methods
+	GetTermsByAcademicYearID(yearID uint) ([]models.Term, error)
+	GetTermsByIDs(ids []uint) ([]models.Term, error)
+	GetTermByID(id uint) (*models.Term, error)
+	// GetActiveTerm returns gorm.ErrRecordNotFound if the school has no
+	// active term.
+	GetActiveTerm(schoolID uint) (*models.Term, error)
+	CreateTerm(term *models.Term) error
+	UpdateTerm(term *models.Term) error
+	DeleteTerm(id uint) error
+	// ActivateTerm makes the term the active term of its school. Every
+	// student of the school is enrolled in the term with their current
+	// class, and their marks are set to those already recorded for the
+	// term, or zero, so the previous term's marks stay in its enrollments.
+	ActivateTerm(id uint) error
+
+	// Enrollment methods
+	GetEnrollment(studentID, termID uint) (*models.Enrollment, error)
+	GetEnrollmentsByStudentID(studentID uint) ([]models.Enrollment, error)
+	// GetEnrollmentsByTermID returns the term's enrollments, only those of
+	// the class unless classID is zero.
+	GetEnrollmentsByTermID(termID, classID uint) ([]models.Enrollment, error)
+	CountEnrollmentsByTermID(termID uint) (int64, error)
+	// UpdateEnrollment returns ErrMarksComputed when the marks of an
+	// enrollment in a class that had published exams in the term would
+	// change; they are the marks computed from the exams.
+	UpdateEnrollment(enrollment *models.Enrollment) error
+}
+
+type academicRepository struct {
+	db *gorm.DB
+}
+
+func NewAcademicRepository(db *gorm.DB) AcademicRepository {
+	return &academicRepository{
+		db: db,
+	}
+}
+
+// Academic year methods
+
+func (r *academicRepository) GetAcademicYearsBySchoolID(schoolID uint) ([]models.AcademicYear, error) {
+	var years []models.AcademicYear
+	result := r.db.Where("school_id = ?", schoolID).Order("start_date").Find(&years)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return years, nil
+}
+
+func (r *academicRepository) GetAcademicYearByID(id uint) (*models.AcademicYear, error) {
+	var year models.AcademicYear
+	result := r.db.First(&year, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &year, nil
+}
+
+func (r *academicRepository) CreateAcademicYear(year *models.AcademicYear) error {
+	return r.db.Create(year).Error
+}
+
+func (r *academicRepository) UpdateAcademicYear(year *models.AcademicYear) error {
+	return r.db.Save(year).Error
+}
+
+func (r *academicRepository) DeleteAcademicYear(id uint) error {
+	return r.db.Delete(&models.AcademicYear{}, id).Error
+}
+
+// Term methods
+
+func (r *academicRepository) GetTermsByAcademicYearID(yearID uint) ([]models.Term, error) {
+	var terms []models.Term
+	result := r.db.Where("academic_year_id = ?", yearID).Order("start_date").Find(&terms)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return terms, nil
+}
+
+func (r *academicRepository) GetTermsByIDs(ids []uint) ([]models.Term, error) {
+	var terms []models.Term
+	if len(ids) == 0 {
+		return terms, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&terms)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return terms, nil
+}
+
+func (r *academicRepository) GetTermByID(id uint) (*models.Term, error) {
+	var term models.Term
+	result := r.db.First(&term, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &term, nil
+}
+
+func (r *academicRepository) GetActiveTerm(schoolID uint) (*models.Term, error) {
+	var term models.Term
+	result := r.db.Where("school_id = ? AND active = ?", schoolID, true).First(&term)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &term, nil
+}
+
+func (r *academicRepository) CreateTerm(term *models.Term) error {
+	return r.db.Create(term).Error
+}
+
+func (r *academicRepository) UpdateTerm(term *models.Term) error {
+	return r.db.Omit("active").Save(term).Error
+}
+
+func (r *academicRepository) DeleteTerm(id uint) error {
+	return r.db.Delete(&models.Term{}, id).Error
+}
+
+func (r *academicRepository) ActivateTerm(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var term models.Term
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&term, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Term{}).
+			Where("school_id = ? AND id <> ? AND active = ?", term.SchoolID, term.ID, true).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&term).Update("active", true).Error; err != nil {
+			return err
+		}
+
+		var students []models.Student
+		if err := tx.Where("class_id IN (?)", tx.Model(&models.Class{}).Select("id").Where("school_id = ?", term.SchoolID)).
+			Order("id").Find(&students).Error; err != nil {
+			return err
+		}
+		var existing []models.Enrollment
+		if err := tx.Where("term_id = ?", term.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		recorded := make(map[uint]int, len(existing))
+		for _, enrollment := range existing {
+			recorded[enrollment.StudentID] = enrollment.Marks
+		}
+		for i := range students {
+			student := &students[i]
+			marks := recorded[student.ID]
+			if err := saveEnrollment(tx, student.ID, term.ID, student.ClassID, marks); err != nil {
+				return err
+			}
+			if student.Marks == marks {
+				continue
+			}
+			previous := student.Marks
+			student.Marks = marks
+			if err := tx.Model(student).Updates(map[string]interface{}{
+				"marks":            marks,
+				"marks_updated_at": time.Now(),
+			}).Error; err != nil {
+				return err
+			}
+			if err := recordStudentEvent(tx, events.StudentMarksChanged, student.ID, student.ClassID, marksChanged{
+				Student:       student,
+				PreviousMarks: previous,
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Enrollment methods
+
+func (r *academicRepository) GetEnrollment(studentID, termID uint) (*models.Enrollment, error) {
+	var enrollment models.Enrollment
+	result := r.db.Where("student_id = ? AND term_id = ?", studentID, termID).First(&enrollment)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &enrollment, nil
+}
+
+func (r *academicRepository) GetEnrollmentsByStudentID(studentID uint) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	result := r.db.Where("student_id = ?", studentID).Order("term_id").Find(&enrollments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return enrollments, nil
+}
+
+func (r *academicRepository) GetEnrollmentsByTermID(termID, classID uint) ([]models.Enrollment, error) {
+	var enrollments []models.Enrollment
+	query := r.db.Where("term_id = ?", termID)
+	if classID != 0 {
+		query = query.Where("class_id = ?", classID)
+	}
+	result := query.Order("class_id, student_id").Find(&enrollments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return enrollments, nil
+}
+
+func (r *academicRepository) CountEnrollmentsByTermID(termID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.Enrollment{}).Where("term_id = ?", termID).Count(&count).Error
+	return count, err
+}
+
+func (r *academicRepository) UpdateEnrollment(enrollment *models.Enrollment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous models.Enrollment
+		found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}), &previous, enrollment.ID)
+		if err != nil {
+			return err
+		}
+		if found && previous.Marks != enrollment.Marks {
+			locked, err := hasPublishedExams(tx, previous.ClassID, previous.TermID)
+			if err != nil {
+				return err
+			}
+			if locked {
+				return ErrMarksComputed
+			}
+		}
+		return tx.Save(enrollment).Error
+	})
+}
+
+// syncEnrollment records the student's class and marks in the active term
+// of the class's school, if it has one, so the enrollment of the current
+// term follows the student row.
+func syncEnrollment(tx *gorm.DB, student *models.Student) error {
+	var term models.Term
+	found, err := findOne(tx.Select("id").
+		Where("active = ? AND school_id IN (?)", true, tx.Model(&models.Class{}).Select("school_id").Where("id = ?", student.ClassID)), &term)
+	if err != nil || !found {
+		return err
+	}
+	return saveEnrollment(tx, student.ID, term.ID, student.ClassID, student.Marks)
+}
+
+func saveEnrollment(tx *gorm.DB, studentID, termID, classID uint, marks int) error {
+	return tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "term_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"class_id", "marks", "updated_at"}),
+	}).Create(&models.Enrollment{
+		StudentID: studentID,
+		TermID:    termID,
+		ClassID:   classID,
+		Marks:     marks,
+	}).Error
+}
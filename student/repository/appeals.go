@@ -0,0 +1,161 @@
+package repository
+
+import (
+	"stu/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AppealRepository interface {
+	GetAppealByID(id uint) (*models.Appeal, error)
+	// GetOpenAppealsBySchoolID returns the school's unresolved appeals,
+	// soonest due first.
+	GetOpenAppealsBySchoolID(schoolID uint) ([]models.Appeal, error)
+	GetAppealsByStudentID(studentID uint) ([]models.Appeal, error)
+	// HasOpenAppeal reports whether the student's marks in the exam are
+	// already under an unresolved appeal.
+	HasOpenAppeal(examID, studentID uint) (bool, error)
+	CreateAppeal(appeal *models.Appeal) error
+	// AssignAppeal stores the appeal's reviewer and puts it in review. It
+	// returns ErrConflict if the appeal was resolved meanwhile.
+	AssignAppeal(appeal *models.Appeal) error
+	// ResolveAppeal stores the outcome of the appeal and, when the appeal
+	// revises the marks, the new exam result, its MarkChange and the
+	// recomputed marks of the class, all in one transaction. It returns ErrConflict if the appeal is no longer
+	// in review, the exam is no longer published or the marks are no
+	// longer the ones appealed.
+	ResolveAppeal(appeal *models.Appeal) error
+
+	GetMarkChangesByStudentID(studentID uint) ([]models.MarkChange, error)
+}
+
+type appealRepository struct {
+	db *gorm.DB
+}
+
+func NewAppealRepository(db *gorm.DB) AppealRepository {
+	return &appealRepository{
+		db: db,
+	}
+}
+
+func (r *appealRepository) GetAppealByID(id uint) (*models.Appeal, error) {
+	var appeal models.Appeal
+	result := r.db.First(&appeal, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &appeal, nil
+}
+
+func (r *appealRepository) GetOpenAppealsBySchoolID(schoolID uint) ([]models.Appeal, error) {
+	var appeals []models.Appeal
+	result := r.db.Where("school_id = ? AND status <> ?", schoolID, models.AppealResolved).Order("due_at, id").Find(&appeals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return appeals, nil
+}
+
+func (r *appealRepository) GetAppealsByStudentID(studentID uint) ([]models.Appeal, error) {
+	var appeals []models.Appeal
+	result := r.db.Where("student_id = ?", studentID).Order("id").Find(&appeals)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return appeals, nil
+}
+
+func (r *appealRepository) HasOpenAppeal(examID, studentID uint) (bool, error) {
+	var count int64
+	result := r.db.Model(&models.Appeal{}).
+		Where("exam_id = ? AND student_id = ? AND status <> ?", examID, studentID, models.AppealResolved).
+		Count(&count)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return count > 0, nil
+}
+
+func (r *appealRepository) CreateAppeal(appeal *models.Appeal) error {
+	return r.db.Create(appeal).Error
+}
+
+func (r *appealRepository) AssignAppeal(appeal *models.Appeal) error {
+	result := r.db.Model(appeal).
+		Where("status <> ?", models.AppealResolved).
+		Updates(map[string]interface{}{
+			"status":      appeal.Status,
+			"reviewer":    appeal.Reviewer,
+			"assigned_at": appeal.AssignedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrConflict
+	}
+	return nil
+}
+
+func (r *appealRepository) ResolveAppeal(appeal *models.Appeal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var stored models.Appeal
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&stored, appeal.ID).Error; err != nil {
+			return err
+		}
+		if stored.Status != models.AppealInReview {
+			return ErrConflict
+		}
+		if appeal.RevisedMarks != nil {
+			var exam models.Exam
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&exam, appeal.ExamID).Error; err != nil {
+				return err
+			}
+			var result models.ExamResult
+			found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("exam_id = ? AND student_id = ?", appeal.ExamID, appeal.StudentID), &result)
+			if err != nil {
+				return err
+			}
+			if exam.Status != models.MarksPublished || !found || result.Marks != appeal.OriginalMarks {
+				return ErrConflict
+			}
+			result.Marks = *appeal.RevisedMarks
+			if err := tx.Save(&result).Error; err != nil {
+				return err
+			}
+			if err := tx.Create(&models.MarkChange{
+				ExamID:    appeal.ExamID,
+				StudentID: appeal.StudentID,
+				From:      appeal.OriginalMarks,
+				To:        *appeal.RevisedMarks,
+				Actor:     appeal.Reviewer,
+				Reason:    appeal.Resolution,
+				AppealID:  &appeal.ID,
+			}).Error; err != nil {
+				return err
+			}
+			if err := recomputeMarks(tx, exam.ClassID); err != nil {
+				return err
+			}
+		}
+		return tx.Model(appeal).Updates(map[string]interface{}{
+			"status":        appeal.Status,
+			"outcome":       appeal.Outcome,
+			"revised_marks": appeal.RevisedMarks,
+			"resolution":    appeal.Resolution,
+			"resolved_at":   appeal.ResolvedAt,
+		}).Error
+	})
+}
+
+func (r *appealRepository) GetMarkChangesByStudentID(studentID uint) ([]models.MarkChange, error) {
+	var changes []models.MarkChange
+	result := r.db.Where("student_id = ?", studentID).Order("id").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
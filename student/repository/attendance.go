@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AttendanceRepository interface {
+	// SaveAttendance inserts the records, replacing existing records for
+	// the same student, date and period, in one transaction.
+	SaveAttendance(records []models.AttendanceRecord) error
+	GetAttendance(filter AttendanceFilter) ([]models.AttendanceRecord, error)
+	// GetAttendanceCounts counts the records matching filter by student
+	// and status.
+	GetAttendanceCounts(filter AttendanceFilter) ([]AttendanceCounts, error)
+}
+
+// AttendanceFilter narrows attendance queries. Zero fields match
+// everything; From and To bound the date inclusively.
+type AttendanceFilter struct {
+	StudentID uint
+	ClassID   uint
+	SchoolID  uint
+	From      time.Time
+	To        time.Time
+}
+
+// AttendanceCounts are a student's records by status.
+type AttendanceCounts struct {
+	StudentID uint
+	Present   int64
+	Absent    int64
+	Late      int64
+	Excused   int64
+}
+
+type attendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &attendanceRepository{
+		db: db,
+	}
+}
+
+func (r *attendanceRepository) SaveAttendance(records []models.AttendanceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "student_id"}, {Name: "date"}, {Name: "period"}},
+		DoUpdates: clause.AssignmentColumns([]string{"class_id", "status", "note", "updated_at"}),
+	}).Create(&records).Error
+}
+
+func (r *attendanceRepository) GetAttendance(filter AttendanceFilter) ([]models.AttendanceRecord, error) {
+	var records []models.AttendanceRecord
+	result := r.filtered(filter).Order("date, period, student_id").Find(&records)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return records, nil
+}
+
+func (r *attendanceRepository) GetAttendanceCounts(filter AttendanceFilter) ([]AttendanceCounts, error) {
+	var counts []AttendanceCounts
+	result := r.filtered(filter).
+		Select(`student_id,
+			sum(CASE WHEN status = ? THEN 1 ELSE 0 END) AS present,
+			sum(CASE WHEN status = ? THEN 1 ELSE 0 END) AS absent,
+			sum(CASE WHEN status = ? THEN 1 ELSE 0 END) AS late,
+			sum(CASE WHEN status = ? THEN 1 ELSE 0 END) AS excused`,
+			models.AttendancePresent, models.AttendanceAbsent, models.AttendanceLate, models.AttendanceExcused).
+		Group("student_id").
+		Order("student_id").
+		Scan(&counts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return counts, nil
+}
+
+func (r *attendanceRepository) filtered(filter AttendanceFilter) *gorm.DB {
+	query := r.db.Model(&models.AttendanceRecord{})
+	if filter.StudentID != 0 {
+		query = query.Where("student_id = ?", filter.StudentID)
+	}
+	if filter.ClassID != 0 {
+		query = query.Where("class_id = ?", filter.ClassID)
+	}
+	if filter.SchoolID != 0 {
+		query = query.Where("class_id IN (?)", r.db.Model(&models.Class{}).Select("id").Where("school_id = ?", filter.SchoolID))
+	}
+	if !filter.From.IsZero() {
+		query = query.Where("date >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("date <= ?", filter.To)
+	}
+	return query
+}
@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"container/list"
+	"fmt"
+	"stu/events"
+	"stu/models"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheOptions bounds the caching repository.
+type CacheOptions struct {
+	// Size is the maximum number of cached records.
+	Size int
+	// TTL bounds how stale a record can be when it was changed by another
+	// replica.
+	TTL time.Duration
+}
+
+// CacheStats counts cache outcomes since the cache was created.
+type CacheStats struct {
+	Hits          uint64 `json:"hits"`
+	Misses        uint64 `json:"misses"`
+	Evictions     uint64 `json:"evictions"`
+	Invalidations uint64 `json:"invalidations"`
+	Size          int    `json:"size"`
+}
+
+type cacheKey struct {
+	entity string
+	id     uint
+}
+
+type cacheEntry struct {
+	key     cacheKey
+	value   interface{}
+	expires time.Time
+}
+
+// CachingRepository decorates a Repository with a read-through LRU cache
+// for the lookups by ID. Its own updates and deletes invalidate the cached
+// record; subscribed to the event bus it also drops records changed
+// through other paths. Concurrent misses for the same record share one
+// query.
+type CachingRepository struct {
+	Repository
+
+	opts    CacheOptions
+	loads   singleflight.Group
+	mu      sync.Mutex
+	entries map[cacheKey]*list.Element
+	order   *list.List
+	// generation is bumped by every invalidation so that loads which
+	// started before it do not store what they read.
+	generation uint64
+
+	hits, misses, evictions, invalidations atomic.Uint64
+}
+
+func NewCachingRepository(next Repository, opts CacheOptions) *CachingRepository {
+	if opts.Size <= 0 {
+		opts.Size = 10000
+	}
+	if opts.TTL <= 0 {
+		opts.TTL = time.Minute
+	}
+	return &CachingRepository{
+		Repository: next,
+		opts:       opts,
+		entries:    map[cacheKey]*list.Element{},
+		order:      list.New(),
+	}
+}
+
+func (r *CachingRepository) Stats() CacheStats {
+	r.mu.Lock()
+	size := r.order.Len()
+	r.mu.Unlock()
+	return CacheStats{
+		Hits:          r.hits.Load(),
+		Misses:        r.misses.Load(),
+		Evictions:     r.evictions.Load(),
+		Invalidations: r.invalidations.Load(),
+		Size:          size,
+	}
+}
+
+// Publish invalidates the record an event is about.
+func (r *CachingRepository) Publish(event events.Event) error {
+	r.invalidate(cacheKey{event.EntityType, event.EntityID})
+	return nil
+}
+
+// School methods
+
+func (r *CachingRepository) GetSchoolByID(id uint) (*models.School, error) {
+	value, err := r.get(cacheKey{"school", id}, func() (interface{}, error) {
+		return r.Repository.GetSchoolByID(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	school := *value.(*models.School)
+	return &school, nil
+}
+
+func (r *CachingRepository) UpdateSchool(school *models.School) error {
+	defer r.invalidate(cacheKey{"school", school.ID})
+	return r.Repository.UpdateSchool(school)
+}
+
+func (r *CachingRepository) DeleteSchool(id uint) error {
+	defer r.invalidate(cacheKey{"school", id})
+	return r.Repository.DeleteSchool(id)
+}
+
+// Class methods
+
+func (r *CachingRepository) GetClassByID(id uint) (*models.Class, error) {
+	value, err := r.get(cacheKey{"class", id}, func() (interface{}, error) {
+		return r.Repository.GetClassByID(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	class := *value.(*models.Class)
+	return &class, nil
+}
+
+func (r *CachingRepository) UpdateClass(class *models.Class) error {
+	defer r.invalidate(cacheKey{"class", class.ID})
+	return r.Repository.UpdateClass(class)
+}
+
+func (r *CachingRepository) DeleteClass(id uint) error {
+	defer r.invalidate(cacheKey{"class", id})
+	return r.Repository.DeleteClass(id)
+}
+
+// Student methods
+
+func (r *CachingRepository) GetStudentByID(id uint) (*models.Student, error) {
+	value, err := r.get(cacheKey{"student", id}, func() (interface{}, error) {
+		return r.Repository.GetStudentByID(id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	student := *value.(*models.Student)
+	return &student, nil
+}
+
+func (r *CachingRepository) UpdateStudent(student *models.Student) error {
+	defer r.invalidate(cacheKey{"student", student.ID})
+	return r.Repository.UpdateStudent(student)
+}
+
+func (r *CachingRepository) DeleteStudent(id uint) error {
+	defer r.invalidate(cacheKey{"student", id})
+	return r.Repository.DeleteStudent(id)
+}
+
+// get returns the cached value for key or loads it, sharing the load with
+// concurrent callers. Errors are not cached. Callers copy the value before
+// handing it out.
+func (r *CachingRepository) get(key cacheKey, load func() (interface{}, error)) (interface{}, error) {
+	r.mu.Lock()
+	if element, ok := r.entries[key]; ok {
+		entry := element.Value.(*cacheEntry)
+		if time.Now().Before(entry.expires) {
+			r.order.MoveToFront(element)
+			r.mu.Unlock()
+			r.hits.Add(1)
+			return entry.value, nil
+		}
+		r.remove(element)
+	}
+	generation := r.generation
+	r.mu.Unlock()
+	r.misses.Add(1)
+
+	value, err, _ := r.loads.Do(flightKey(key), func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		r.store(key, value, generation)
+		return value, nil
+	})
+	return value, err
+}
+
+func (r *CachingRepository) store(key cacheKey, value interface{}, generation uint64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.generation != generation {
+		return
+	}
+	entry := &cacheEntry{key: key, value: value, expires: time.Now().Add(r.opts.TTL)}
+	if element, ok := r.entries[key]; ok {
+		element.Value = entry
+		r.order.MoveToFront(element)
+		return
+	}
+	r.entries[key] = r.order.PushFront(entry)
+	for r.order.Len() > r.opts.Size {
+		r.remove(r.order.Back())
+		r.evictions.Add(1)
+	}
+}
+
+func (r *CachingRepository) invalidate(key cacheKey) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.generation++
+	if element, ok := r.entries[key]; ok {
+		r.remove(element)
+	}
+	// Callers arriving after the change must not join a load that may
+	// have read the old record.
+	r.loads.Forget(flightKey(key))
+	r.invalidations.Add(1)
+}
+
+func (r *CachingRepository) remove(element *list.Element) {
+	delete(r.entries, element.Value.(*cacheEntry).key)
+	r.order.Remove(element)
+}
+
+func flightKey(key cacheKey) string {
+	return fmt.Sprintf("%s:%d", key.entity, key.id)
+}
@@ -0,0 +1,151 @@
+package repository_test
+
+import (
+	"errors"
+	"stu/events"
+	"stu/models"
+	"stu/repository"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// countingRepository serves schools from memory and counts lookups. The
+// embedded interface leaves the methods the tests do not use unimplemented.
+type countingRepository struct {
+	repository.Repository
+	mu      sync.Mutex
+	schools map[uint]models.School
+	lookups atomic.Int64
+	// release, when set, holds lookups until it is closed.
+	release chan struct{}
+}
+
+func newCountingRepository(names ...string) *countingRepository {
+	repo := &countingRepository{schools: map[uint]models.School{}}
+	for i, name := range names {
+		school := models.School{Name: name}
+		school.ID = uint(i + 1)
+		repo.schools[school.ID] = school
+	}
+	return repo
+}
+
+func (r *countingRepository) GetSchoolByID(id uint) (*models.School, error) {
+	r.lookups.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	school, ok := r.schools[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &school, nil
+}
+
+func (r *countingRepository) UpdateSchool(school *models.School) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.schools[school.ID] = *school
+	return nil
+}
+
+func TestCachingRepository_HitsAndInvalidates(t *testing.T) {
+	next := newCountingRepository("North")
+	cache := repository.NewCachingRepository(next, repository.CacheOptions{Size: 10, TTL: time.Minute})
+
+	first, err := cache.GetSchoolByID(1)
+	assert.Nil(t, err)
+	first.Name = "changed by caller"
+	second, _ := cache.GetSchoolByID(1)
+	assert.Equal(t, "North", second.Name)
+	assert.Equal(t, int64(1), next.lookups.Load())
+
+	updated := *second
+	updated.Name = "South"
+	assert.Nil(t, cache.UpdateSchool(&updated))
+	third, _ := cache.GetSchoolByID(1)
+	assert.Equal(t, "South", third.Name)
+	assert.Equal(t, int64(2), next.lookups.Load())
+
+	stats := cache.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Invalidations)
+}
+
+func TestCachingRepository_DoesNotCacheErrors(t *testing.T) {
+	next := newCountingRepository()
+	cache := repository.NewCachingRepository(next, repository.CacheOptions{})
+
+	_, err := cache.GetSchoolByID(7)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	_, err = cache.GetSchoolByID(7)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	assert.Equal(t, int64(2), next.lookups.Load())
+}
+
+func TestCachingRepository_EvictsLeastRecentlyUsed(t *testing.T) {
+	next := newCountingRepository("A", "B", "C")
+	cache := repository.NewCachingRepository(next, repository.CacheOptions{Size: 2, TTL: time.Minute})
+
+	cache.GetSchoolByID(1)
+	cache.GetSchoolByID(2)
+	cache.GetSchoolByID(1)
+	cache.GetSchoolByID(3) // evicts 2
+	assert.Equal(t, int64(3), next.lookups.Load())
+
+	cache.GetSchoolByID(1)
+	assert.Equal(t, int64(3), next.lookups.Load())
+	cache.GetSchoolByID(2)
+	assert.Equal(t, int64(4), next.lookups.Load())
+	assert.Equal(t, 2, cache.Stats().Size)
+	assert.Equal(t, uint64(2), cache.Stats().Evictions)
+}
+
+func TestCachingRepository_ExpiresEntries(t *testing.T) {
+	next := newCountingRepository("A")
+	cache := repository.NewCachingRepository(next, repository.CacheOptions{TTL: 10 * time.Millisecond})
+
+	cache.GetSchoolByID(1)
+	time.Sleep(20 * time.Millisecond)
+	cache.GetSchoolByID(1)
+	assert.Equal(t, int64(2), next.lookups.Load())
+}
+
+func TestCachingRepository_SharesConcurrentMisses(t *testing.T) {
+	next := newCountingRepository("A")
+	next.release = make(chan struct{})
+	cache := repository.NewCachingRepository(next, repository.CacheOptions{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			school, err := cache.GetSchoolByID(1)
+			assert.Nil(t, err)
+			assert.Equal(t, "A", school.Name)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(next.release)
+	wg.Wait()
+	assert.Equal(t, int64(1), next.lookups.Load())
+}
+
+func TestCachingRepository_InvalidatesOnEvents(t *testing.T) {
+	next := newCountingRepository("A")
+	cache := repository.NewCachingRepository(next, repository.CacheOptions{})
+
+	cache.GetSchoolByID(1)
+	assert.Nil(t, cache.Publish(events.Event{Type: events.SchoolUpdated, EntityType: "school", EntityID: 1}))
+	cache.GetSchoolByID(1)
+	assert.Equal(t, int64(2), next.lookups.Load())
+}
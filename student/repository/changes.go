@@ -0,0 +1,148 @@
+package repository
+
+import (
+	"errors"
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrConflict is returned by the conditional writes when the record was
+// modified or deleted after the version the caller based its change on.
+var ErrConflict = errors.New("record was changed since the given version")
+
+// ChangeRepository backs offline sync: it reads records including
+// soft-deleted tombstones and applies writes only if the stored UpdatedAt
+// still matches the version the client last saw.
+type ChangeRepository interface {
+	GetSchoolsIncludingDeleted(ids []uint) ([]models.School, error)
+	GetClassesIncludingDeleted(ids []uint) ([]models.Class, error)
+	GetStudentsIncludingDeleted(ids []uint) ([]models.Student, error)
+
+	UpdateSchoolIfUnchanged(school *models.School, version time.Time) error
+	DeleteSchoolIfUnchanged(id uint, version time.Time) error
+	UpdateClassIfUnchanged(class *models.Class, version time.Time) error
+	DeleteClassIfUnchanged(id uint, version time.Time) error
+	UpdateStudentIfUnchanged(student *models.Student, version time.Time) error
+	DeleteStudentIfUnchanged(id uint, version time.Time) error
+}
+
+type changeRepository struct {
+	db *gorm.DB
+}
+
+func NewChangeRepository(db *gorm.DB) ChangeRepository {
+	return &changeRepository{
+		db: db,
+	}
+}
+
+func (r *changeRepository) GetSchoolsIncludingDeleted(ids []uint) ([]models.School, error) {
+	var schools []models.School
+	result := r.db.Unscoped().Where("id IN ?", ids).Order("id").Find(&schools)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return schools, nil
+}
+
+func (r *changeRepository) GetClassesIncludingDeleted(ids []uint) ([]models.Class, error) {
+	var classes []models.Class
+	result := r.db.Unscoped().Where("id IN ?", ids).Order("id").Find(&classes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return classes, nil
+}
+
+func (r *changeRepository) GetStudentsIncludingDeleted(ids []uint) ([]models.Student, error) {
+	var students []models.Student
+	result := r.db.Unscoped().Where("id IN ?", ids).Order("id").Find(&students)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return students, nil
+}
+
+// The conditional writes lock the row, compare versions and then run the
+// regular repository write in the same transaction, so the change and its
+// outbox event are recorded exactly as for any other write.
+
+func (r *changeRepository) UpdateSchoolIfUnchanged(school *models.School, version time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, &models.School{}, school.ID, version); err != nil {
+			return err
+		}
+		return (&repository{db: tx}).UpdateSchool(school)
+	})
+}
+
+func (r *changeRepository) DeleteSchoolIfUnchanged(id uint, version time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, &models.School{}, id, version); err != nil {
+			return err
+		}
+		return (&repository{db: tx}).DeleteSchool(id)
+	})
+}
+
+func (r *changeRepository) UpdateClassIfUnchanged(class *models.Class, version time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, &models.Class{}, class.ID, version); err != nil {
+			return err
+		}
+		return (&repository{db: tx}).UpdateClass(class)
+	})
+}
+
+func (r *changeRepository) DeleteClassIfUnchanged(id uint, version time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, &models.Class{}, id, version); err != nil {
+			return err
+		}
+		return (&repository{db: tx}).DeleteClass(id)
+	})
+}
+
+func (r *changeRepository) UpdateStudentIfUnchanged(student *models.Student, version time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, &models.Student{}, student.ID, version); err != nil {
+			return err
+		}
+		return (&repository{db: tx}).UpdateStudent(student)
+	})
+}
+
+func (r *changeRepository) DeleteStudentIfUnchanged(id uint, version time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := checkVersion(tx, &models.Student{}, id, version); err != nil {
+			return err
+		}
+		return (&repository{db: tx}).DeleteStudent(id)
+	})
+}
+
+// checkVersion locks the row of model with the given ID and returns
+// ErrConflict if it was deleted or its UpdatedAt differs from version.
+// Postgres stores microseconds, so both sides are compared at that
+// precision.
+func checkVersion(tx *gorm.DB, model interface{}, id uint, version time.Time) error {
+	var row struct {
+		UpdatedAt time.Time
+		DeletedAt gorm.DeletedAt
+	}
+	result := tx.Model(model).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("updated_at", "deleted_at").
+		Where("id = ?", id).
+		Take(&row)
+	if result.Error != nil {
+		return result.Error
+	}
+	if row.DeletedAt.Valid || !row.UpdatedAt.Truncate(time.Microsecond).Equal(version.Truncate(time.Microsecond)) {
+		return ErrConflict
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+package repository
+
+import (
+	"errors"
+	"sort"
+	"stu/assessment"
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrMarksComputed is returned when marks that are computed from published
+// exams would be set directly.
+var ErrMarksComputed = errors.New("marks are computed from published exams and cannot be set directly")
+
+type ExamRepository interface {
+	// GetExamsByClassID returns the class's exams by term, subject and
+	// date.
+	GetExamsByClassID(classID uint) ([]models.Exam, error)
+	// GetPublishedExams returns the exams in subject with published marks
+	// of the classes in scope.
+	GetPublishedExams(scope StudentScope, subject string) ([]models.Exam, error)
+	GetExamByID(id uint) (*models.Exam, error)
+	CreateExam(exam *models.Exam) error
+	UpdateExam(exam *models.Exam) error
+	// DeleteExam deletes the exam and its results.
+	DeleteExam(id uint) error
+	// ChangeExamStatus moves the exam from change.From to change.To and
+	// records the change. It returns ErrConflict if the exam's status is
+	// no longer change.From. Publishing marks or reopening published ones
+	// recomputes the marks of the class's students in the same
+	// transaction.
+	ChangeExamStatus(change *models.ExamStatusChange) error
+	GetExamStatusChanges(examID uint) ([]models.ExamStatusChange, error)
+	// GetScoreBreakdowns scores the students over the class's exams in the
+	// term in effect, the active term of its school. Only published exams
+	// are graded and show marks.
+	GetScoreBreakdowns(classID uint, studentIDs []uint) (map[uint]assessment.Breakdown, error)
+	// MarksComputed reports whether the marks of the class's students are
+	// computed from published exams of the term in effect. Such marks are
+	// only changed through the exams; setting them returns
+	// ErrMarksComputed.
+	MarksComputed(classID uint) (bool, error)
+
+	GetResultsByExamIDs(examIDs []uint) ([]models.ExamResult, error)
+	// SaveResults inserts the results of the exam, replacing existing
+	// results of the same student. It returns ErrConflict unless the exam is
+	// a draft.
+	SaveResults(examID uint, results []models.ExamResult) error
+
+	// SaveClassMarks sets the marks of students of the class in one
+	// transaction, and SaveExamMarks those of students in a draft exam. A
+	// cell whose version no longer matches the stored marks_updated_at of
+	// the student or updated_at of the exam result, or whose
+	// student left the class, is stale: then nothing is saved and they
+	// return the stale cells' student IDs with ErrConflict. Rows are
+	// locked in student ID order, so concurrent saves cannot deadlock.
+	SaveClassMarks(classID uint, cells []MarksCell) ([]uint, error)
+	SaveExamMarks(examID uint, cells []MarksCell) ([]uint, error)
+}
+
+// MarksCell is a changed cell of a marks grid. Version is when the marks
+// last changed as read by the client, nil if the student had none.
+type MarksCell struct {
+	StudentID uint
+	Marks     int
+	Version   *time.Time
+}
+
+// current reports whether version is still when the marks last changed.
+func (c MarksCell) current(updatedAt *time.Time) bool {
+	if c.Version == nil || updatedAt == nil {
+		return c.Version == nil && updatedAt == nil
+	}
+	return c.Version.Equal(*updatedAt)
+}
+
+type examRepository struct {
+	db *gorm.DB
+}
+
+func NewExamRepository(db *gorm.DB) ExamRepository {
+	return &examRepository{
+		db: db,
+	}
+}
+
+func (r *examRepository) GetExamsByClassID(classID uint) ([]models.Exam, error) {
+	var exams []models.Exam
+	result := r.db.Where("class_id = ?", classID).Order("term_id, subject, date, id").Find(&exams)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return exams, nil
+}
+
+func (r *examRepository) GetPublishedExams(scope StudentScope, subject string) ([]models.Exam, error) {
+	var exams []models.Exam
+	query := r.db.Where("subject = ? AND status = ?", subject, models.MarksPublished)
+	if scope.ClassID != 0 {
+		query = query.Where("class_id = ?", scope.ClassID)
+	}
+	if scope.SchoolID != 0 {
+		query = query.Where("class_id IN (?)", r.db.Model(&models.Class{}).Select("id").Where("school_id = ?", scope.SchoolID))
+	}
+	result := query.Order("class_id, term_id, date, id").Find(&exams)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return exams, nil
+}
+
+func (r *examRepository) GetExamByID(id uint) (*models.Exam, error) {
+	var exam models.Exam
+	result := r.db.First(&exam, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &exam, nil
+}
+
+func (r *examRepository) CreateExam(exam *models.Exam) error {
+	return r.db.Create(exam).Error
+}
+
+func (r *examRepository) UpdateExam(exam *models.Exam) error {
+	return r.db.Save(exam).Error
+}
+
+func (r *examRepository) DeleteExam(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("exam_id = ?", id).Delete(&models.ExamResult{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Exam{}, id).Error
+	})
+}
+
+func (r *examRepository) ChangeExamStatus(change *models.ExamStatusChange) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var exam models.Exam
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&exam, change.ExamID).Error; err != nil {
+			return err
+		}
+		if exam.Status != change.From {
+			return ErrConflict
+		}
+		if err := tx.Model(&exam).Update("status", change.To).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(change).Error; err != nil {
+			return err
+		}
+		if change.From == models.MarksPublished || change.To == models.MarksPublished {
+			return recomputeMarks(tx, exam.ClassID)
+		}
+		return nil
+	})
+}
+
+func (r *examRepository) GetExamStatusChanges(examID uint) ([]models.ExamStatusChange, error) {
+	var changes []models.ExamStatusChange
+	result := r.db.Where("exam_id = ?", examID).Order("id").Find(&changes)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return changes, nil
+}
+
+func (r *examRepository) GetScoreBreakdowns(classID uint, studentIDs []uint) (map[uint]assessment.Breakdown, error) {
+	return scoreBreakdowns(r.db, classID, studentIDs)
+}
+
+func (r *examRepository) MarksComputed(classID uint) (bool, error) {
+	return marksComputed(r.db, classID)
+}
+
+func (r *examRepository) GetResultsByExamIDs(examIDs []uint) ([]models.ExamResult, error) {
+	var results []models.ExamResult
+	if len(examIDs) == 0 {
+		return results, nil
+	}
+	result := r.db.Where("exam_id IN ?", examIDs).Order("exam_id, student_id").Find(&results)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+func (r *examRepository) SaveResults(examID uint, results []models.ExamResult) error {
+	if len(results) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var exam models.Exam
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&exam, examID).Error; err != nil {
+			return err
+		}
+		if exam.Status != models.MarksDraft {
+			return ErrConflict
+		}
+		for i := range results {
+			results[i].ExamID = examID
+		}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "exam_id"}, {Name: "student_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"marks", "updated_at"}),
+		}).Create(&results).Error
+	})
+}
+
+func (r *examRepository) SaveClassMarks(classID uint, cells []MarksCell) ([]uint, error) {
+	var stale []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		core := &repository{db: tx}
+		for _, cell := range byStudent(cells) {
+			var student models.Student
+			found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}), &student, cell.StudentID)
+			if err != nil {
+				return err
+			}
+			if !found || student.ClassID != classID || !cell.current(student.MarksUpdatedAt) {
+				stale = append(stale, cell.StudentID)
+				continue
+			}
+			if len(stale) > 0 {
+				continue
+			}
+			student.Marks = cell.Marks
+			if err := core.UpdateStudent(&student); err != nil {
+				return err
+			}
+		}
+		if len(stale) > 0 {
+			return ErrConflict
+		}
+		return nil
+	})
+	return stale, err
+}
+
+func (r *examRepository) SaveExamMarks(examID uint, cells []MarksCell) ([]uint, error) {
+	var stale []uint
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var exam models.Exam
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&exam, examID).Error; err != nil {
+			return err
+		}
+		if exam.Status != models.MarksDraft {
+			return ErrConflict
+		}
+		for _, cell := range byStudent(cells) {
+			var student models.Student
+			found, err := findOne(tx.Select("id", "class_id"), &student, cell.StudentID)
+			if err != nil {
+				return err
+			}
+			var result models.ExamResult
+			var updatedAt *time.Time
+			graded, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("exam_id = ? AND student_id = ?", examID, cell.StudentID), &result)
+			if err != nil {
+				return err
+			}
+			if graded {
+				updatedAt = &result.UpdatedAt
+			}
+			if !found || student.ClassID != exam.ClassID || !cell.current(updatedAt) {
+				stale = append(stale, cell.StudentID)
+				continue
+			}
+			if len(stale) > 0 {
+				continue
+			}
+			result.ExamID = examID
+			result.StudentID = cell.StudentID
+			result.Marks = cell.Marks
+			if err := tx.Save(&result).Error; err != nil {
+				return err
+			}
+		}
+		if len(stale) > 0 {
+			return ErrConflict
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// byStudent returns a copy of the cells sorted by student ID, the order in
+// which their rows are locked.
+func byStudent(cells []MarksCell) []MarksCell {
+	sorted := append([]MarksCell(nil), cells...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].StudentID < sorted[j].StudentID
+	})
+	return sorted
+}
+
+// termInEffect returns the ID of the active term of the class's school, or
+// zero if it has none.
+func termInEffect(db *gorm.DB, classID uint) (uint, error) {
+	var termIDs []uint
+	result := db.Model(&models.Term{}).
+		Where("active = ? AND school_id IN (?)", true, db.Model(&models.Class{}).Select("school_id").Where("id = ?", classID)).
+		Limit(1).
+		Pluck("id", &termIDs)
+	if result.Error != nil || len(termIDs) == 0 {
+		return 0, result.Error
+	}
+	return termIDs[0], nil
+}
+
+// marksComputed reports whether the class has published exams in the term
+// in effect.
+func marksComputed(db *gorm.DB, classID uint) (bool, error) {
+	termID, err := termInEffect(db, classID)
+	if err != nil {
+		return false, err
+	}
+	return hasPublishedExams(db, classID, termID)
+}
+
+// hasPublishedExams reports whether the class has published exams in the
+// term.
+func hasPublishedExams(db *gorm.DB, classID, termID uint) (bool, error) {
+	var examIDs []uint
+	result := db.Model(&models.Exam{}).
+		Where("class_id = ? AND term_id = ? AND status = ?", classID, termID, models.MarksPublished).
+		Limit(1).
+		Pluck("id", &examIDs)
+	return len(examIDs) > 0, result.Error
+}
+
+func scoreBreakdowns(db *gorm.DB, classID uint, studentIDs []uint) (map[uint]assessment.Breakdown, error) {
+	termID, err := termInEffect(db, classID)
+	if err != nil {
+		return nil, err
+	}
+	var exams []models.Exam
+	if err := db.Where("class_id = ? AND term_id = ?", classID, termID).Order("subject, date, id").Find(&exams).Error; err != nil {
+		return nil, err
+	}
+	var published []uint
+	for _, exam := range exams {
+		if exam.Status == models.MarksPublished {
+			published = append(published, exam.ID)
+		}
+	}
+	var results []models.ExamResult
+	if len(published) > 0 {
+		if err := db.Where("exam_id IN ?", published).Find(&results).Error; err != nil {
+			return nil, err
+		}
+	}
+	graded := make(map[uint]bool)
+	marks := make(map[[2]uint]int, len(results))
+	for _, result := range results {
+		graded[result.ExamID] = true
+		marks[[2]uint{result.ExamID, result.StudentID}] = result.Marks
+	}
+
+	breakdowns := make(map[uint]assessment.Breakdown, len(studentIDs))
+	for _, studentID := range studentIDs {
+		components := make([]assessment.Component, len(exams))
+		for i, exam := range exams {
+			components[i] = assessment.Component{
+				ExamID:   exam.ID,
+				Subject:  exam.Subject,
+				Name:     exam.Name,
+				MaxMarks: exam.MaxMarks,
+				Weight:   exam.Weight,
+				Graded:   graded[exam.ID],
+			}
+			if m, ok := marks[[2]uint{exam.ID, studentID}]; ok {
+				components[i].Marks = &m
+			}
+		}
+		breakdowns[studentID] = assessment.Compute(components)
+	}
+	return breakdowns, nil
+}
+
+// recomputeMarks stores the final score of each student of the class as
+// part of tx if the class has published marks. The students are locked so
+// a concurrent edit either happens before and is overwritten, or after and
+// sees the published marks.
+func recomputeMarks(tx *gorm.DB, classID uint) error {
+	var students []models.Student
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("class_id = ?", classID).Order("id").Find(&students)
+	if result.Error != nil {
+		return result.Error
+	}
+	studentIDs := make([]uint, len(students))
+	for i, student := range students {
+		studentIDs[i] = student.ID
+	}
+	breakdowns, err := scoreBreakdowns(tx, classID, studentIDs)
+	if err != nil {
+		return err
+	}
+	core := &repository{db: tx}
+	for i := range students {
+		student := &students[i]
+		breakdown := breakdowns[student.ID]
+		if !breakdown.Graded || student.Marks == breakdown.Marks() {
+			continue
+		}
+		student.Marks = breakdown.Marks()
+		if err := core.updateStudent(student, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
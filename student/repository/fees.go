@@ -0,0 +1,312 @@
+package repository
+
+import (
+	"errors"
+	"stu/fees"
+	"stu/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrOverpayment is returned when a payment exceeds the balance of the
+// invoice it settles.
+var ErrOverpayment = errors.New("payment exceeds the invoice balance")
+
+type FeeRepository interface {
+	// Fee item methods
+	GetFeeItemsBySchoolID(schoolID uint) ([]models.FeeItem, error)
+	GetFeeItemByID(id uint) (*models.FeeItem, error)
+	CreateFeeItem(item *models.FeeItem) error
+	UpdateFeeItem(item *models.FeeItem) error
+	DeleteFeeItem(id uint) error
+
+	// Discount methods
+	GetDiscountsByStudentIDs(studentIDs []uint) ([]models.Discount, error)
+	GetDiscountByID(id uint) (*models.Discount, error)
+	CreateDiscount(discount *models.Discount) error
+	DeleteDiscount(id uint) error
+
+	// Invoice methods
+	// GetInvoices returns the matching invoices with their payments
+	// totalled, without lines.
+	GetInvoices(filter InvoiceFilter) ([]models.Invoice, error)
+	// GetInvoiceByID returns the invoice with its lines and payments
+	// totalled.
+	GetInvoiceByID(id uint) (*models.Invoice, error)
+	GetInvoicedStudentIDs(termID uint) ([]uint, error)
+	// CreateInvoices inserts the invoices with their lines and posts them
+	// to the ledger in one transaction.
+	CreateInvoices(invoices []models.Invoice) error
+
+	// Payment and ledger methods
+	GetPaymentsByInvoiceID(invoiceID uint) ([]models.Payment, error)
+	// CreatePayment records the payment and posts it to the ledger. It
+	// returns ErrOverpayment if it exceeds the invoice balance.
+	CreatePayment(payment *models.Payment) error
+	// GetLedgerEntries returns every entry posted for the student's
+	// invoices and payments, oldest first.
+	GetLedgerEntries(studentID uint) ([]models.LedgerEntry, error)
+	// GetBalances returns the receivable balance of each student with
+	// matching invoices who still owes money, largest first.
+	GetBalances(filter InvoiceFilter) ([]Balance, error)
+}
+
+// InvoiceFilter narrows invoice queries. Zero fields match everything.
+type InvoiceFilter struct {
+	StudentID uint
+	TermID    uint
+	SchoolID  uint
+	ClassID   uint
+}
+
+// Balance is what a student was invoiced, has paid and still owes.
+type Balance struct {
+	StudentID uint  `json:"student_id"`
+	Invoiced  int64 `json:"invoiced"`
+	Paid      int64 `json:"paid"`
+	Balance   int64 `json:"balance"`
+}
+
+type feeRepository struct {
+	db *gorm.DB
+}
+
+func NewFeeRepository(db *gorm.DB) FeeRepository {
+	return &feeRepository{
+		db: db,
+	}
+}
+
+// Fee item methods
+
+func (r *feeRepository) GetFeeItemsBySchoolID(schoolID uint) ([]models.FeeItem, error) {
+	var items []models.FeeItem
+	result := r.db.Where("school_id = ?", schoolID).Order("class_id, id").Find(&items)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return items, nil
+}
+
+func (r *feeRepository) GetFeeItemByID(id uint) (*models.FeeItem, error) {
+	var item models.FeeItem
+	result := r.db.First(&item, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &item, nil
+}
+
+func (r *feeRepository) CreateFeeItem(item *models.FeeItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *feeRepository) UpdateFeeItem(item *models.FeeItem) error {
+	return r.db.Save(item).Error
+}
+
+func (r *feeRepository) DeleteFeeItem(id uint) error {
+	return r.db.Delete(&models.FeeItem{}, id).Error
+}
+
+// Discount methods
+
+func (r *feeRepository) GetDiscountsByStudentIDs(studentIDs []uint) ([]models.Discount, error) {
+	var discounts []models.Discount
+	if len(studentIDs) == 0 {
+		return discounts, nil
+	}
+	result := r.db.Where("student_id IN ?", studentIDs).Order("id").Find(&discounts)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return discounts, nil
+}
+
+func (r *feeRepository) GetDiscountByID(id uint) (*models.Discount, error) {
+	var discount models.Discount
+	result := r.db.First(&discount, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &discount, nil
+}
+
+func (r *feeRepository) CreateDiscount(discount *models.Discount) error {
+	return r.db.Create(discount).Error
+}
+
+func (r *feeRepository) DeleteDiscount(id uint) error {
+	return r.db.Delete(&models.Discount{}, id).Error
+}
+
+// Invoice methods
+
+func (r *feeRepository) GetInvoices(filter InvoiceFilter) ([]models.Invoice, error) {
+	var invoices []models.Invoice
+	result := r.filteredInvoices(filter).Order("invoices.id").Find(&invoices)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if err := r.fillPaid(invoices); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *feeRepository) GetInvoiceByID(id uint) (*models.Invoice, error) {
+	var invoice models.Invoice
+	if err := r.db.First(&invoice, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("invoice_id = ?", id).Order("id").Find(&invoice.Lines).Error; err != nil {
+		return nil, err
+	}
+	invoices := []models.Invoice{invoice}
+	if err := r.fillPaid(invoices); err != nil {
+		return nil, err
+	}
+	return &invoices[0], nil
+}
+
+func (r *feeRepository) GetInvoicedStudentIDs(termID uint) ([]uint, error) {
+	var ids []uint
+	result := r.db.Model(&models.Invoice{}).Where("term_id = ?", termID).Pluck("student_id", &ids)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return ids, nil
+}
+
+func (r *feeRepository) CreateInvoices(invoices []models.Invoice) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range invoices {
+			invoice := &invoices[i]
+			if err := tx.Create(invoice).Error; err != nil {
+				return err
+			}
+			for j := range invoice.Lines {
+				invoice.Lines[j].InvoiceID = invoice.ID
+			}
+			if len(invoice.Lines) > 0 {
+				if err := tx.Create(&invoice.Lines).Error; err != nil {
+					return err
+				}
+			}
+			entries := fees.InvoiceEntries(*invoice)
+			if err := tx.Create(&entries).Error; err != nil {
+				return err
+			}
+			invoice.Balance = invoice.Total
+		}
+		return nil
+	})
+}
+
+// Payment and ledger methods
+
+func (r *feeRepository) GetPaymentsByInvoiceID(invoiceID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	result := r.db.Where("invoice_id = ?", invoiceID).Order("paid_at, id").Find(&payments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return payments, nil
+}
+
+func (r *feeRepository) CreatePayment(payment *models.Payment) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var invoice models.Invoice
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&invoice, payment.InvoiceID).Error; err != nil {
+			return err
+		}
+		var paid int64
+		if err := tx.Model(&models.Payment{}).Where("invoice_id = ?", invoice.ID).
+			Select("COALESCE(SUM(amount), 0)").Scan(&paid).Error; err != nil {
+			return err
+		}
+		if paid+payment.Amount > invoice.Total {
+			return ErrOverpayment
+		}
+		payment.StudentID = invoice.StudentID
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		entries := fees.PaymentEntries(*payment)
+		return tx.Create(&entries).Error
+	})
+}
+
+func (r *feeRepository) GetLedgerEntries(studentID uint) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	result := r.db.Where("invoice_id IN (?)", r.db.Model(&models.Invoice{}).Select("id").Where("student_id = ?", studentID)).
+		Order("id").Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (r *feeRepository) GetBalances(filter InvoiceFilter) ([]Balance, error) {
+	var balances []Balance
+	result := r.filteredInvoices(filter).
+		Joins("JOIN ledger_entries ON ledger_entries.invoice_id = invoices.id AND ledger_entries.account = ?", models.AccountReceivable).
+		Select(`invoices.student_id,
+			SUM(ledger_entries.debit) AS invoiced,
+			SUM(ledger_entries.credit) AS paid,
+			SUM(ledger_entries.debit) - SUM(ledger_entries.credit) AS balance`).
+		Group("invoices.student_id").
+		Having("SUM(ledger_entries.debit) - SUM(ledger_entries.credit) > 0").
+		Order("balance DESC, invoices.student_id").
+		Scan(&balances)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return balances, nil
+}
+
+func (r *feeRepository) filteredInvoices(filter InvoiceFilter) *gorm.DB {
+	query := r.db.Model(&models.Invoice{})
+	if filter.StudentID != 0 {
+		query = query.Where("invoices.student_id = ?", filter.StudentID)
+	}
+	if filter.TermID != 0 {
+		query = query.Where("invoices.term_id = ?", filter.TermID)
+	}
+	if filter.SchoolID != 0 {
+		query = query.Where("invoices.school_id = ?", filter.SchoolID)
+	}
+	if filter.ClassID != 0 {
+		query = query.Where("invoices.class_id = ?", filter.ClassID)
+	}
+	return query
+}
+
+// fillPaid sets Paid and Balance of the invoices from their payments.
+func (r *feeRepository) fillPaid(invoices []models.Invoice) error {
+	if len(invoices) == 0 {
+		return nil
+	}
+	ids := make([]uint, len(invoices))
+	for i, invoice := range invoices {
+		ids[i] = invoice.ID
+	}
+	var totals []struct {
+		InvoiceID uint
+		Paid      int64
+	}
+	if err := r.db.Model(&models.Payment{}).Select("invoice_id, SUM(amount) AS paid").
+		Where("invoice_id IN ?", ids).Group("invoice_id").Scan(&totals).Error; err != nil {
+		return err
+	}
+	paid := make(map[uint]int64, len(totals))
+	for _, total := range totals {
+		paid[total.InvoiceID] = total.Paid
+	}
+	for i := range invoices {
+		invoices[i].Paid = paid[invoices[i].ID]
+		invoices[i].Balance = invoices[i].Total - invoices[i].Paid
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"stu/models"
+
+	"gorm.io/gorm"
+)
+
+type GuardianRepository interface {
+	// Guardian methods
+	GetGuardians() ([]models.Guardian, error)
+	GetGuardiansPage(offset, limit int) ([]models.Guardian, int64, error)
+	GetGuardianByID(id uint) (*models.Guardian, error)
+	GetGuardiansByIDs(ids []uint) ([]models.Guardian, error)
+	CreateGuardian(guardian *models.Guardian) error
+	UpdateGuardian(guardian *models.Guardian) error
+	// DeleteGuardian deletes the guardian and their links to students.
+	DeleteGuardian(id uint) error
+
+	// Link methods
+	// GetLinksByStudentID returns the student's links by priority.
+	GetLinksByStudentID(studentID uint) ([]models.StudentGuardian, error)
+	GetLinksByGuardianID(guardianID uint) ([]models.StudentGuardian, error)
+	GetLink(studentID, guardianID uint) (*models.StudentGuardian, error)
+	CreateLink(link *models.StudentGuardian) error
+	UpdateLink(link *models.StudentGuardian) error
+	// DeleteLink returns gorm.ErrRecordNotFound if the guardian is not
+	// linked to the student.
+	DeleteLink(studentID, guardianID uint) error
+}
+
+type guardianRepository struct {
+	db *gorm.DB
+}
+
+func NewGuardianRepository(db *gorm.DB) GuardianRepository {
+	return &guardianRepository{
+		db: db,
+	}
+}
+
+// Guardian methods
+
+func (r *guardianRepository) GetGuardians() ([]models.Guardian, error) {
+	var guardians []models.Guardian
+	result := r.db.Find(&guardians)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return guardians, nil
+}
+
+func (r *guardianRepository) GetGuardiansPage(offset, limit int) ([]models.Guardian, int64, error) {
+	var guardians []models.Guardian
+	var total int64
+	if err := r.db.Model(&models.Guardian{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	result := r.db.Order("id").Offset(offset).Limit(limit).Find(&guardians)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return guardians, total, nil
+}
+
+func (r *guardianRepository) GetGuardianByID(id uint) (*models.Guardian, error) {
+	var guardian models.Guardian
+	result := r.db.First(&guardian, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &guardian, nil
+}
+
+func (r *guardianRepository) GetGuardiansByIDs(ids []uint) ([]models.Guardian, error) {
+	var guardians []models.Guardian
+	if len(ids) == 0 {
+		return guardians, nil
+	}
+	result := r.db.Where("id IN ?", ids).Find(&guardians)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return guardians, nil
+}
+
+func (r *guardianRepository) CreateGuardian(guardian *models.Guardian) error {
+	return r.db.Create(guardian).Error
+}
+
+func (r *guardianRepository) UpdateGuardian(guardian *models.Guardian) error {
+	return r.db.Save(guardian).Error
+}
+
+func (r *guardianRepository) DeleteGuardian(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("guardian_id = ?", id).Delete(&models.StudentGuardian{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Guardian{}, id).Error
+	})
+}
+
+// Link methods
+
+func (r *guardianRepository) GetLinksByStudentID(studentID uint) ([]models.StudentGuardian, error) {
+	var links []models.StudentGuardian
+	result := r.db.Where("student_id = ?", studentID).Order("priority, id").Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+func (r *guardianRepository) GetLinksByGuardianID(guardianID uint) ([]models.StudentGuardian, error) {
+	var links []models.StudentGuardian
+	result := r.db.Where("guardian_id = ?", guardianID).Order("student_id").Find(&links)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return links, nil
+}
+
+func (r *guardianRepository) GetLink(studentID, guardianID uint) (*models.StudentGuardian, error) {
+	var link models.StudentGuardian
+	result := r.db.Where("student_id = ? AND guardian_id = ?", studentID, guardianID).First(&link)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &link, nil
+}
+
+func (r *guardianRepository) CreateLink(link *models.StudentGuardian) error {
+	return r.db.Create(link).Error
+}
+
+func (r *guardianRepository) UpdateLink(link *models.StudentGuardian) error {
+	return r.db.Save(link).Error
+}
+
+func (r *guardianRepository) DeleteLink(studentID, guardianID uint) error {
+	result := r.db.Where("student_id = ? AND guardian_id = ?", studentID, guardianID).Delete(&models.StudentGuardian{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package repository
+
+import (
+	"encoding/json"
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// outboxLockID is the transaction-scoped advisory lock that lets a single
+// relay at a time publish the outbox, which keeps events in order.
+const outboxLockID = 72_657_302
+
+// outboxSequenceLockID is the transaction-scoped advisory lock held while
+// positions are assigned to newly committed events.
+const outboxSequenceLockID = 72_657_303
+
+// sequenceBatchSize bounds how many events are positioned at once.
+const sequenceBatchSize = 1000
+
+type OutboxRepository interface {
+	// ProcessOutbox passes up to limit unpublished events, in position
+	// order, to publish and marks them published if it returns nil. When
+	// another replica holds the outbox it returns 0 without calling publish.
+	ProcessOutbox(limit int, publish func(batch []models.OutboxEvent) error) (int, error)
+	// GetOutboxEventsAfter returns up to limit events positioned after
+	// position that match filter, in position order. Events that commit
+	// later are positioned later, so a reader that resumes from the last
+	// position it has seen misses nothing.
+	GetOutboxEventsAfter(position uint64, filter OutboxFilter, limit int) ([]models.OutboxEvent, error)
+}
+
+// OutboxFilter narrows outbox queries; zero fields match everything.
+type OutboxFilter struct {
+	EntityType string
+	SchoolID   uint
+	ClassID    uint
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{
+		db: db,
+	}
+}
+
+func (r *outboxRepository) ProcessOutbox(limit int, publish func(batch []models.OutboxEvent) error) (int, error) {
+	if err := sequenceOutbox(r.db); err != nil {
+		return 0, err
+	}
+	count := 0
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			var locked bool
+			if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxLockID).Scan(&locked).Error; err != nil {
+				return err
+			}
+			if !locked {
+				return nil
+			}
+		}
+
+		var batch []models.OutboxEvent
+		result := tx.Where("published_at IS NULL AND position IS NOT NULL").Order("position").Limit(limit).Find(&batch)
+		if result.Error != nil {
+			return result.Error
+		}
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := publish(batch); err != nil {
+			return err
+		}
+
+		ids := make([]uint64, len(batch))
+		for i, event := range batch {
+			ids[i] = event.ID
+		}
+		count = len(batch)
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).Update("published_at", time.Now()).Error
+	})
+	return count, err
+}
+
+func (r *outboxRepository) GetOutboxEventsAfter(position uint64, filter OutboxFilter, limit int) ([]models.OutboxEvent, error) {
+	if err := sequenceOutbox(r.db); err != nil {
+		return nil, err
+	}
+	var rows []models.OutboxEvent
+	query := r.db.Where("position > ?", position)
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.SchoolID != 0 {
+		query = query.Where("school_id = ?", filter.SchoolID)
+	}
+	if filter.ClassID != 0 {
+		query = query.Where("class_id = ?", filter.ClassID)
+	}
+	result := query.Order("position").Limit(limit).Find(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return rows, nil
+}
+
+// sequenceOutbox positions the committed events that have no position yet,
+// in ID order, after the highest position so far. One transaction at a time
+// assigns positions and commits them before the next one starts, so a
+// reader that sees a position also sees every lower one, and events whose
+// transactions are still open are positioned after them once they commit.
+func sequenceOutbox(db *gorm.DB) error {
+	var pending []uint64
+	if err := db.Model(&models.OutboxEvent{}).Where("position IS NULL").Limit(1).Pluck("id", &pending).Error; err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if tx.Dialector.Name() == "postgres" {
+			if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", outboxSequenceLockID).Error; err != nil {
+				return err
+			}
+		}
+		var ids []uint64
+		if err := tx.Model(&models.OutboxEvent{}).Where("position IS NULL").Order("id").Limit(sequenceBatchSize).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+		var last uint64
+		if err := tx.Model(&models.OutboxEvent{}).Select("COALESCE(MAX(position), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		for _, id := range ids {
+			last++
+			if err := tx.Model(&models.OutboxEvent{}).Where("id = ?", id).Update("position", last).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleted is the payload of *.deleted events.
+type deleted struct {
+	ID uint `json:"id"`
+}
+
+// marksChanged is the payload of student.marks_changed events.
+type marksChanged struct {
+	Student       *models.Student `json:"student"`
+	PreviousMarks int             `json:"previous_marks"`
+}
+
+// recordEvent writes an event to the outbox as part of tx.
+func recordEvent(tx *gorm.DB, eventType, entityType string, entityID, schoolID, classID uint, data interface{}) error {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&models.OutboxEvent{
+		Type:       eventType,
+		EntityType: entityType,
+		EntityID:   entityID,
+		SchoolID:   schoolID,
+		ClassID:    classID,
+		Data:       string(raw),
+		OccurredAt: time.Now().UTC(),
+	}).Error
+}
+
+func recordSchoolEvent(tx *gorm.DB, eventType string, id uint, data interface{}) error {
+	return recordEvent(tx, eventType, "school", id, id, 0, data)
+}
+
+func recordClassEvent(tx *gorm.DB, eventType string, id, schoolID uint, data interface{}) error {
+	return recordEvent(tx, eventType, "class", id, schoolID, id, data)
+}
+
+// recordStudentEvent scopes the event to the student's class and that
+// class's school.
+func recordStudentEvent(tx *gorm.DB, eventType string, id, classID uint, data interface{}) error {
+	var schoolID uint
+	if classID != 0 {
+		var class models.Class
+		if _, err := findOne(tx.Select("id", "school_id"), &class, classID); err != nil {
+			return err
+		}
+		schoolID = class.SchoolID
+	}
+	return recordEvent(tx, eventType, "student", id, schoolID, classID, data)
+}
@@ -0,0 +1,76 @@
+package repository_test
+
+import (
+	"path/filepath"
+	"stu/models"
+	"stu/repository"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openOutboxDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "outbox.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&models.OutboxEvent{}))
+	return db
+}
+
+// commitEvent stands in for a writer's transaction committing an event
+// whose ID it took when it wrote it.
+func commitEvent(t *testing.T, db *gorm.DB, id uint64) {
+	t.Helper()
+	assert.Nil(t, db.Create(&models.OutboxEvent{ID: id, Type: "student.updated", EntityType: "student", EntityID: uint(id)}).Error)
+}
+
+func ids(rows []models.OutboxEvent) []uint64 {
+	var ids []uint64
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+func TestOutbox_EventCommittedLateIsNotSkipped(t *testing.T) {
+	db := openOutboxDB(t)
+	repo := repository.NewOutboxRepository(db)
+
+	// Two writers take IDs 1 and 2; the second commits first and a reader
+	// catches up before the first commits.
+	commitEvent(t, db, 2)
+	rows, err := repo.GetOutboxEventsAfter(0, repository.OutboxFilter{}, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{2}, ids(rows))
+	cursor := *rows[0].Position
+
+	commitEvent(t, db, 1)
+	commitEvent(t, db, 3)
+	rows, err = repo.GetOutboxEventsAfter(cursor, repository.OutboxFilter{}, 10)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 3}, ids(rows))
+	assert.Greater(t, *rows[0].Position, cursor)
+}
+
+func TestOutbox_RelayPublishesInCommitOrder(t *testing.T) {
+	db := openOutboxDB(t)
+	repo := repository.NewOutboxRepository(db)
+	commitEvent(t, db, 2)
+	_, err := repo.GetOutboxEventsAfter(0, repository.OutboxFilter{}, 10)
+	assert.Nil(t, err)
+	commitEvent(t, db, 1)
+
+	var published []uint64
+	count, err := repo.ProcessOutbox(10, func(batch []models.OutboxEvent) error {
+		published = append(published, ids(batch)...)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+	assert.Equal(t, []uint64{2, 1}, published)
+}
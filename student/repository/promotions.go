@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"stu/events"
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PromotionRepository interface {
+	GetPromotionPlansBySchoolID(schoolID uint) ([]models.PromotionPlan, error)
+	// GetPromotionPlanByID returns the plan with its entries.
+	GetPromotionPlanByID(id uint) (*models.PromotionPlan, error)
+	// CreatePromotionPlan inserts the plan and its entries.
+	CreatePromotionPlan(plan *models.PromotionPlan) error
+	UpdatePromotionEntry(entry *models.PromotionEntry) error
+	DeletePromotionPlan(id uint) error
+	// ApplyPromotionPlan moves every student of a draft plan to their
+	// planned class and takes graduates out of their class, recording
+	// student.graduated, all in one transaction. It
+	// returns ErrConflict if the plan is no longer a draft or a student
+	// changed class or left since the plan was drafted, and ErrClassFull
+	// if a class would hold more students than its capacity once every
+	// student has moved. Waitlisted students only take the seats left
+	// free after that.
+	ApplyPromotionPlan(id uint) error
+}
+
+type promotionRepository struct {
+	db *gorm.DB
+}
+
+func NewPromotionRepository(db *gorm.DB) PromotionRepository {
+	return &promotionRepository{
+		db: db,
+	}
+}
+
+func (r *promotionRepository) GetPromotionPlansBySchoolID(schoolID uint) ([]models.PromotionPlan, error) {
+	var plans []models.PromotionPlan
+	result := r.db.Where("school_id = ?", schoolID).Order("id").Find(&plans)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return plans, nil
+}
+
+func (r *promotionRepository) GetPromotionPlanByID(id uint) (*models.PromotionPlan, error) {
+	var plan models.PromotionPlan
+	if err := r.db.First(&plan, id).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Where("plan_id = ?", id).Order("from_class_id, student_id").Find(&plan.Entries).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *promotionRepository) CreatePromotionPlan(plan *models.PromotionPlan) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(plan).Error; err != nil {
+			return err
+		}
+		if len(plan.Entries) == 0 {
+			return nil
+		}
+		for i := range plan.Entries {
+			plan.Entries[i].PlanID = plan.ID
+		}
+		return tx.CreateInBatches(plan.Entries, 500).Error
+	})
+}
+
+func (r *promotionRepository) UpdatePromotionEntry(entry *models.PromotionEntry) error {
+	return r.db.Save(entry).Error
+}
+
+func (r *promotionRepository) DeletePromotionPlan(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("plan_id = ?", id).Delete(&models.PromotionEntry{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.PromotionPlan{}, id).Error
+	})
+}
+
+func (r *promotionRepository) ApplyPromotionPlan(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var plan models.PromotionPlan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&plan, id).Error; err != nil {
+			return err
+		}
+		if plan.Status != models.PromotionDraft {
+			return ErrConflict
+		}
+		var entries []models.PromotionEntry
+		if err := tx.Where("plan_id = ?", id).Order("student_id").Find(&entries).Error; err != nil {
+			return err
+		}
+		seats := &seating{}
+		core := &repository{db: tx, seats: seats}
+		for _, entry := range entries {
+			var student models.Student
+			found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}), &student, entry.StudentID)
+			if err != nil {
+				return err
+			}
+			if !found || student.ClassID != entry.FromClassID {
+				return ErrConflict
+			}
+			switch entry.Outcome {
+			case models.OutcomeGraduated:
+				if err := graduate(tx, seats, &student); err != nil {
+					return err
+				}
+			default:
+				if student.ClassID == entry.ToClassID {
+					continue
+				}
+				student.ClassID = entry.ToClassID
+				if err := core.UpdateStudent(&student); err != nil {
+					return err
+				}
+			}
+		}
+		if err := seats.settle(tx); err != nil {
+			return err
+		}
+		now := time.Now()
+		return tx.Model(&plan).Updates(map[string]interface{}{
+			"status":     models.PromotionApplied,
+			"applied_at": &now,
+		}).Error
+	})
+}
+
+// graduate clears the student's class and frees their seat. Graduates
+// keep their records but leave every waitlist and do not attend the term
+// that is already active.
+func graduate(tx *gorm.DB, seats *seating, student *models.Student) error {
+	classID := student.ClassID
+	now := time.Now()
+	student.ClassID = 0
+	student.GraduatedAt = &now
+	if err := tx.Model(student).Select("class_id", "graduated_at").Updates(student).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("student_id = ?", student.ID).Delete(&models.WaitlistEntry{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("student_id = ? AND term_id IN (?)", student.ID,
+		tx.Model(&models.Term{}).Select("id").Where("active = ?", true)).
+		Delete(&models.Enrollment{}).Error; err != nil {
+		return err
+	}
+	if err := recordStudentEvent(tx, events.StudentGraduated, student.ID, classID, student); err != nil {
+		return err
+	}
+	return seats.free(tx, classID)
+}
@@ -1,36 +1,86 @@
 package repository
 
 import (
+	"database/sql"
+	"sort"
+	"strings"
+	"stu/events"
 	"stu/models"
+	"stu/search"
+	"stu/stats"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	// School methods
 	GetSchools() ([]models.School, error)
+	GetSchoolsPage(offset, limit int) ([]models.School, int64, error)
 	GetSchoolByID(id uint) (*models.School, error)
+	GetSchoolsByIDs(ids []uint) ([]models.School, error)
 	CreateSchool(school *models.School) error
 	UpdateSchool(school *models.School) error
 	DeleteSchool(id uint) error
 
 	// Class methods
 	GetClasses() ([]models.Class, error)
+	GetClassesPage(offset, limit int) ([]models.Class, int64, error)
 	GetClassByID(id uint) (*models.Class, error)
+	GetClassesByIDs(ids []uint) ([]models.Class, error)
+	GetClassesBySchoolIDs(schoolIDs []uint) ([]models.Class, error)
+	// GetClassOccupancy counts the students and waitlisted students of the
+	// classes.
+	GetClassOccupancy(classIDs []uint) (map[uint]models.Occupancy, error)
 	CreateClass(class *models.Class) error
+	// UpdateClass fills seats freed by a larger capacity from the
+	// waitlist.
 	UpdateClass(class *models.Class) error
 	DeleteClass(id uint) error
 
 	// Student methods
 	GetStudents() ([]models.Student, error)
+	GetStudentsPage(offset, limit int) ([]models.Student, int64, error)
 	GetStudentByID(id uint) (*models.Student, error)
+	GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error)
+	// CreateStudent and UpdateStudent return ErrClassFull when the student
+	// would join a class that has no free seat. A student leaving a class,
+	// including by DeleteStudent, gives their seat to the class's
+	// waitlist.
 	CreateStudent(student *models.Student) error
 	UpdateStudent(student *models.Student) error
 	DeleteStudent(id uint) error
+	// SearchStudents returns up to limit students matching every term,
+	// best match first.
+	SearchStudents(terms []string, limit int) ([]ScoredStudent, error)
+
+	// Marks statistics
+	GetMarksSummary(scope StudentScope, passMark int) (*stats.Summary, error)
+	// GetMarksHistogram counts marks into the buckets delimited by edges,
+	// as stats.Histogram does.
+	GetMarksHistogram(scope StudentScope, edges []int) ([]int64, error)
+	GetMarksPercentile(scope StudentScope, marks int) (float64, error)
+	GetMarksRanking(scope StudentScope, dense bool, offset, limit int) ([]RankedStudent, int64, error)
+	// GetTermMarksRanking ranks the students enrolled in the term in a
+	// class in scope by their marks in the term.
+	GetTermMarksRanking(scope StudentScope, termID uint, dense bool, offset, limit int) ([]RankedStudent, int64, error)
+	// GetTermMarksPercentile is GetMarksPercentile among the students
+	// ranked by GetTermMarksRanking.
+	GetTermMarksPercentile(scope StudentScope, termID uint, marks int) (float64, error)
+}
+
+// ScoredStudent is a search result with its relevance score.
+type ScoredStudent struct {
+	Student models.Student `gorm:"embedded"`
+	Score   float64
 }
 
 type repository struct {
 	db *gorm.DB
+	// seats, when set, defers the seat checks of students who change
+	// class.
+	seats *seating
 }
 
 func NewRepository(db *gorm.DB) Repository {
@@ -39,6 +89,13 @@ func NewRepository(db *gorm.DB) Repository {
 	}
 }
 
+// findOne loads the first row of the query into dest and reports whether
+// there was one.
+func findOne(query *gorm.DB, dest interface{}, conds ...interface{}) (bool, error) {
+	result := query.Limit(1).Find(dest, conds...)
+	return result.RowsAffected > 0, result.Error
+}
+
 // School methods
 
 func (r *repository) GetSchools() ([]models.School, error) {
@@ -50,6 +107,19 @@ func (r *repository) GetSchools() ([]models.School, error) {
 	return schools, nil
 }
 
+func (r *repository) GetSchoolsPage(offset, limit int) ([]models.School, int64, error) {
+	var schools []models.School
+	var total int64
+	if err := r.db.Model(&models.School{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	result := r.db.Order("id").Offset(offset).Limit(limit).Find(&schools)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return schools, total, nil
+}
+
 func (r *repository) GetSchoolByID(id uint) (*models.School, error) {
 	var school models.School
 	result := r.db.First(&school, id)
@@ -59,28 +129,43 @@ func (r *repository) GetSchoolByID(id uint) (*models.School, error) {
 	return &school, nil
 }
 
-func (r *repository) CreateSchool(school *models.School) error {
-	result := r.db.Create(school)
+func (r *repository) GetSchoolsByIDs(ids []uint) ([]models.School, error) {
+	var schools []models.School
+	result := r.db.Where("id IN ?", ids).Find(&schools)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	return nil
+	return schools, nil
+}
+
+func (r *repository) CreateSchool(school *models.School) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Create(school)
+		if result.Error != nil {
+			return result.Error
+		}
+		return recordSchoolEvent(tx, events.SchoolCreated, school.ID, school)
+	})
 }
 
 func (r *repository) UpdateSchool(school *models.School) error {
-	result := r.db.Save(school)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Save(school)
+		if result.Error != nil {
+			return result.Error
+		}
+		return recordSchoolEvent(tx, events.SchoolUpdated, school.ID, school)
+	})
 }
 
 func (r *repository) DeleteSchool(id uint) error {
-	result := r.db.Delete(&models.School{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&models.School{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		return recordSchoolEvent(tx, events.SchoolDeleted, id, deleted{ID: id})
+	})
 }
 
 // Class methods
@@ -94,6 +179,19 @@ func (r *repository) GetClasses() ([]models.Class, error) {
 	return classes, nil
 }
 
+func (r *repository) GetClassesPage(offset, limit int) ([]models.Class, int64, error) {
+	var classes []models.Class
+	var total int64
+	if err := r.db.Model(&models.Class{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	result := r.db.Order("id").Offset(offset).Limit(limit).Find(&classes)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return classes, total, nil
+}
+
 func (r *repository) GetClassByID(id uint) (*models.Class, error) {
 	var class models.Class
 	result := r.db.First(&class, id)
@@ -103,28 +201,62 @@ func (r *repository) GetClassByID(id uint) (*models.Class, error) {
 	return &class, nil
 }
 
-func (r *repository) CreateClass(class *models.Class) error {
-	result := r.db.Create(class)
+func (r *repository) GetClassesByIDs(ids []uint) ([]models.Class, error) {
+	var classes []models.Class
+	result := r.db.Where("id IN ?", ids).Find(&classes)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	return nil
+	return classes, nil
 }
 
-func (r *repository) UpdateClass(class *models.Class) error {
-	result := r.db.Save(class)
+func (r *repository) GetClassesBySchoolIDs(schoolIDs []uint) ([]models.Class, error) {
+	var classes []models.Class
+	result := r.db.Where("school_id IN ?", schoolIDs).Order("id").Find(&classes)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	return nil
+	return classes, nil
+}
+
+func (r *repository) CreateClass(class *models.Class) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Create(class)
+		if result.Error != nil {
+			return result.Error
+		}
+		return recordClassEvent(tx, events.ClassCreated, class.ID, class.SchoolID, class)
+	})
+}
+
+func (r *repository) UpdateClass(class *models.Class) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Save(class)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := recordClassEvent(tx, events.ClassUpdated, class.ID, class.SchoolID, class); err != nil {
+			return err
+		}
+		return fillSeats(tx, class.ID)
+	})
 }
 
 func (r *repository) DeleteClass(id uint) error {
-	result := r.db.Delete(&models.Class{}, id)
-	if result.Error != nil {
-		return result.Error
-	}
-	return nil
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var class models.Class
+		if _, err := findOne(tx.Select("id", "school_id"), &class, id); err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Class{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.Where("class_id = ?", id).Delete(&models.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		return recordClassEvent(tx, events.ClassDeleted, id, class.SchoolID, deleted{ID: id})
+	})
 }
 
 // Student methods
@@ -138,6 +270,19 @@ func (r *repository) GetStudents() ([]models.Student, error) {
 	return students, nil
 }
 
+func (r *repository) GetStudentsPage(offset, limit int) ([]models.Student, int64, error) {
+	var students []models.Student
+	var total int64
+	if err := r.db.Model(&models.Student{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	result := r.db.Order("id").Offset(offset).Limit(limit).Find(&students)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return students, total, nil
+}
+
 func (r *repository) GetStudentByID(id uint) (*models.Student, error) {
 	var student models.Student
 	result := r.db.First(&student, id)
@@ -147,26 +292,191 @@ func (r *repository) GetStudentByID(id uint) (*models.Student, error) {
 	return &student, nil
 }
 
-func (r *repository) CreateStudent(student *models.Student) error {
-	result := r.db.Create(student)
+func (r *repository) GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error) {
+	var students []models.Student
+	result := r.db.Where("class_id IN ?", classIDs).Order("id").Find(&students)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
 	}
-	return nil
+	return students, nil
 }
 
+// CreateStudent gives a student who joins a class with computed marks
+// their computed marks.
+func (r *repository) CreateStudent(student *models.Student) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := takeSeat(tx, student.ClassID, 0); err != nil {
+			return err
+		}
+		now := time.Now()
+		student.MarksUpdatedAt = &now
+		result := tx.Create(student)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := syncEnrollment(tx, student); err != nil {
+			return err
+		}
+		if err := recordStudentEvent(tx, events.StudentCreated, student.ID, student.ClassID, student); err != nil {
+			return err
+		}
+		return recomputeJoined(tx, student)
+	})
+}
+
+// UpdateStudent also records student.marks_changed when the marks differ
+// from the stored row. It returns ErrMarksComputed when the marks of a
+// student whose class has computed marks would change. A student without
+// a stored row is saved as new and, like a student changing class, needs
+// a free seat in their class.
 func (r *repository) UpdateStudent(student *models.Student) error {
-	result := r.db.Save(student)
-	if result.Error != nil {
-		return result.Error
+	return r.updateStudent(student, false)
+}
+
+// updateStudent is UpdateStudent; computed is set when the marks are the
+// student's computed marks.
+func (r *repository) updateStudent(student *models.Student, computed bool) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var previous models.Student
+		found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "marks", "marks_updated_at", "class_id", "graduated_at"), &previous, student.ID)
+		if err != nil {
+			return err
+		}
+		student.MarksUpdatedAt = previous.MarksUpdatedAt
+		// Only promotions graduate students; a graduate stays one until
+		// they join a class again.
+		if student.ClassID == 0 {
+			student.GraduatedAt = previous.GraduatedAt
+		} else {
+			student.GraduatedAt = nil
+		}
+		if !found || previous.Marks != student.Marks {
+			now := time.Now()
+			student.MarksUpdatedAt = &now
+		}
+		if found && previous.Marks != student.Marks && !computed {
+			locked, err := marksComputed(tx, student.ClassID)
+			if err != nil {
+				return err
+			}
+			if locked {
+				return ErrMarksComputed
+			}
+		}
+		moved := !found || previous.ClassID != student.ClassID
+		if moved {
+			if err := r.seats.take(tx, student.ClassID, student.ID); err != nil {
+				return err
+			}
+		}
+		result := tx.Save(student)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := syncEnrollment(tx, student); err != nil {
+			return err
+		}
+		if err := recordStudentEvent(tx, events.StudentUpdated, student.ID, student.ClassID, student); err != nil {
+			return err
+		}
+		if found && previous.Marks != student.Marks {
+			if err := recordStudentEvent(tx, events.StudentMarksChanged, student.ID, student.ClassID, marksChanged{
+				Student:       student,
+				PreviousMarks: previous.Marks,
+			}); err != nil {
+				return err
+			}
+		}
+		if moved {
+			if err := r.seats.free(tx, previous.ClassID); err != nil {
+				return err
+			}
+			return recomputeJoined(tx, student)
+		}
+		return nil
+	})
+}
+
+// recomputeJoined computes the marks of a student who joined a class with
+// computed marks.
+func recomputeJoined(tx *gorm.DB, student *models.Student) error {
+	locked, err := marksComputed(tx, student.ClassID)
+	if err != nil || !locked {
+		return err
+	}
+	if err := recomputeMarks(tx, student.ClassID); err != nil {
+		return err
 	}
-	return nil
+	return tx.First(student, student.ID).Error
 }
 
 func (r *repository) DeleteStudent(id uint) error {
-	result := r.db.Delete(&models.Student{}, id)
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var student models.Student
+		if _, err := findOne(tx.Select("id", "class_id"), &student, id); err != nil {
+			return err
+		}
+		result := tx.Delete(&models.Student{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.Where("student_id = ?", id).Delete(&models.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+		if err := recordStudentEvent(tx, events.StudentDeleted, id, student.ClassID, deleted{ID: id}); err != nil {
+			return err
+		}
+		return fillSeats(tx, student.ClassID)
+	})
+}
+
+// SearchStudents uses the search_vector column and pg_trgm on Postgres: a
+// student matches if every term prefixes a word of the vector or the
+// query is similar to a word of the name. Other databases scan the table
+// and score in Go.
+func (r *repository) SearchStudents(terms []string, limit int) ([]ScoredStudent, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		return r.searchStudentsPortable(terms, limit)
+	}
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+	var results []ScoredStudent
+	result := r.db.Raw(`SELECT students.*, ts_rank(search_vector, query) + word_similarity(@text, name) AS score
+		FROM students, to_tsquery('simple', @tsquery) AS query
+		WHERE deleted_at IS NULL AND (search_vector @@ query OR @text <% name)
+		ORDER BY score DESC, id
+		LIMIT @limit`,
+		sql.Named("text", strings.Join(terms, " ")),
+		sql.Named("tsquery", strings.Join(prefixes, " & ")),
+		sql.Named("limit", limit),
+	).Scan(&results)
 	if result.Error != nil {
-		return result.Error
+		return nil, result.Error
+	}
+	return results, nil
+}
+
+func (r *repository) searchStudentsPortable(terms []string, limit int) ([]ScoredStudent, error) {
+	var results []ScoredStudent
+	var batch []models.Student
+	result := r.db.FindInBatches(&batch, 500, func(tx *gorm.DB, _ int) error {
+		for _, student := range batch {
+			if score, ok := search.Score(search.StudentFields(student), terms); ok {
+				results = append(results, ScoredStudent{Student: student, Score: score})
+			}
+		}
+		return nil
+	})
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		return results[i].Score > results[j].Score
+	})
+	if len(results) > limit {
+		results = results[:limit]
 	}
-	return nil
+	return results, nil
 }
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"strconv"
+	"strings"
+	"stu/models"
+	"stu/stats"
+
+	"gorm.io/gorm"
+)
+
+// StudentScope selects the students of a class or of every class of a
+// school; the zero scope selects all students but graduates.
+type StudentScope struct {
+	SchoolID uint
+	ClassID  uint
+}
+
+func (r *repository) scopedStudents(scope StudentScope) *gorm.DB {
+	query := r.db.Model(&models.Student{}).Where("graduated_at IS NULL")
+	if scope.ClassID != 0 {
+		query = query.Where("class_id = ?", scope.ClassID)
+	}
+	if scope.SchoolID != 0 {
+		query = query.Where("class_id IN (?)", r.db.Model(&models.Class{}).Select("id").Where("school_id = ?", scope.SchoolID))
+	}
+	return query
+}
+
+// The statistics are computed by Postgres; other databases load the marks
+// and use the stats package.
+
+func (r *repository) GetMarksSummary(scope StudentScope, passMark int) (*stats.Summary, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		marks, err := r.scopedMarks(scope)
+		if err != nil {
+			return nil, err
+		}
+		summary := stats.Summarize(marks, passMark)
+		return &summary, nil
+	}
+	var summary stats.Summary
+	result := r.scopedStudents(scope).Select(`count(*) AS count,
+		coalesce(avg(marks), 0) AS mean,
+		coalesce(stddev_pop(marks), 0) AS std_dev,
+		coalesce(min(marks), 0) AS min,
+		coalesce(max(marks), 0) AS max,
+		coalesce(percentile_cont(0.25) WITHIN GROUP (ORDER BY marks), 0) AS q1,
+		coalesce(percentile_cont(0.5) WITHIN GROUP (ORDER BY marks), 0) AS median,
+		coalesce(percentile_cont(0.75) WITHIN GROUP (ORDER BY marks), 0) AS q3,
+		count(*) FILTER (WHERE marks >= ?) AS passed`, passMark).Scan(&summary)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &summary, nil
+}
+
+func (r *repository) GetMarksHistogram(scope StudentScope, edges []int) ([]int64, error) {
+	if r.db.Dialector.Name() != "postgres" {
+		marks, err := r.scopedMarks(scope)
+		if err != nil {
+			return nil, err
+		}
+		return stats.Histogram(marks, edges), nil
+	}
+	bounds := make([]string, len(edges))
+	for i, edge := range edges {
+		bounds[i] = strconv.Itoa(edge)
+	}
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	result := r.scopedStudents(scope).
+		Select("width_bucket(marks, ?::int[]) AS bucket, count(*) AS count", "{"+strings.Join(bounds, ",")+"}").
+		Group("bucket").
+		Scan(&rows)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	counts := make([]int64, len(edges)+1)
+	for _, row := range rows {
+		counts[row.Bucket] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *repository) scopedMarks(scope StudentScope) ([]int, error) {
+	var marks []int
+	result := r.scopedStudents(scope).Pluck("marks", &marks)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return marks, nil
+}
+
+// RankedStudent is a student with the marks they were ranked by, their
+// rank and their percentile in the ranked scope.
+type RankedStudent struct {
+	Student    models.Student `gorm:"embedded"`
+	Score      float64
+	Rank       int64
+	Percentile float64
+}
+
+// GetMarksPercentile returns the percentile of marks among the students
+// in scope, counting ties as half, or 0 if the scope is empty.
+func (r *repository) GetMarksPercentile(scope StudentScope, marks int) (float64, error) {
+	return marksPercentile(r.scopedStudents(scope), "marks", marks)
+}
+
+// marksPercentile returns the percentile of marks among the rows of query
+// by their column, counting ties as half, or 0 if there are none.
+func marksPercentile(query *gorm.DB, column string, marks int) (float64, error) {
+	var row struct {
+		Below int64
+		Equal int64
+		Total int64
+	}
+	result := query.Select(`coalesce(sum(CASE WHEN `+column+` < ? THEN 1 ELSE 0 END), 0) AS below,
+		coalesce(sum(CASE WHEN `+column+` = ? THEN 1 ELSE 0 END), 0) AS equal,
+		count(*) AS total`, marks, marks).Scan(&row)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if row.Total == 0 {
+		return 0, nil
+	}
+	return (float64(row.Below) + float64(row.Equal)/2) * 100 / float64(row.Total), nil
+}
+
+// GetMarksRanking ranks the students in scope by marks, best first. Tied
+// students share a rank; dense ranking numbers the next rank one higher,
+// competition ranking skips as many ranks as were tied.
+func (r *repository) GetMarksRanking(scope StudentScope, dense bool, offset, limit int) ([]RankedStudent, int64, error) {
+	var total int64
+	if result := r.scopedStudents(scope).Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+	rankFunction := "rank()"
+	if dense {
+		rankFunction = "dense_rank()"
+	}
+	var ranked []RankedStudent
+	result := r.scopedStudents(scope).
+		Select("students.*, marks AS score, " + rankFunction + ` OVER (ORDER BY marks DESC) AS rank,
+			(rank() OVER (ORDER BY marks) - 1 + count(*) OVER (PARTITION BY marks) / 2.0) * 100.0 / count(*) OVER () AS percentile`).
+		Order("marks DESC, id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&ranked)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return ranked, total, nil
+}
+
+// GetTermMarksRanking ranks as GetMarksRanking does, by the marks of the
+// students' enrollments in the term. Classes are those the students were
+// enrolled in during the term.
+func (r *repository) GetTermMarksRanking(scope StudentScope, termID uint, dense bool, offset, limit int) ([]RankedStudent, int64, error) {
+	var total int64
+	if result := r.termEnrollments(scope, termID).Count(&total); result.Error != nil {
+		return nil, 0, result.Error
+	}
+	rankFunction := "rank()"
+	if dense {
+		rankFunction = "dense_rank()"
+	}
+	var ranked []RankedStudent
+	result := r.termEnrollments(scope, termID).
+		Select("students.*, enrollments.marks AS score, " + rankFunction + ` OVER (ORDER BY enrollments.marks DESC) AS rank,
+			(rank() OVER (ORDER BY enrollments.marks) - 1 + count(*) OVER (PARTITION BY enrollments.marks) / 2.0) * 100.0 / count(*) OVER () AS percentile`).
+		Order("enrollments.marks DESC, students.id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&ranked)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return ranked, total, nil
+}
+
+func (r *repository) GetTermMarksPercentile(scope StudentScope, termID uint, marks int) (float64, error) {
+	return marksPercentile(r.termEnrollments(scope, termID), "enrollments.marks", marks)
+}
+
+// termEnrollments selects the enrollments in the term, in a class in
+// scope, of students that were not deleted.
+func (r *repository) termEnrollments(scope StudentScope, termID uint) *gorm.DB {
+	query := r.db.Table("enrollments").
+		Joins("JOIN students ON students.id = enrollments.student_id AND students.deleted_at IS NULL").
+		Where("enrollments.term_id = ?", termID)
+	if scope.ClassID != 0 {
+		query = query.Where("enrollments.class_id = ?", scope.ClassID)
+	}
+	if scope.SchoolID != 0 {
+		query = query.Where("enrollments.class_id IN (?)", r.db.Model(&models.Class{}).Select("id").Where("school_id = ?", scope.SchoolID))
+	}
+	return query
+}
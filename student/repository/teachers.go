@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"stu/models"
+
+	"gorm.io/gorm"
+)
+
+type TeacherRepository interface {
+	// Teacher methods
+	GetTeachers() ([]models.Teacher, error)
+	GetTeachersPage(offset, limit int) ([]models.Teacher, int64, error)
+	GetTeacherByID(id uint) (*models.Teacher, error)
+	CreateTeacher(teacher *models.Teacher) error
+	UpdateTeacher(teacher *models.Teacher) error
+	// DeleteTeacher deletes the teacher and their assignments.
+	DeleteTeacher(id uint) error
+
+	// Assignment methods
+	GetAssignmentsByTeacherID(teacherID uint) ([]models.TeacherAssignment, error)
+	GetAssignmentsByClassID(classID uint) ([]models.TeacherAssignment, error)
+	CreateTeacherAssignment(assignment *models.TeacherAssignment) error
+	// DeleteTeacherAssignment deletes an assignment of the teacher and
+	// returns gorm.ErrRecordNotFound if there is none with that ID.
+	DeleteTeacherAssignment(teacherID, id uint) error
+}
+
+type teacherRepository struct {
+	db *gorm.DB
+}
+
+func NewTeacherRepository(db *gorm.DB) TeacherRepository {
+	return &teacherRepository{
+		db: db,
+	}
+}
+
+// Teacher methods
+
+func (r *teacherRepository) GetTeachers() ([]models.Teacher, error) {
+	var teachers []models.Teacher
+	result := r.db.Find(&teachers)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return teachers, nil
+}
+
+func (r *teacherRepository) GetTeachersPage(offset, limit int) ([]models.Teacher, int64, error) {
+	var teachers []models.Teacher
+	var total int64
+	if err := r.db.Model(&models.Teacher{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	result := r.db.Order("id").Offset(offset).Limit(limit).Find(&teachers)
+	if result.Error != nil {
+		return nil, 0, result.Error
+	}
+	return teachers, total, nil
+}
+
+func (r *teacherRepository) GetTeacherByID(id uint) (*models.Teacher, error) {
+	var teacher models.Teacher
+	result := r.db.First(&teacher, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &teacher, nil
+}
+
+func (r *teacherRepository) CreateTeacher(teacher *models.Teacher) error {
+	return r.db.Create(teacher).Error
+}
+
+func (r *teacherRepository) UpdateTeacher(teacher *models.Teacher) error {
+	return r.db.Save(teacher).Error
+}
+
+func (r *teacherRepository) DeleteTeacher(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("teacher_id = ?", id).Delete(&models.TeacherAssignment{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.Teacher{}, id).Error
+	})
+}
+
+// Assignment methods
+
+func (r *teacherRepository) GetAssignmentsByTeacherID(teacherID uint) ([]models.TeacherAssignment, error) {
+	var assignments []models.TeacherAssignment
+	result := r.db.Where("teacher_id = ?", teacherID).Order("class_id, role, subject").Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+func (r *teacherRepository) GetAssignmentsByClassID(classID uint) ([]models.TeacherAssignment, error) {
+	var assignments []models.TeacherAssignment
+	result := r.db.Where("class_id = ?", classID).Order("role, subject, teacher_id").Find(&assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return assignments, nil
+}
+
+func (r *teacherRepository) CreateTeacherAssignment(assignment *models.TeacherAssignment) error {
+	return r.db.Create(assignment).Error
+}
+
+func (r *teacherRepository) DeleteTeacherAssignment(teacherID, id uint) error {
+	result := r.db.Where("teacher_id = ?", teacherID).Delete(&models.TeacherAssignment{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package repository
+
+import (
+	"errors"
+	"slices"
+	"stu/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrClassFull is returned when a student would join a class that has no
+// free seat.
+var ErrClassFull = errors.New("class is full")
+
+type WaitlistRepository interface {
+	// GetWaitlist returns the class's waitlist in seating order.
+	GetWaitlist(classID uint) ([]models.WaitlistEntry, error)
+	GetWaitlistEntry(classID, studentID uint) (*models.WaitlistEntry, error)
+	// AddToWaitlist queues the student and, should a seat be free by now,
+	// seats the waitlist at once.
+	AddToWaitlist(entry *models.WaitlistEntry) error
+	// RemoveFromWaitlist returns gorm.ErrRecordNotFound if the student is
+	// not waiting for the class.
+	RemoveFromWaitlist(classID, studentID uint) error
+}
+
+type waitlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWaitlistRepository(db *gorm.DB) WaitlistRepository {
+	return &waitlistRepository{
+		db: db,
+	}
+}
+
+func (r *waitlistRepository) GetWaitlist(classID uint) ([]models.WaitlistEntry, error) {
+	var entries []models.WaitlistEntry
+	result := r.db.Where("class_id = ?", classID).Order("id").Find(&entries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return entries, nil
+}
+
+func (r *waitlistRepository) GetWaitlistEntry(classID, studentID uint) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	result := r.db.Where("class_id = ? AND student_id = ?", classID, studentID).First(&entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &entry, nil
+}
+
+func (r *waitlistRepository) AddToWaitlist(entry *models.WaitlistEntry) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(entry).Error; err != nil {
+			return err
+		}
+		return fillSeats(tx, entry.ClassID)
+	})
+}
+
+func (r *waitlistRepository) RemoveFromWaitlist(classID, studentID uint) error {
+	result := r.db.Where("class_id = ? AND student_id = ?", classID, studentID).Delete(&models.WaitlistEntry{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetClassOccupancy(classIDs []uint) (map[uint]models.Occupancy, error) {
+	occupancy := make(map[uint]models.Occupancy, len(classIDs))
+	if len(classIDs) == 0 {
+		return occupancy, nil
+	}
+	var classes []models.Class
+	if err := r.db.Select("id", "capacity").Where("id IN ?", classIDs).Find(&classes).Error; err != nil {
+		return nil, err
+	}
+	type count struct {
+		ClassID uint
+		Count   int64
+	}
+	var enrolled, waitlisted []count
+	if err := r.db.Model(&models.Student{}).Select("class_id, COUNT(*) AS count").
+		Where("class_id IN ?", classIDs).Group("class_id").Scan(&enrolled).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Model(&models.WaitlistEntry{}).Select("class_id, COUNT(*) AS count").
+		Where("class_id IN ?", classIDs).Group("class_id").Scan(&waitlisted).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[uint]*models.Occupancy, len(classes))
+	for _, class := range classes {
+		counts[class.ID] = &models.Occupancy{}
+	}
+	for _, c := range enrolled {
+		if o, ok := counts[c.ClassID]; ok {
+			o.Enrolled = c.Count
+		}
+	}
+	for _, c := range waitlisted {
+		if o, ok := counts[c.ClassID]; ok {
+			o.Waitlisted = c.Count
+		}
+	}
+	for _, class := range classes {
+		o := counts[class.ID]
+		if class.Capacity > 0 {
+			available := max(int64(class.Capacity)-o.Enrolled, 0)
+			o.Available = &available
+		}
+		occupancy[class.ID] = *o
+	}
+	return occupancy, nil
+}
+
+// takeSeat locks the class a student is joining and returns ErrClassFull
+// if it has no free seat. studentID is zero for a new student; a joining
+// student leaves the class's waitlist.
+func takeSeat(tx *gorm.DB, classID, studentID uint) error {
+	if classID == 0 {
+		return nil
+	}
+	var class models.Class
+	found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "capacity"), &class, classID)
+	if err != nil || !found {
+		return err
+	}
+	if studentID != 0 {
+		if err := tx.Where("class_id = ? AND student_id = ?", classID, studentID).Delete(&models.WaitlistEntry{}).Error; err != nil {
+			return err
+		}
+	}
+	if class.Capacity == 0 {
+		return nil
+	}
+	var enrolled int64
+	if err := tx.Model(&models.Student{}).Where("class_id = ? AND id <> ?", classID, studentID).Count(&enrolled).Error; err != nil {
+		return err
+	}
+	if enrolled >= int64(class.Capacity) {
+		return ErrClassFull
+	}
+	return nil
+}
+
+// fillSeats moves waitlisted students into the class's free seats in
+// waitlist order. The seats they leave are filled in turn.
+func fillSeats(tx *gorm.DB, classID uint) error {
+	if classID == 0 {
+		return nil
+	}
+	core := &repository{db: tx}
+	for {
+		var class models.Class
+		found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "capacity"), &class, classID)
+		if err != nil || !found {
+			return err
+		}
+		var entry models.WaitlistEntry
+		found, err = findOne(tx.Where("class_id = ?", classID).Order("id"), &entry)
+		if err != nil || !found {
+			return err
+		}
+		if class.Capacity > 0 {
+			var enrolled int64
+			if err := tx.Model(&models.Student{}).Where("class_id = ?", classID).Count(&enrolled).Error; err != nil {
+				return err
+			}
+			if enrolled >= int64(class.Capacity) {
+				return nil
+			}
+		}
+		var student models.Student
+		found, err = findOne(tx, &student, entry.StudentID)
+		if err != nil {
+			return err
+		}
+		if !found || student.ClassID == classID {
+			if err := tx.Delete(&entry).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		student.ClassID = classID
+		if err := core.UpdateStudent(&student); err != nil {
+			return err
+		}
+	}
+}
+
+// seating defers the seat checks of students moved in one transaction: the
+// classes they join are only checked for free seats, and the seats they
+// leave only filled, once every move is made. Students can then move into
+// a class that others leave later in the transaction. A nil seating takes
+// and frees seats as each student moves.
+type seating struct {
+	joined []uint
+	freed  []uint
+}
+
+func (s *seating) take(tx *gorm.DB, classID, studentID uint) error {
+	if s == nil {
+		return takeSeat(tx, classID, studentID)
+	}
+	if classID == 0 {
+		return nil
+	}
+	s.joined = append(s.joined, classID)
+	return tx.Where("class_id = ? AND student_id = ?", classID, studentID).Delete(&models.WaitlistEntry{}).Error
+}
+
+func (s *seating) free(tx *gorm.DB, classID uint) error {
+	if s == nil {
+		return fillSeats(tx, classID)
+	}
+	s.freed = append(s.freed, classID)
+	return nil
+}
+
+// settle returns ErrClassFull if a class that students joined holds more
+// students than it has seats, and otherwise fills the seats they left.
+func (s *seating) settle(tx *gorm.DB) error {
+	for _, classID := range distinct(s.joined) {
+		var class models.Class
+		found, err := findOne(tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id", "capacity"), &class, classID)
+		if err != nil {
+			return err
+		}
+		if !found || class.Capacity == 0 {
+			continue
+		}
+		var enrolled int64
+		if err := tx.Model(&models.Student{}).Where("class_id = ?", classID).Count(&enrolled).Error; err != nil {
+			return err
+		}
+		if enrolled > int64(class.Capacity) {
+			return ErrClassFull
+		}
+	}
+	for _, classID := range distinct(s.freed) {
+		if err := fillSeats(tx, classID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// distinct sorts the IDs, so classes are locked in ID order, and drops
+// duplicates.
+func distinct(ids []uint) []uint {
+	slices.Sort(ids)
+	return slices.Compact(ids)
+}
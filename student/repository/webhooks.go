@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"stu/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepository interface {
+	// Subscription methods
+	GetWebhookSubscriptions() ([]models.WebhookSubscription, error)
+	GetWebhookSubscriptionByID(id uint) (*models.WebhookSubscription, error)
+	CreateWebhookSubscription(subscription *models.WebhookSubscription) error
+	UpdateWebhookSubscription(subscription *models.WebhookSubscription) error
+	DeleteWebhookSubscription(id uint) error
+
+	// Delivery methods
+	GetWebhookDeliveries(status string) ([]models.WebhookDelivery, error)
+	GetWebhookDeliveryByID(id uint) (*models.WebhookDelivery, error)
+	// CreateWebhookDeliveries skips deliveries of an event that is already
+	// queued for the subscription.
+	CreateWebhookDeliveries(deliveries []models.WebhookDelivery) error
+	UpdateWebhookDelivery(delivery *models.WebhookDelivery) error
+	// ClaimDueWebhookDeliveries returns up to limit pending deliveries whose
+	// next attempt is due and pushes their next attempt back by lease, so
+	// other replicas skip them while they are being sent.
+	ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{
+		db: db,
+	}
+}
+
+// Subscription methods
+
+func (r *webhookRepository) GetWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+	var subscriptions []models.WebhookSubscription
+	result := r.db.Order("id").Find(&subscriptions)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return subscriptions, nil
+}
+
+func (r *webhookRepository) GetWebhookSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	result := r.db.First(&subscription, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) CreateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	return r.db.Create(subscription).Error
+}
+
+func (r *webhookRepository) UpdateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	return r.db.Save(subscription).Error
+}
+
+func (r *webhookRepository) DeleteWebhookSubscription(id uint) error {
+	return r.db.Delete(&models.WebhookSubscription{}, id).Error
+}
+
+// Delivery methods
+
+func (r *webhookRepository) GetWebhookDeliveries(status string) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	query := r.db.Order("id")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Find(&deliveries)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) GetWebhookDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	result := r.db.First(&delivery, id)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) CreateWebhookDeliveries(deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries).Error
+}
+
+func (r *webhookRepository) UpdateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+func (r *webhookRepository) ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at").Limit(limit)
+		if tx.Dialector.Name() == "postgres" {
+			query = query.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+		}
+		if err := query.Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(deliveries))
+		for i, delivery := range deliveries {
+			ids[i] = delivery.ID
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
@@ -1,17 +1,23 @@
 package routes
 
 import (
+	"expvar"
 	"stu/controllers"
+	"stu/gql"
 
 	"github.com/gin-gonic/gin"
 )
 
+// SetupRouter registers the core endpoints. The middleware runs before
+// every route, including those registered on the engine afterwards.
 func SetupRouter(
 	schoolController *controllers.SchoolController,
 	classController *controllers.ClassController,
 	studentController *controllers.StudentController,
+	middleware ...gin.HandlerFunc,
 ) *gin.Engine {
 	r := gin.Default()
+	r.Use(middleware...)
 
 	// Schools endpoints
 	schools := r.Group("/schools")
@@ -37,6 +43,7 @@ func SetupRouter(
 	students := r.Group("/students")
 	{
 		students.GET("/", studentController.GetAllStudents)
+		students.GET("/search", studentController.SearchStudents)
 		students.GET("/:id", studentController.GetStudentByID)
 		students.POST("/", studentController.CreateStudent)
 		students.PUT("/:id", studentController.UpdateStudent)
@@ -45,3 +52,187 @@ func SetupRouter(
 
 	return r
 }
+
+func RegisterStatsRoutes(r *gin.Engine, statsController *controllers.StatsController) {
+	r.GET("/classes/:id/stats", statsController.GetClassStats)
+	r.GET("/schools/:id/stats", statsController.GetSchoolStats)
+}
+
+func RegisterRankingRoutes(r *gin.Engine, rankingController *controllers.RankingController) {
+	r.GET("/classes/:id/rankings", rankingController.GetClassRanking)
+	r.GET("/schools/:id/rankings", rankingController.GetSchoolRanking)
+	r.GET("/rankings", rankingController.GetDistrictRanking)
+}
+
+func RegisterAttendanceRoutes(r *gin.Engine, attendanceController *controllers.AttendanceController) {
+	r.POST("/classes/:id/attendance", attendanceController.RecordRollCall)
+	r.GET("/classes/:id/attendance", attendanceController.GetClassAttendance)
+	r.GET("/classes/:id/attendance/summary", attendanceController.GetClassAttendanceSummary)
+	r.GET("/students/:id/attendance/summary", attendanceController.GetStudentAttendanceSummary)
+	r.GET("/attendance/chronic-absentees", attendanceController.GetChronicAbsentees)
+}
+
+func RegisterTeacherRoutes(r *gin.Engine, teacherController *controllers.TeacherController) {
+	teachers := r.Group("/teachers")
+	{
+		teachers.GET("/", teacherController.GetAllTeachers)
+		teachers.GET("/:id", teacherController.GetTeacherByID)
+		teachers.POST("/", teacherController.CreateTeacher)
+		teachers.PUT("/:id", teacherController.UpdateTeacher)
+		teachers.DELETE("/:id", teacherController.DeleteTeacher)
+		teachers.GET("/:id/classes", teacherController.GetTeacherClasses)
+		teachers.POST("/:id/assignments", teacherController.CreateAssignment)
+		teachers.DELETE("/:id/assignments/:assignment_id", teacherController.DeleteAssignment)
+	}
+	r.GET("/classes/:id/teachers", teacherController.GetClassTeachers)
+}
+
+func RegisterAcademicRoutes(r *gin.Engine, academicController *controllers.AcademicController) {
+	r.GET("/schools/:id/academic-years", academicController.GetAcademicYears)
+	r.POST("/schools/:id/academic-years", academicController.CreateAcademicYear)
+	r.GET("/schools/:id/active-term", academicController.GetActiveTerm)
+	years := r.Group("/academic-years")
+	{
+		years.GET("/:id", academicController.GetAcademicYearByID)
+		years.PUT("/:id", academicController.UpdateAcademicYear)
+		years.DELETE("/:id", academicController.DeleteAcademicYear)
+		years.GET("/:id/terms", academicController.GetTerms)
+		years.POST("/:id/terms", academicController.CreateTerm)
+	}
+	terms := r.Group("/terms")
+	{
+		terms.GET("/:id", academicController.GetTermByID)
+		terms.PUT("/:id", academicController.UpdateTerm)
+		terms.DELETE("/:id", academicController.DeleteTerm)
+		terms.POST("/:id/activate", academicController.ActivateTerm)
+		terms.GET("/:id/enrollments", academicController.GetTermEnrollments)
+	}
+	r.GET("/students/:id/history", academicController.GetStudentHistory)
+	r.PUT("/students/:id/terms/:term_id", academicController.RecordTermMarks)
+}
+
+func RegisterPromotionRoutes(r *gin.Engine, promotionController *controllers.PromotionController) {
+	r.GET("/schools/:id/promotions", promotionController.GetPromotionPlans)
+	r.POST("/schools/:id/promotions", promotionController.DraftPromotionPlan)
+	promotions := r.Group("/promotions")
+	{
+		promotions.GET("/:id", promotionController.GetPromotionPlan)
+		promotions.DELETE("/:id", promotionController.DeletePromotionPlan)
+		promotions.PUT("/:id/entries/:student_id", promotionController.OverridePromotion)
+		promotions.POST("/:id/apply", promotionController.ApplyPromotionPlan)
+	}
+}
+
+func RegisterGuardianRoutes(r *gin.Engine, guardianController *controllers.GuardianController) {
+	guardians := r.Group("/guardians")
+	{
+		guardians.GET("/", guardianController.GetAllGuardians)
+		guardians.GET("/:id", guardianController.GetGuardianByID)
+		guardians.POST("/", guardianController.CreateGuardian)
+		guardians.PUT("/:id", guardianController.UpdateGuardian)
+		guardians.DELETE("/:id", guardianController.DeleteGuardian)
+		guardians.GET("/:id/students", guardianController.GetGuardianStudents)
+	}
+	r.GET("/students/:id/contacts", guardianController.GetStudentContacts)
+	r.POST("/students/:id/guardians", guardianController.LinkGuardian)
+	r.PUT("/students/:id/guardians/:guardian_id", guardianController.UpdateLink)
+	r.DELETE("/students/:id/guardians/:guardian_id", guardianController.UnlinkGuardian)
+}
+
+func RegisterFeeRoutes(r *gin.Engine, feeController *controllers.FeeController) {
+	r.GET("/schools/:id/fee-items", feeController.GetFeeItems)
+	items := r.Group("/fee-items")
+	{
+		items.GET("/:id", feeController.GetFeeItemByID)
+		items.POST("/", feeController.CreateFeeItem)
+		items.PUT("/:id", feeController.UpdateFeeItem)
+		items.DELETE("/:id", feeController.DeleteFeeItem)
+	}
+	r.GET("/students/:id/discounts", feeController.GetStudentDiscounts)
+	r.POST("/students/:id/discounts", feeController.CreateDiscount)
+	r.DELETE("/discounts/:id", feeController.DeleteDiscount)
+	r.POST("/terms/:id/invoices", feeController.GenerateInvoices)
+	invoices := r.Group("/invoices")
+	{
+		invoices.GET("/", feeController.GetInvoices)
+		invoices.GET("/:id", feeController.GetInvoiceByID)
+		invoices.GET("/:id/print", feeController.PrintInvoice)
+		invoices.POST("/:id/payments", feeController.RecordPayment)
+	}
+	r.GET("/students/:id/ledger", feeController.GetStudentLedger)
+	r.GET("/fees/outstanding", feeController.GetOutstandingBalances)
+}
+
+func RegisterExamRoutes(r *gin.Engine, examController *controllers.ExamController) {
+	r.GET("/classes/:id/exams", examController.GetClassExams)
+	r.POST("/classes/:id/exams", examController.CreateExam)
+	r.GET("/classes/:id/marks", examController.GetMarksGrid)
+	r.PUT("/classes/:id/marks", examController.SaveMarksGrid)
+	exams := r.Group("/exams")
+	{
+		exams.GET("/:id", examController.GetExamByID)
+		exams.PUT("/:id", examController.UpdateExam)
+		exams.DELETE("/:id", examController.DeleteExam)
+		exams.GET("/:id/results", examController.GetExamResults)
+		exams.PUT("/:id/results", examController.RecordResults)
+		exams.POST("/:id/submit", examController.SubmitMarks)
+		exams.POST("/:id/approve", examController.ApproveMarks)
+		exams.POST("/:id/return", examController.ReturnMarks)
+		exams.POST("/:id/publish", examController.PublishMarks)
+		exams.POST("/:id/reopen", examController.ReopenMarks)
+		exams.GET("/:id/history", examController.GetMarksHistory)
+	}
+	r.GET("/students/:id/scores", examController.GetStudentScores)
+}
+
+func RegisterAppealRoutes(r *gin.Engine, appealController *controllers.AppealController) {
+	r.GET("/schools/:id/appeals", appealController.GetSchoolAppeals)
+	r.GET("/students/:id/appeals", appealController.GetStudentAppeals)
+	r.POST("/students/:id/appeals", appealController.CreateAppeal)
+	r.GET("/students/:id/mark-changes", appealController.GetMarkChanges)
+	appeals := r.Group("/appeals")
+	{
+		appeals.GET("/:id", appealController.GetAppealByID)
+		appeals.POST("/:id/assign", appealController.AssignAppeal)
+		appeals.POST("/:id/resolve", appealController.ResolveAppeal)
+	}
+}
+
+func RegisterWaitlistRoutes(r *gin.Engine, waitlistController *controllers.WaitlistController) {
+	r.GET("/classes/:id/waitlist", waitlistController.GetWaitlist)
+	r.POST("/classes/:id/waitlist", waitlistController.AddToWaitlist)
+	r.DELETE("/classes/:id/waitlist/:student_id", waitlistController.RemoveFromWaitlist)
+}
+
+func RegisterWebhookRoutes(r *gin.Engine, webhookController *controllers.WebhookController) {
+	webhooks := r.Group("/webhooks")
+	{
+		webhooks.GET("/", webhookController.GetAllWebhooks)
+		webhooks.GET("/:id", webhookController.GetWebhookByID)
+		webhooks.POST("/", webhookController.CreateWebhook)
+		webhooks.PUT("/:id", webhookController.UpdateWebhook)
+		webhooks.DELETE("/:id", webhookController.DeleteWebhook)
+		webhooks.GET("/deliveries", webhookController.GetWebhookDeliveries)
+		webhooks.POST("/deliveries/:id/replay", webhookController.ReplayWebhookDelivery)
+	}
+}
+
+func RegisterEventRoutes(r *gin.Engine, eventController *controllers.EventController) {
+	r.GET("/events", eventController.StreamStudentEvents)
+}
+
+func RegisterChangeRoutes(r *gin.Engine, changeController *controllers.ChangeController) {
+	r.GET("/changes", changeController.GetChanges)
+	r.POST("/changes", changeController.ApplyChanges)
+}
+
+// RegisterMetricsRoutes serves the variables published with expvar, such
+// as the repository cache statistics, as JSON. They are for operators, so
+// serve registers them on a separate debug listener, not the API router.
+func RegisterMetricsRoutes(r *gin.Engine) {
+	r.GET("/debug/vars", gin.WrapH(expvar.Handler()))
+}
+
+func RegisterGraphQLRoutes(r *gin.Engine, graphqlHandler *gql.Handler) {
+	r.POST("/graphql", graphqlHandler.Serve)
+}
@@ -0,0 +1,142 @@
+// Package search scores and highlights free-text matches against student
+// records. Postgres does the matching itself with tsvector and pg_trgm;
+// this package mirrors that closely enough to serve as the fallback for
+// other databases and to highlight results from either.
+package search
+
+import (
+	"html"
+	"strconv"
+	"strings"
+	"stu/models"
+	"unicode"
+)
+
+// SimilarityThreshold is the trigram similarity above which two words are
+// considered a fuzzy match, the pg_trgm default.
+const SimilarityThreshold = 0.3
+
+// Field is a piece of record text matched with the given weight.
+type Field struct {
+	Name   string
+	Text   string
+	Weight float64
+}
+
+// StudentFields returns the searchable fields of a student. Name and
+// StudentID weigh more than the address, as in the tsvector.
+func StudentFields(student models.Student) []Field {
+	studentID := ""
+	if student.StudentID != 0 {
+		studentID = strconv.Itoa(student.StudentID)
+	}
+	return []Field{
+		{Name: "name", Text: student.Name, Weight: 1},
+		{Name: "student_id", Text: studentID, Weight: 1},
+		{Name: "street", Text: student.Address.Street, Weight: 0.4},
+		{Name: "city", Text: student.Address.City, Weight: 0.4},
+		{Name: "state", Text: student.Address.State, Weight: 0.4},
+	}
+}
+
+// Terms splits a query into lower-case words of letters and digits.
+func Terms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), isSeparator)
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
+
+// Score sums, for every term, the best weighted match among the words of
+// fields. A word starting with the term matches fully, a similar word by
+// its similarity. ok is false unless every term matched.
+func Score(fields []Field, terms []string) (score float64, ok bool) {
+	for _, term := range terms {
+		best := 0.0
+		for _, field := range fields {
+			for _, word := range Terms(field.Text) {
+				if s := matchWord(word, term) * field.Weight; s > best {
+					best = s
+				}
+			}
+		}
+		if best == 0 {
+			return 0, false
+		}
+		score += best
+	}
+	return score, true
+}
+
+// Highlight returns text HTML-escaped, with every word matching one of
+// the terms wrapped in <mark> tags, and whether any word matched.
+func Highlight(text string, terms []string) (string, bool) {
+	var b strings.Builder
+	matched := false
+	runes := []rune(text)
+	for start := 0; start < len(runes); {
+		end := start
+		for end < len(runes) && !isSeparator(runes[end]) {
+			end++
+		}
+		if end == start {
+			b.WriteString(html.EscapeString(string(runes[start])))
+			start++
+			continue
+		}
+		word := string(runes[start:end])
+		if matchesAny(strings.ToLower(word), terms) {
+			matched = true
+			b.WriteString("<mark>" + html.EscapeString(word) + "</mark>")
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		start = end
+	}
+	return b.String(), matched
+}
+
+func matchesAny(word string, terms []string) bool {
+	for _, term := range terms {
+		if matchWord(word, term) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func matchWord(word, term string) float64 {
+	if strings.HasPrefix(word, term) {
+		return 1
+	}
+	if s := Similarity(word, term); s >= SimilarityThreshold {
+		return s
+	}
+	return 0
+}
+
+// Similarity returns the share of trigrams two words have in common, as
+// pg_trgm's similarity computes it for single words.
+func Similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+	common := 0
+	for trigram := range ta {
+		if tb[trigram] {
+			common++
+		}
+	}
+	return float64(common) / float64(len(ta)+len(tb)-common)
+}
+
+func trigrams(word string) map[string]bool {
+	runes := []rune("  " + strings.ToLower(word) + " ")
+	set := map[string]bool{}
+	for i := 0; i+3 <= len(runes); i++ {
+		set[string(runes[i:i+3])] = true
+	}
+	return set
+}
@@ -0,0 +1,48 @@
+package search_test
+
+import (
+	"stu/models"
+	"stu/search"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTerms(t *testing.T) {
+	assert.Equal(t, []string{"jahnavi", "o", "brien", "1042"}, search.Terms("  Jahnavi O'Brien, #1042 "))
+	assert.Empty(t, search.Terms("--"))
+}
+
+func TestSimilarity(t *testing.T) {
+	assert.Equal(t, 1.0, search.Similarity("Jahnavi", "jahnavi"))
+	assert.InDelta(t, 0.5, search.Similarity("jahnvi", "jahnavi"), 0.001)
+	assert.Less(t, search.Similarity("jahnvi", "ravi"), search.SimilarityThreshold)
+}
+
+func TestScore(t *testing.T) {
+	student := models.Student{Name: "Jahnavi Sharma", StudentID: 1042}
+	student.Address.City = "Hyderabad"
+	fields := search.StudentFields(student)
+
+	exact, ok := search.Score(fields, []string{"jahnavi"})
+	assert.True(t, ok)
+	fuzzy, ok := search.Score(fields, []string{"jahnvi"})
+	assert.True(t, ok)
+	assert.Greater(t, exact, fuzzy)
+
+	address, ok := search.Score(fields, []string{"hyder"})
+	assert.True(t, ok)
+	assert.Less(t, address, exact)
+
+	_, ok = search.Score(fields, []string{"jahnavi", "mumbai"})
+	assert.False(t, ok)
+}
+
+func TestHighlight(t *testing.T) {
+	highlighted, ok := search.Highlight("Jahnavi <Sharma>", []string{"jahnvi"})
+	assert.True(t, ok)
+	assert.Equal(t, "<mark>Jahnavi</mark> &lt;Sharma&gt;", highlighted)
+
+	_, ok = search.Highlight("Ravi", []string{"jahnvi"})
+	assert.False(t, ok)
+}
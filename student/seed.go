@@ -0,0 +1,28 @@
+package main
+
+import (
+	"flag"
+	"log"
+	"stu/seed"
+)
+
+func runSeed(app *App, args []string) error {
+	flags := flag.NewFlagSet("seed", flag.ExitOnError)
+	randomSeed := flags.Int64("seed", 1, "random seed; the same seed generates the same data")
+	schools := flags.Int("schools", 3, "number of schools")
+	classes := flags.Int("classes", 10, "classes per school")
+	students := flags.Int("students", 30, "students per class")
+	flags.Parse(args)
+
+	data := seed.Generate(*randomSeed, seed.Options{
+		Schools:          *schools,
+		ClassesPerSchool: *classes,
+		StudentsPerClass: *students,
+	})
+	if err := data.Save(app.SchoolService, app.ClassService, app.StudentService); err != nil {
+		return err
+	}
+	log.Printf("Seeded %d school(s), %d class(es) and %d student(s)",
+		len(data.Schools), len(data.Classes), len(data.Students))
+	return nil
+}
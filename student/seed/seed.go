@@ -0,0 +1,147 @@
+package seed
+
+import (
+	"fmt"
+	"math/rand"
+	"stu/models"
+	"stu/services"
+)
+
+// Options controls the size of a generated dataset.
+type Options struct {
+	Schools          int
+	ClassesPerSchool int
+	StudentsPerClass int
+}
+
+// Dataset is a generated set of schools, classes and students. Relations
+// between them are expressed by index until the rows are saved.
+type Dataset struct {
+	Schools  []models.School
+	Classes  []models.Class
+	Students []models.Student
+
+	// classSchool maps each class to the index of its school, and
+	// studentClass maps each student to the index of its class.
+	classSchool  []int
+	studentClass []int
+}
+
+var (
+	firstNames = []string{
+		"Aarav", "Aditi", "Ananya", "Arjun", "Diya", "Ishaan", "Jahnavi", "Kabir",
+		"Kavya", "Meera", "Nikhil", "Priya", "Rahul", "Riya", "Rohan", "Saanvi",
+		"Sai", "Sneha", "Tanvi", "Vihaan",
+	}
+	lastNames = []string{
+		"Agarwal", "Bose", "Chowdary", "Desai", "Gupta", "Iyer", "Kotyada", "Menon",
+		"Nair", "Patel", "Rao", "Reddy", "Sharma", "Singh", "Varma",
+	}
+	streets = []string{
+		"MG Road", "Station Road", "Park Street", "Beach Road", "Temple Street",
+		"Gandhi Nagar", "Lake View Road", "College Road",
+	}
+	cities = []struct{ city, state string }{
+		{"Visakhapatnam", "Andhra Pradesh"},
+		{"Vijayawada", "Andhra Pradesh"},
+		{"Hyderabad", "Telangana"},
+		{"Bengaluru", "Karnataka"},
+		{"Chennai", "Tamil Nadu"},
+		{"Pune", "Maharashtra"},
+	}
+	schoolKinds = []string{"Public School", "High School", "Vidyalaya", "Academy", "Model School"}
+	sections    = []string{"A", "B", "C", "D"}
+)
+
+// Generate builds a dataset from seed. The same seed and options always
+// produce the same dataset.
+func Generate(seed int64, opts Options) *Dataset {
+	rng := rand.New(rand.NewSource(seed))
+	data := &Dataset{}
+	studentNumber := 1000
+
+	for s := 0; s < opts.Schools; s++ {
+		place := cities[rng.Intn(len(cities))]
+		data.Schools = append(data.Schools, models.School{
+			Name: fmt.Sprintf("%s %s", place.city, schoolKinds[rng.Intn(len(schoolKinds))]),
+		})
+
+		for c := 0; c < opts.ClassesPerSchool; c++ {
+			grade := c%10 + 1
+			data.Classes = append(data.Classes, models.Class{
+				ClassID:   uint(grade),
+				ClassName: fmt.Sprintf("Grade %d-%s", grade, sections[(c/10)%len(sections)]),
+			})
+			data.classSchool = append(data.classSchool, s)
+
+			for st := 0; st < opts.StudentsPerClass; st++ {
+				studentNumber++
+				home := cities[rng.Intn(len(cities))]
+				data.Students = append(data.Students, models.Student{
+					StudentID: studentNumber,
+					Name:      firstNames[rng.Intn(len(firstNames))] + " " + lastNames[rng.Intn(len(lastNames))],
+					Marks:     marks(rng),
+					Address: models.Address{
+						Street: fmt.Sprintf("%d %s", rng.Intn(200)+1, streets[rng.Intn(len(streets))]),
+						City:   home.city,
+						State:  home.state,
+					},
+				})
+				data.studentClass = append(data.studentClass, len(data.Classes)-1)
+			}
+		}
+	}
+	return data
+}
+
+// marks draws a roughly bell-shaped score between 0 and 100.
+func marks(rng *rand.Rand) int {
+	score := int(rng.NormFloat64()*15 + 65)
+	return max(0, min(100, score))
+}
+
+// Save writes the dataset through the services, parents first so that
+// classes and students can reference the generated IDs. The legacy
+// School.ClassID and Class.StudentID fields are then pointed at the first
+// class and student of each.
+func (d *Dataset) Save(schools services.SchoolService, classes services.ClassService, students services.StudentService) error {
+	for i := range d.Schools {
+		if err := schools.CreateSchool(&d.Schools[i]); err != nil {
+			return fmt.Errorf("create school %q: %w", d.Schools[i].Name, err)
+		}
+	}
+	for i := range d.Classes {
+		d.Classes[i].SchoolID = d.Schools[d.classSchool[i]].ID
+		if err := classes.CreateClass(&d.Classes[i]); err != nil {
+			return fmt.Errorf("create class %q: %w", d.Classes[i].ClassName, err)
+		}
+	}
+	for i := range d.Students {
+		d.Students[i].ClassID = d.Classes[d.studentClass[i]].ID
+		if err := students.CreateStudent(&d.Students[i]); err != nil {
+			return fmt.Errorf("create student %q: %w", d.Students[i].Name, err)
+		}
+	}
+
+	firstClass := map[int]int{}
+	for i := len(d.Classes) - 1; i >= 0; i-- {
+		firstClass[d.classSchool[i]] = i
+	}
+	for s, c := range firstClass {
+		d.Schools[s].ClassID = d.Classes[c].ID
+		if err := schools.UpdateSchool(&d.Schools[s]); err != nil {
+			return fmt.Errorf("update school %q: %w", d.Schools[s].Name, err)
+		}
+	}
+	firstStudent := map[int]int{}
+	for i := len(d.Students) - 1; i >= 0; i-- {
+		firstStudent[d.studentClass[i]] = i
+	}
+	for c, s := range firstStudent {
+		d.Classes[c].StudentID = d.Students[s].ID
+		if err := classes.UpdateClass(&d.Classes[c]); err != nil {
+			return fmt.Errorf("update class %q: %w", d.Classes[c].ClassName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package seed_test
+
+import (
+	"stu/seed"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerate_IsDeterministic(t *testing.T) {
+	opts := seed.Options{Schools: 2, ClassesPerSchool: 3, StudentsPerClass: 4}
+
+	first := seed.Generate(42, opts)
+	second := seed.Generate(42, opts)
+
+	assert.Equal(t, first.Schools, second.Schools)
+	assert.Equal(t, first.Classes, second.Classes)
+	assert.Equal(t, first.Students, second.Students)
+	assert.Len(t, first.Schools, 2)
+	assert.Len(t, first.Classes, 6)
+	assert.Len(t, first.Students, 24)
+}
+
+func TestGenerate_MarksWithinRange(t *testing.T) {
+	data := seed.Generate(7, seed.Options{Schools: 1, ClassesPerSchool: 5, StudentsPerClass: 50})
+
+	for _, student := range data.Students {
+		assert.GreaterOrEqual(t, student.Marks, 0)
+		assert.LessOrEqual(t, student.Marks, 100)
+	}
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"context"
+	"expvar"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"slices"
+	"strings"
+	"stu/controllers"
+	"stu/gql"
+	"stu/grpcapi"
+	"stu/migrations"
+	"stu/outbox"
+	"stu/ratelimit"
+	"stu/routes"
+
+	"github.com/gin-gonic/gin"
+)
+
+func runServe(app *App, args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := flags.String("addr", app.Config.HTTPAddr, "address to listen on")
+	grpcAddr := flags.String("grpc-addr", app.Config.GRPCAddr, "address for the gRPC API; empty disables it")
+	debugAddr := flags.String("debug-addr", app.Config.DebugAddr, "address for /debug/vars; empty disables it")
+	skipMigrate := flags.Bool("skip-migrate", false, "do not apply pending migrations on start")
+	flags.Parse(args)
+
+	if !*skipMigrate {
+		if _, err := migrations.NewMigrator(app.DB).Up(); err != nil {
+			return err
+		}
+	}
+
+	if *grpcAddr != "" {
+		listener, err := net.Listen("tcp", *grpcAddr)
+		if err != nil {
+			return err
+		}
+		grpcServer := grpcapi.NewServer(app.SchoolService, app.ClassService, app.StudentService)
+		go func() {
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Printf("gRPC server stopped: %v", err)
+			}
+		}()
+		defer grpcServer.GracefulStop()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go app.Webhooks.Run(ctx)
+
+	sinks, closeSinks, err := outboxSinks(app)
+	if err != nil {
+		return err
+	}
+	defer closeSinks()
+	go outbox.NewRelay(app.OutboxRepo, sinks, outbox.Options{}).Run(ctx)
+
+	schoolController := controllers.NewSchoolController(app.SchoolService)
+	classController := controllers.NewClassController(app.ClassService)
+	studentController := controllers.NewStudentController(app.StudentService).WithRankings(app.RankingService)
+
+	var middleware []gin.HandlerFunc
+	if app.Config.RateLimits != "" {
+		limits, err := ratelimit.ParseLimits(app.Config.RateLimits)
+		if err != nil {
+			return err
+		}
+		keys, err := ratelimit.ParseAPIKeys(app.Config.APIKeys)
+		if err != nil {
+			return err
+		}
+		var store ratelimit.Store
+		switch app.Config.RateLimitStore {
+		case "memory":
+			store = ratelimit.NewMemoryStore()
+		case "postgres":
+			pgStore := ratelimit.NewPostgresStore(app.DB)
+			go pgStore.Run(ctx)
+			store = pgStore
+		default:
+			return fmt.Errorf("unknown rate limit store %q", app.Config.RateLimitStore)
+		}
+		middleware = append(middleware, ratelimit.Identify(keys), ratelimit.NewLimiter(store, ratelimit.Options{Limits: limits}).Handler())
+	}
+
+	router := routes.SetupRouter(schoolController, classController, studentController, middleware...)
+	if err := router.SetTrustedProxies(app.Config.TrustedProxies); err != nil {
+		return err
+	}
+	routes.RegisterStatsRoutes(router, controllers.NewStatsController(app.StatsService))
+	routes.RegisterRankingRoutes(router, controllers.NewRankingController(app.RankingService))
+	routes.RegisterAttendanceRoutes(router, controllers.NewAttendanceController(app.AttendanceService))
+	routes.RegisterTeacherRoutes(router, controllers.NewTeacherController(app.TeacherService))
+	routes.RegisterAcademicRoutes(router, controllers.NewAcademicController(app.AcademicService))
+	routes.RegisterPromotionRoutes(router, controllers.NewPromotionController(app.PromotionService))
+	routes.RegisterGuardianRoutes(router, controllers.NewGuardianController(app.GuardianService))
+	routes.RegisterFeeRoutes(router, controllers.NewFeeController(app.FeeService))
+	routes.RegisterExamRoutes(router, controllers.NewExamController(app.ExamService))
+	routes.RegisterAppealRoutes(router, controllers.NewAppealController(app.AppealService))
+	routes.RegisterWaitlistRoutes(router, controllers.NewWaitlistController(app.WaitlistService))
+	routes.RegisterWebhookRoutes(router, controllers.NewWebhookController(app.WebhookService))
+	routes.RegisterEventRoutes(router, controllers.NewEventController(app.EventStream, 0))
+	routes.RegisterChangeRoutes(router, controllers.NewChangeController(app.ChangeService))
+	routes.RegisterGraphQLRoutes(router, gql.NewHandler(app.SchoolService, app.ClassService, app.StudentService))
+	if *debugAddr != "" {
+		if app.Cache != nil {
+			expvar.Publish("repository_cache", expvar.Func(func() interface{} { return app.Cache.Stats() }))
+		}
+		debugRouter := gin.New()
+		routes.RegisterMetricsRoutes(debugRouter)
+		go func() {
+			if err := debugRouter.Run(*debugAddr); err != nil {
+				log.Printf("Debug server stopped: %v", err)
+			}
+		}()
+	}
+
+	return router.Run(*addr)
+}
+
+// outboxSinks builds the sinks named in the configuration and returns a
+// function that releases them. Webhooks, the event stream and the
+// repository cache learn of changes from the events published to the bus,
+// so the bus sink is required.
+func outboxSinks(app *App) ([]outbox.Sink, func(), error) {
+	if !slices.ContainsFunc(app.Config.OutboxSinks, func(name string) bool {
+		return strings.TrimSpace(name) == "bus"
+	}) {
+		users := "webhooks and the event stream"
+		if app.Cache != nil {
+			users = "webhooks, the event stream and the repository cache"
+		}
+		return nil, nil, fmt.Errorf(`OUTBOX_SINKS needs the "bus" sink, which feeds %s`, users)
+	}
+
+	var sinks []outbox.Sink
+	var closers []func()
+	closeAll := func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
+	}
+
+	for _, name := range app.Config.OutboxSinks {
+		switch strings.TrimSpace(name) {
+		case "bus":
+			sinks = append(sinks, outbox.NewBusSink(app.Bus))
+		case "file":
+			sink, err := outbox.NewFileSink(app.Config.OutboxFile)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			sinks = append(sinks, sink)
+			closers = append(closers, func() { sink.Close() })
+		case "nats":
+			url := app.Config.NATSURL
+			if url == "" {
+				ns, err := outbox.StartEmbeddedNATS(app.Config.NATSStoreDir)
+				if err != nil {
+					closeAll()
+					return nil, nil, err
+				}
+				closers = append(closers, ns.Shutdown)
+				url = ns.ClientURL()
+				log.Printf("Embedded NATS server listening on %s", url)
+			}
+			sink, err := outbox.NewNATSSink(url)
+			if err != nil {
+				closeAll()
+				return nil, nil, err
+			}
+			sinks = append(sinks, sink)
+			closers = append(closers, func() { sink.Close() })
+		case "":
+		default:
+			closeAll()
+			return nil, nil, fmt.Errorf("unknown outbox sink %q", name)
+		}
+	}
+	return sinks, closeAll, nil
+}
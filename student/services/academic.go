@@ -0,0 +1,320 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"stu/models"
+	"stu/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AcademicService interface {
+	GetAcademicYears(schoolID uint) ([]models.AcademicYear, error)
+	GetAcademicYearByID(id uint) (*models.AcademicYear, error)
+	CreateAcademicYear(year *models.AcademicYear) error
+	UpdateAcademicYear(year *models.AcademicYear) error
+	DeleteAcademicYear(id uint) error
+
+	GetTerms(yearID uint) ([]models.Term, error)
+	GetTermByID(id uint) (*models.Term, error)
+	GetActiveTerm(schoolID uint) (*models.Term, error)
+	CreateTerm(term *models.Term) error
+	UpdateTerm(term *models.Term) error
+	DeleteTerm(id uint) error
+	// ActivateTerm starts the term: current marks and class changes are
+	// recorded against it from now on, and the school's previous term
+	// keeps the marks recorded so far.
+	ActivateTerm(id uint) (*models.Term, error)
+
+	// GetTermEnrollments returns the class membership and marks of a
+	// term, only those of the class unless classID is zero.
+	GetTermEnrollments(termID, classID uint) ([]models.Enrollment, error)
+	// GetStudentHistory returns the student's enrollments with their
+	// terms, oldest first.
+	GetStudentHistory(studentID uint) ([]models.Enrollment, error)
+	// RecordTermMarks sets the student's marks for a term. Marks of the
+	// active term update the student; those of other terms correct the
+	// student's enrollment in that term.
+	RecordTermMarks(studentID, termID uint, marks int) (*models.Enrollment, error)
+}
+
+type academicService struct {
+	repo repository.AcademicRepository
+	core repository.Repository
+}
+
+func NewAcademicService(repo repository.AcademicRepository, core repository.Repository) *academicService {
+	return &academicService{
+		repo: repo,
+		core: core,
+	}
+}
+
+func (s *academicService) GetAcademicYears(schoolID uint) ([]models.AcademicYear, error) {
+	if _, err := s.core.GetSchoolByID(schoolID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAcademicYearsBySchoolID(schoolID)
+}
+
+func (s *academicService) GetAcademicYearByID(id uint) (*models.AcademicYear, error) {
+	return s.repo.GetAcademicYearByID(id)
+}
+
+func (s *academicService) CreateAcademicYear(year *models.AcademicYear) error {
+	if err := s.validateAcademicYear(year); err != nil {
+		return err
+	}
+	return s.repo.CreateAcademicYear(year)
+}
+
+func (s *academicService) UpdateAcademicYear(year *models.AcademicYear) error {
+	current, err := s.repo.GetAcademicYearByID(year.ID)
+	if err != nil {
+		return err
+	}
+	year.SchoolID = current.SchoolID
+	year.CreatedAt = current.CreatedAt
+	if err := s.validateAcademicYear(year); err != nil {
+		return err
+	}
+	terms, err := s.repo.GetTermsByAcademicYearID(year.ID)
+	if err != nil {
+		return err
+	}
+	for _, term := range terms {
+		if term.StartDate.Before(year.StartDate) || term.EndDate.After(year.EndDate) {
+			return invalid("term %q falls outside the new dates", term.Name)
+		}
+	}
+	return s.repo.UpdateAcademicYear(year)
+}
+
+func (s *academicService) DeleteAcademicYear(id uint) error {
+	terms, err := s.repo.GetTermsByAcademicYearID(id)
+	if err != nil {
+		return err
+	}
+	if len(terms) > 0 {
+		return invalid("academic year %d still has terms", id)
+	}
+	return s.repo.DeleteAcademicYear(id)
+}
+
+func (s *academicService) GetTerms(yearID uint) ([]models.Term, error) {
+	if _, err := s.repo.GetAcademicYearByID(yearID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTermsByAcademicYearID(yearID)
+}
+
+func (s *academicService) GetTermByID(id uint) (*models.Term, error) {
+	return s.repo.GetTermByID(id)
+}
+
+func (s *academicService) GetActiveTerm(schoolID uint) (*models.Term, error) {
+	if _, err := s.core.GetSchoolByID(schoolID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetActiveTerm(schoolID)
+}
+
+func (s *academicService) CreateTerm(term *models.Term) error {
+	term.Active = false
+	if err := s.validateTerm(term); err != nil {
+		return err
+	}
+	return s.repo.CreateTerm(term)
+}
+
+func (s *academicService) UpdateTerm(term *models.Term) error {
+	current, err := s.repo.GetTermByID(term.ID)
+	if err != nil {
+		return err
+	}
+	term.AcademicYearID = current.AcademicYearID
+	term.Active = current.Active
+	term.CreatedAt = current.CreatedAt
+	if err := s.validateTerm(term); err != nil {
+		return err
+	}
+	return s.repo.UpdateTerm(term)
+}
+
+func (s *academicService) DeleteTerm(id uint) error {
+	term, err := s.repo.GetTermByID(id)
+	if err != nil {
+		return err
+	}
+	if term.Active {
+		return invalid("term %d is the active term", id)
+	}
+	count, err := s.repo.CountEnrollmentsByTermID(id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return invalid("term %d has %d enrollments", id, count)
+	}
+	return s.repo.DeleteTerm(id)
+}
+
+func (s *academicService) ActivateTerm(id uint) (*models.Term, error) {
+	if err := s.repo.ActivateTerm(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetTermByID(id)
+}
+
+func (s *academicService) GetTermEnrollments(termID, classID uint) ([]models.Enrollment, error) {
+	if _, err := s.repo.GetTermByID(termID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetEnrollmentsByTermID(termID, classID)
+}
+
+func (s *academicService) GetStudentHistory(studentID uint) ([]models.Enrollment, error) {
+	if _, err := s.core.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	enrollments, err := s.repo.GetEnrollmentsByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	termIDs := make([]uint, len(enrollments))
+	for i, enrollment := range enrollments {
+		termIDs[i] = enrollment.TermID
+	}
+	terms, err := s.repo.GetTermsByIDs(termIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Term, len(terms))
+	for i := range terms {
+		byID[terms[i].ID] = &terms[i]
+	}
+	for i := range enrollments {
+		enrollments[i].Term = byID[enrollments[i].TermID]
+	}
+	sort.SliceStable(enrollments, func(i, j int) bool {
+		a, b := enrollments[i].Term, enrollments[j].Term
+		return a != nil && b != nil && a.StartDate.Before(b.StartDate)
+	})
+	return enrollments, nil
+}
+
+func (s *academicService) RecordTermMarks(studentID, termID uint, marks int) (*models.Enrollment, error) {
+	if marks < 0 {
+		return nil, invalid("marks must not be negative")
+	}
+	term, err := s.repo.GetTermByID(termID)
+	if err != nil {
+		return nil, err
+	}
+	if term.Active {
+		student, err := s.core.GetStudentByID(studentID)
+		if err != nil {
+			return nil, err
+		}
+		student.Marks = marks
+		if err := s.core.UpdateStudent(student); err != nil {
+			return nil, err
+		}
+	} else {
+		enrollment, err := s.repo.GetEnrollment(studentID, termID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, invalid("student %d was not enrolled in term %d", studentID, termID)
+			}
+			return nil, err
+		}
+		enrollment.Marks = marks
+		if err := s.repo.UpdateEnrollment(enrollment); err != nil {
+			return nil, err
+		}
+	}
+	enrollment, err := s.repo.GetEnrollment(studentID, termID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid("student %d is not enrolled in a class of the term's school", studentID)
+		}
+		return nil, err
+	}
+	enrollment.Term = term
+	return enrollment, nil
+}
+
+func (s *academicService) validateAcademicYear(year *models.AcademicYear) error {
+	year.Name = strings.TrimSpace(year.Name)
+	if year.Name == "" {
+		return invalid("name is required")
+	}
+	if year.StartDate.IsZero() || year.EndDate.IsZero() {
+		return invalid("start_date and end_date are required")
+	}
+	year.StartDate, year.EndDate = day(year.StartDate), day(year.EndDate)
+	if year.EndDate.Before(year.StartDate) {
+		return invalid("end_date is before start_date")
+	}
+	if _, err := s.core.GetSchoolByID(year.SchoolID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("school %d does not exist", year.SchoolID)
+		}
+		return err
+	}
+	years, err := s.repo.GetAcademicYearsBySchoolID(year.SchoolID)
+	if err != nil {
+		return err
+	}
+	for _, other := range years {
+		if other.ID != year.ID && overlaps(year.StartDate, year.EndDate, other.StartDate, other.EndDate) {
+			return invalid("academic year overlaps %q", other.Name)
+		}
+	}
+	return nil
+}
+
+// validateTerm checks the term lies within its academic year and does not
+// overlap the year's other terms, and takes the school from the year.
+func (s *academicService) validateTerm(term *models.Term) error {
+	term.Name = strings.TrimSpace(term.Name)
+	if term.Name == "" {
+		return invalid("name is required")
+	}
+	if term.StartDate.IsZero() || term.EndDate.IsZero() {
+		return invalid("start_date and end_date are required")
+	}
+	term.StartDate, term.EndDate = day(term.StartDate), day(term.EndDate)
+	if term.EndDate.Before(term.StartDate) {
+		return invalid("end_date is before start_date")
+	}
+	year, err := s.repo.GetAcademicYearByID(term.AcademicYearID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("academic year %d does not exist", term.AcademicYearID)
+		}
+		return err
+	}
+	term.SchoolID = year.SchoolID
+	if term.StartDate.Before(day(year.StartDate)) || term.EndDate.After(day(year.EndDate)) {
+		return invalid("term must fall within academic year %q", year.Name)
+	}
+	terms, err := s.repo.GetTermsByAcademicYearID(year.ID)
+	if err != nil {
+		return err
+	}
+	for _, other := range terms {
+		if other.ID != term.ID && overlaps(term.StartDate, term.EndDate, other.StartDate, other.EndDate) {
+			return invalid("term overlaps %q", other.Name)
+		}
+	}
+	return nil
+}
+
+// overlaps reports whether two inclusive date ranges share a day.
+func overlaps(aStart, aEnd, bStart, bEnd time.Time) bool {
+	return !aEnd.Before(day(bStart)) && !day(bEnd).Before(aStart)
+}
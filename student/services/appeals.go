@@ -0,0 +1,256 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"stu/models"
+	"stu/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// AppealResolution is a reviewer's decision on an appeal. Upheld appeals
+// give the revised marks; rejected ones leave the marks as they are.
+type AppealResolution struct {
+	Outcome      string `json:"outcome"`
+	RevisedMarks *int   `json:"revised_marks"`
+	Note         string `json:"note"`
+}
+
+// AppealService handles appeals against published exam marks. Appeals are
+// due within the service's SLA of being raised; unresolved appeals past
+// their due date are overdue.
+type AppealService interface {
+	GetAppealByID(id uint) (*models.Appeal, error)
+	// GetSchoolAppeals returns the school's unresolved appeals, soonest
+	// due first, or only the overdue ones.
+	GetSchoolAppeals(schoolID uint, overdueOnly bool) ([]models.Appeal, error)
+	GetStudentAppeals(studentID uint) ([]models.Appeal, error)
+	// CreateAppeal contests the student's published marks in the exam,
+	// on behalf of the student or of one of their guardians.
+	CreateAppeal(appeal *models.Appeal) error
+	// AssignAppeal hands an unresolved appeal to a reviewer, replacing
+	// any earlier one.
+	AssignAppeal(id uint, reviewer string) (*models.Appeal, error)
+	// ResolveAppeal records the reviewer's decision. Revised marks replace
+	// the exam result, are audited as a MarkChange and count towards the
+	// student's final marks at once.
+	ResolveAppeal(id uint, resolution AppealResolution) (*models.Appeal, error)
+
+	// GetMarkChanges returns the audit trail of the student's published
+	// marks.
+	GetMarkChanges(studentID uint) ([]models.MarkChange, error)
+}
+
+type appealService struct {
+	repo      repository.AppealRepository
+	exams     repository.ExamRepository
+	guardians repository.GuardianRepository
+	core      repository.Repository
+	sla       time.Duration
+}
+
+func NewAppealService(repo repository.AppealRepository, exams repository.ExamRepository, guardians repository.GuardianRepository, core repository.Repository, sla time.Duration) *appealService {
+	return &appealService{
+		repo:      repo,
+		exams:     exams,
+		guardians: guardians,
+		core:      core,
+		sla:       sla,
+	}
+}
+
+func (s *appealService) GetAppealByID(id uint) (*models.Appeal, error) {
+	appeal, err := s.repo.GetAppealByID(id)
+	if err != nil {
+		return nil, err
+	}
+	markOverdue(appeal, time.Now())
+	return appeal, nil
+}
+
+func (s *appealService) GetSchoolAppeals(schoolID uint, overdueOnly bool) ([]models.Appeal, error) {
+	if _, err := s.core.GetSchoolByID(schoolID); err != nil {
+		return nil, err
+	}
+	appeals, err := s.repo.GetOpenAppealsBySchoolID(schoolID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	selected := appeals[:0]
+	for i := range appeals {
+		markOverdue(&appeals[i], now)
+		if !overdueOnly || appeals[i].Overdue {
+			selected = append(selected, appeals[i])
+		}
+	}
+	return selected, nil
+}
+
+func (s *appealService) GetStudentAppeals(studentID uint) ([]models.Appeal, error) {
+	if _, err := s.core.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	appeals, err := s.repo.GetAppealsByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	for i := range appeals {
+		markOverdue(&appeals[i], now)
+	}
+	return appeals, nil
+}
+
+func (s *appealService) CreateAppeal(appeal *models.Appeal) error {
+	appeal.Reason = strings.TrimSpace(appeal.Reason)
+	if appeal.Reason == "" {
+		return invalid("a reason is required to appeal")
+	}
+	if _, err := s.core.GetStudentByID(appeal.StudentID); err != nil {
+		return err
+	}
+	exam, err := s.exams.GetExamByID(appeal.ExamID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("exam %d does not exist", appeal.ExamID)
+		}
+		return err
+	}
+	if exam.Status != models.MarksPublished {
+		return invalid("marks of exam %d are not published", exam.ID)
+	}
+	results, err := s.exams.GetResultsByExamIDs([]uint{exam.ID})
+	if err != nil {
+		return err
+	}
+	var result *models.ExamResult
+	for i := range results {
+		if results[i].StudentID == appeal.StudentID {
+			result = &results[i]
+		}
+	}
+	if result == nil {
+		return invalid("student %d has no marks in exam %d", appeal.StudentID, exam.ID)
+	}
+	if appeal.GuardianID != nil {
+		if _, err := s.guardians.GetLink(appeal.StudentID, *appeal.GuardianID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return invalid("guardian %d is not a guardian of student %d", *appeal.GuardianID, appeal.StudentID)
+			}
+			return err
+		}
+	}
+	open, err := s.repo.HasOpenAppeal(exam.ID, appeal.StudentID)
+	if err != nil {
+		return err
+	}
+	if open {
+		return invalid("marks of student %d in exam %d are already under appeal", appeal.StudentID, exam.ID)
+	}
+	class, err := s.core.GetClassByID(exam.ClassID)
+	if err != nil {
+		return err
+	}
+
+	appeal.ID = 0
+	appeal.SchoolID = class.SchoolID
+	appeal.Status = models.AppealOpen
+	appeal.Reviewer = ""
+	appeal.AssignedAt = nil
+	appeal.DueAt = time.Now().Add(s.sla)
+	appeal.OriginalMarks = result.Marks
+	appeal.Outcome = ""
+	appeal.RevisedMarks = nil
+	appeal.Resolution = ""
+	appeal.ResolvedAt = nil
+	return s.repo.CreateAppeal(appeal)
+}
+
+func (s *appealService) AssignAppeal(id uint, reviewer string) (*models.Appeal, error) {
+	reviewer = strings.TrimSpace(reviewer)
+	if reviewer == "" {
+		return nil, invalid("reviewer is required")
+	}
+	appeal, err := s.repo.GetAppealByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if appeal.Status == models.AppealResolved {
+		return nil, invalid("appeal %d is already resolved", appeal.ID)
+	}
+	now := time.Now()
+	appeal.Status = models.AppealInReview
+	appeal.Reviewer = reviewer
+	appeal.AssignedAt = &now
+	if err := s.repo.AssignAppeal(appeal); err != nil {
+		return nil, err
+	}
+	markOverdue(appeal, now)
+	return appeal, nil
+}
+
+func (s *appealService) ResolveAppeal(id uint, resolution AppealResolution) (*models.Appeal, error) {
+	appeal, err := s.repo.GetAppealByID(id)
+	if err != nil {
+		return nil, err
+	}
+	switch appeal.Status {
+	case models.AppealOpen:
+		return nil, invalid("appeal %d has no reviewer yet", appeal.ID)
+	case models.AppealResolved:
+		return nil, invalid("appeal %d is already resolved", appeal.ID)
+	}
+	resolution.Note = strings.TrimSpace(resolution.Note)
+	if resolution.Note == "" {
+		return nil, invalid("a note explaining the decision is required")
+	}
+	exam, err := s.exams.GetExamByID(appeal.ExamID)
+	if err != nil {
+		return nil, err
+	}
+	switch resolution.Outcome {
+	case models.AppealUpheld:
+		if resolution.RevisedMarks == nil {
+			return nil, invalid("upheld appeals need revised_marks")
+		}
+		marks := *resolution.RevisedMarks
+		if marks < 0 || marks > exam.MaxMarks {
+			return nil, invalid("revised_marks must be between 0 and %d", exam.MaxMarks)
+		}
+		if marks == appeal.OriginalMarks {
+			return nil, invalid("revised_marks are the appealed marks; reject the appeal instead")
+		}
+	case models.AppealRejected:
+		if resolution.RevisedMarks != nil {
+			return nil, invalid("rejected appeals keep the marks; omit revised_marks")
+		}
+	default:
+		return nil, invalid("outcome must be %q or %q", models.AppealUpheld, models.AppealRejected)
+	}
+
+	now := time.Now()
+	appeal.Status = models.AppealResolved
+	appeal.Outcome = resolution.Outcome
+	appeal.RevisedMarks = resolution.RevisedMarks
+	appeal.Resolution = resolution.Note
+	appeal.ResolvedAt = &now
+	if err := s.repo.ResolveAppeal(appeal); err != nil {
+		return nil, err
+	}
+	return appeal, nil
+}
+
+func (s *appealService) GetMarkChanges(studentID uint) ([]models.MarkChange, error) {
+	if _, err := s.core.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetMarkChangesByStudentID(studentID)
+}
+
+// markOverdue flags an unresolved appeal that is past its due date.
+func markOverdue(appeal *models.Appeal, now time.Time) {
+	appeal.Overdue = appeal.Status != models.AppealResolved && now.After(appeal.DueAt)
+}
@@ -0,0 +1,61 @@
+package services_test
+
+import (
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// newAppealFixture publishes exam 1 of newExamFixture and puts appeal 7
+// against the 30 marks of student 101 in review.
+func newAppealFixture(t *testing.T) (*gorm.DB, services.AppealService) {
+	t.Helper()
+	db, exams := newExamFixture(t)
+	_, err := exams.TransitionMarks(1, services.MarksPublish, "hod", "")
+	assert.Nil(t, err)
+	appeal := models.Appeal{SchoolID: 1, ExamID: 1, StudentID: 101, Reason: "Page 3 was not marked", Status: models.AppealInReview, Reviewer: "hod", OriginalMarks: 30}
+	appeal.ID = 7
+	create(t, db, &appeal)
+
+	core := repository.NewRepository(db)
+	return db, services.NewAppealService(repository.NewAppealRepository(db), repository.NewExamRepository(db), nil, core, 24*time.Hour)
+}
+
+func upheld(marks int) services.AppealResolution {
+	return services.AppealResolution{Outcome: models.AppealUpheld, RevisedMarks: &marks, Note: "Page 3 marked"}
+}
+
+func TestResolveAppeal_UpheldRecomputesMarks(t *testing.T) {
+	db, service := newAppealFixture(t)
+
+	appeal, err := service.ResolveAppeal(7, upheld(40))
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.AppealResolved, appeal.Status)
+	assert.Equal(t, 80, studentMarks(t, db, 101))
+}
+
+func TestResolveAppeal_FailedRecomputeKeepsAppealInReview(t *testing.T) {
+	db, service := newAppealFixture(t)
+	// Saving the computed marks fails without the enrollments table.
+	assert.Nil(t, db.Migrator().DropTable(&models.Enrollment{}))
+
+	_, err := service.ResolveAppeal(7, upheld(40))
+
+	assert.NotNil(t, err)
+	appeal, err := service.GetAppealByID(7)
+	assert.Nil(t, err)
+	assert.Equal(t, models.AppealInReview, appeal.Status)
+	var result models.ExamResult
+	assert.Nil(t, db.Where("exam_id = ? AND student_id = ?", 1, 101).First(&result).Error)
+	assert.Equal(t, 30, result.Marks)
+	changes, err := service.GetMarkChanges(101)
+	assert.Nil(t, err)
+	assert.Empty(t, changes)
+	assert.Equal(t, 60, studentMarks(t, db, 101))
+}
@@ -0,0 +1,227 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"stu/models"
+	"stu/repository"
+	"time"
+)
+
+const maxRollCallEntries = 1000
+
+// DefaultChronicAbsenceThreshold is the absence rate, in percent, from
+// which a student counts as chronically absent.
+const DefaultChronicAbsenceThreshold = 10
+
+type RollCallEntry struct {
+	StudentID uint   `json:"student_id"`
+	Status    string `json:"status"`
+	Note      string `json:"note"`
+}
+
+// RollCall records the attendance of a class for one period of a day;
+// period 0 is the whole day.
+type RollCall struct {
+	Date    time.Time
+	Period  int
+	Entries []RollCallEntry
+}
+
+// DateRange bounds a summary inclusively; zero bounds are open.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// AttendanceSummary counts attendance records by status. Late students
+// attended; excused students did not. Rates are percentages of Total.
+type AttendanceSummary struct {
+	StudentID      uint    `json:"student_id,omitempty"`
+	Total          int64   `json:"total"`
+	Present        int64   `json:"present"`
+	Absent         int64   `json:"absent"`
+	Late           int64   `json:"late"`
+	Excused        int64   `json:"excused"`
+	AttendanceRate float64 `json:"attendance_rate"`
+	AbsenceRate    float64 `json:"absence_rate"`
+}
+
+type ClassAttendanceSummary struct {
+	ClassID  uint                `json:"class_id"`
+	Overall  AttendanceSummary   `json:"overall"`
+	Students []AttendanceSummary `json:"students"`
+}
+
+// ChronicAbsenceOptions select the students reported as chronically
+// absent: those whose absence rate in the date range is at least
+// Threshold percent. Zero SchoolID and ClassID cover the district.
+type ChronicAbsenceOptions struct {
+	SchoolID  uint
+	ClassID   uint
+	Dates     DateRange
+	Threshold float64
+}
+
+type AttendanceService interface {
+	RecordRollCall(classID uint, rollCall RollCall) ([]models.AttendanceRecord, error)
+	GetClassAttendance(classID uint, date time.Time) ([]models.AttendanceRecord, error)
+	GetStudentAttendanceSummary(studentID uint, dates DateRange) (*AttendanceSummary, error)
+	GetClassAttendanceSummary(classID uint, dates DateRange) (*ClassAttendanceSummary, error)
+	GetChronicAbsentees(opts ChronicAbsenceOptions) ([]AttendanceSummary, error)
+}
+
+type attendanceService struct {
+	repo repository.AttendanceRepository
+	core repository.Repository
+}
+
+func NewAttendanceService(repo repository.AttendanceRepository, core repository.Repository) *attendanceService {
+	return &attendanceService{
+		repo: repo,
+		core: core,
+	}
+}
+
+func (s *attendanceService) RecordRollCall(classID uint, rollCall RollCall) ([]models.AttendanceRecord, error) {
+	if rollCall.Date.IsZero() {
+		return nil, invalid("date is required")
+	}
+	if rollCall.Period < 0 {
+		return nil, invalid("period must not be negative")
+	}
+	if len(rollCall.Entries) == 0 || len(rollCall.Entries) > maxRollCallEntries {
+		return nil, invalid("a roll call needs between 1 and %d entries", maxRollCallEntries)
+	}
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	students, err := s.core.GetStudentsByClassIDs([]uint{classID})
+	if err != nil {
+		return nil, err
+	}
+	enrolled := make(map[uint]bool, len(students))
+	for _, student := range students {
+		enrolled[student.ID] = true
+	}
+
+	date := day(rollCall.Date)
+	seen := map[uint]bool{}
+	records := make([]models.AttendanceRecord, len(rollCall.Entries))
+	for i, entry := range rollCall.Entries {
+		if !slices.Contains(models.AttendanceStatuses, entry.Status) {
+			return nil, invalid("status %q must be one of %v", entry.Status, models.AttendanceStatuses)
+		}
+		if !enrolled[entry.StudentID] {
+			return nil, invalid("student %d is not in class %d", entry.StudentID, classID)
+		}
+		if seen[entry.StudentID] {
+			return nil, invalid("student %d is listed twice", entry.StudentID)
+		}
+		seen[entry.StudentID] = true
+		records[i] = models.AttendanceRecord{
+			StudentID: entry.StudentID,
+			ClassID:   classID,
+			Date:      date,
+			Period:    rollCall.Period,
+			Status:    entry.Status,
+			Note:      entry.Note,
+		}
+	}
+	if err := s.repo.SaveAttendance(records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+func (s *attendanceService) GetClassAttendance(classID uint, date time.Time) ([]models.AttendanceRecord, error) {
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	date = day(date)
+	return s.repo.GetAttendance(repository.AttendanceFilter{ClassID: classID, From: date, To: date})
+}
+
+func (s *attendanceService) GetStudentAttendanceSummary(studentID uint, dates DateRange) (*AttendanceSummary, error) {
+	if _, err := s.core.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.GetAttendanceCounts(repository.AttendanceFilter{StudentID: studentID, From: dates.From, To: dates.To})
+	if err != nil {
+		return nil, err
+	}
+	summary := AttendanceSummary{StudentID: studentID}
+	for _, c := range counts {
+		summary.add(c)
+	}
+	summary.computeRates()
+	return &summary, nil
+}
+
+func (s *attendanceService) GetClassAttendanceSummary(classID uint, dates DateRange) (*ClassAttendanceSummary, error) {
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	counts, err := s.repo.GetAttendanceCounts(repository.AttendanceFilter{ClassID: classID, From: dates.From, To: dates.To})
+	if err != nil {
+		return nil, err
+	}
+	result := &ClassAttendanceSummary{ClassID: classID, Students: make([]AttendanceSummary, len(counts))}
+	for i, c := range counts {
+		result.Overall.add(c)
+		result.Students[i] = AttendanceSummary{StudentID: c.StudentID}
+		result.Students[i].add(c)
+		result.Students[i].computeRates()
+	}
+	result.Overall.computeRates()
+	return result, nil
+}
+
+func (s *attendanceService) GetChronicAbsentees(opts ChronicAbsenceOptions) ([]AttendanceSummary, error) {
+	if opts.Threshold < 0 || opts.Threshold > 100 {
+		return nil, invalid("threshold must be a percentage")
+	}
+	if opts.Threshold == 0 {
+		opts.Threshold = DefaultChronicAbsenceThreshold
+	}
+	counts, err := s.repo.GetAttendanceCounts(repository.AttendanceFilter{
+		ClassID: opts.ClassID, SchoolID: opts.SchoolID, From: opts.Dates.From, To: opts.Dates.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	absentees := []AttendanceSummary{}
+	for _, c := range counts {
+		summary := AttendanceSummary{StudentID: c.StudentID}
+		summary.add(c)
+		summary.computeRates()
+		if summary.AbsenceRate >= opts.Threshold {
+			absentees = append(absentees, summary)
+		}
+	}
+	sort.SliceStable(absentees, func(i, j int) bool {
+		return absentees[i].AbsenceRate > absentees[j].AbsenceRate
+	})
+	return absentees, nil
+}
+
+func (a *AttendanceSummary) add(c repository.AttendanceCounts) {
+	a.Present += c.Present
+	a.Absent += c.Absent
+	a.Late += c.Late
+	a.Excused += c.Excused
+	a.Total += c.Present + c.Absent + c.Late + c.Excused
+}
+
+func (a *AttendanceSummary) computeRates() {
+	if a.Total == 0 {
+		return
+	}
+	a.AttendanceRate = float64(a.Present+a.Late) * 100 / float64(a.Total)
+	a.AbsenceRate = float64(a.Absent+a.Excused) * 100 / float64(a.Total)
+}
+
+// day truncates t to midnight UTC of its calendar date.
+func day(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,261 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+// Operations a sync client can upload.
+const (
+	OperationUpsert = "upsert"
+	OperationDelete = "delete"
+)
+
+// Statuses reported for each uploaded change.
+const (
+	ChangeApplied  = "applied"
+	ChangeConflict = "conflict"
+	ChangeRejected = "rejected"
+)
+
+const maxUploadedChanges = 500
+
+// Change is the latest state of an entity that changed after a cursor.
+// Deleted entities are sent as tombstones with their DeletedAt set.
+type Change struct {
+	Cursor     uint64      `json:"cursor"`
+	EntityType string      `json:"entity_type"`
+	EntityID   uint        `json:"entity_id"`
+	Deleted    bool        `json:"deleted"`
+	Data       interface{} `json:"data"`
+}
+
+type ChangeSet struct {
+	Changes    []Change `json:"changes"`
+	NextCursor uint64   `json:"next_cursor"`
+	HasMore    bool     `json:"has_more"`
+}
+
+// ClientChange is a change made offline. Data holds the entity as the
+// client last received it, edited; its ID and UpdatedAt identify the
+// server version the change is based on. Upserts without an ID create a
+// new entity.
+type ClientChange struct {
+	EntityType string          `json:"entity_type"`
+	Operation  string          `json:"operation"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// ChangeResult reports the outcome of one uploaded change. Current is the
+// stored entity after the change was applied or, on conflict, the server
+// copy the client has to merge with.
+type ChangeResult struct {
+	Index    int         `json:"index"`
+	Status   string      `json:"status"`
+	EntityID uint        `json:"entity_id,omitempty"`
+	Error    string      `json:"error,omitempty"`
+	Current  interface{} `json:"current,omitempty"`
+}
+
+type ChangeService interface {
+	// GetChanges returns up to limit entity changes recorded after the
+	// cursor, collapsed to the latest state of each entity.
+	GetChanges(since uint64, limit int) (*ChangeSet, error)
+	// ApplyChanges applies uploaded changes in order, validated as the
+	// other services validate them. Conflicting or invalid changes are
+	// reported per change and do not stop the batch.
+	ApplyChanges(changes []ClientChange) ([]ChangeResult, error)
+}
+
+type changeService struct {
+	repo    repository.Repository
+	changes repository.ChangeRepository
+	outbox  repository.OutboxRepository
+}
+
+// NewChangeService uses the outbox event positions as the monotonic sync
+// cursor.
+func NewChangeService(repo repository.Repository, changes repository.ChangeRepository, outbox repository.OutboxRepository) *changeService {
+	return &changeService{
+		repo:    repo,
+		changes: changes,
+		outbox:  outbox,
+	}
+}
+
+type entityKey struct {
+	entityType string
+	id         uint
+}
+
+func (s *changeService) GetChanges(since uint64, limit int) (*ChangeSet, error) {
+	rows, err := s.outbox.GetOutboxEventsAfter(since, repository.OutboxFilter{}, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	set := &ChangeSet{Changes: []Change{}, NextCursor: since}
+	if len(rows) > limit {
+		rows, set.HasMore = rows[:limit], true
+	}
+	if len(rows) == 0 {
+		return set, nil
+	}
+	set.NextCursor = *rows[len(rows)-1].Position
+
+	latest := map[entityKey]uint64{}
+	ids := map[string][]uint{}
+	for _, row := range rows {
+		key := entityKey{row.EntityType, row.EntityID}
+		if _, seen := latest[key]; !seen {
+			ids[row.EntityType] = append(ids[row.EntityType], row.EntityID)
+		}
+		latest[key] = *row.Position
+	}
+
+	add := func(entityType string, id uint, deleted bool, data interface{}) {
+		key := entityKey{entityType, id}
+		set.Changes = append(set.Changes, Change{
+			Cursor: latest[key], EntityType: entityType, EntityID: id, Deleted: deleted, Data: data,
+		})
+	}
+	if len(ids["school"]) > 0 {
+		schools, err := s.changes.GetSchoolsIncludingDeleted(ids["school"])
+		if err != nil {
+			return nil, err
+		}
+		for i := range schools {
+			add("school", schools[i].ID, schools[i].DeletedAt.Valid, &schools[i])
+		}
+	}
+	if len(ids["class"]) > 0 {
+		classes, err := s.changes.GetClassesIncludingDeleted(ids["class"])
+		if err != nil {
+			return nil, err
+		}
+		for i := range classes {
+			add("class", classes[i].ID, classes[i].DeletedAt.Valid, &classes[i])
+		}
+	}
+	if len(ids["student"]) > 0 {
+		students, err := s.changes.GetStudentsIncludingDeleted(ids["student"])
+		if err != nil {
+			return nil, err
+		}
+		for i := range students {
+			add("student", students[i].ID, students[i].DeletedAt.Valid, &students[i])
+		}
+	}
+	sort.Slice(set.Changes, func(i, j int) bool {
+		return set.Changes[i].Cursor < set.Changes[j].Cursor
+	})
+	return set, nil
+}
+
+func (s *changeService) ApplyChanges(changes []ClientChange) ([]ChangeResult, error) {
+	if len(changes) > maxUploadedChanges {
+		return nil, invalid("at most %d changes can be uploaded at once", maxUploadedChanges)
+	}
+	results := make([]ChangeResult, len(changes))
+	for i, change := range changes {
+		results[i] = s.applyChange(change)
+		results[i].Index = i
+	}
+	return results, nil
+}
+
+func (s *changeService) applyChange(change ClientChange) ChangeResult {
+	if change.Operation != OperationUpsert && change.Operation != OperationDelete {
+		return ChangeResult{Status: ChangeRejected, Error: "unknown operation " + change.Operation}
+	}
+	deleting := change.Operation == OperationDelete
+
+	switch change.EntityType {
+	case "school":
+		var school models.School
+		if err := json.Unmarshal(change.Data, &school); err != nil {
+			return ChangeResult{Status: ChangeRejected, Error: "invalid school data"}
+		}
+		var err error
+		switch {
+		case deleting:
+			err = s.changes.DeleteSchoolIfUnchanged(school.ID, school.UpdatedAt)
+		case school.ID == 0:
+			err = s.repo.CreateSchool(&school)
+		default:
+			err = s.changes.UpdateSchoolIfUnchanged(&school, school.UpdatedAt)
+		}
+		return s.changeResult(change.EntityType, school.ID, err)
+	case "class":
+		var class models.Class
+		if err := json.Unmarshal(change.Data, &class); err != nil {
+			return ChangeResult{Status: ChangeRejected, Error: "invalid class data"}
+		}
+		var err error
+		switch {
+		case deleting:
+			err = s.changes.DeleteClassIfUnchanged(class.ID, class.UpdatedAt)
+		case class.ID == 0:
+			if err = validateClass(s.repo, &class, false); err == nil {
+				err = s.repo.CreateClass(&class)
+			}
+		default:
+			if err = validateClass(s.repo, &class, true); err == nil {
+				err = s.changes.UpdateClassIfUnchanged(&class, class.UpdatedAt)
+			}
+		}
+		return s.changeResult(change.EntityType, class.ID, err)
+	case "student":
+		var student models.Student
+		if err := json.Unmarshal(change.Data, &student); err != nil {
+			return ChangeResult{Status: ChangeRejected, Error: "invalid student data"}
+		}
+		var err error
+		switch {
+		case deleting:
+			err = s.changes.DeleteStudentIfUnchanged(student.ID, student.UpdatedAt)
+		case student.ID == 0:
+			err = s.repo.CreateStudent(&student)
+		default:
+			err = s.changes.UpdateStudentIfUnchanged(&student, student.UpdatedAt)
+		}
+		return s.changeResult(change.EntityType, student.ID, err)
+	}
+	return ChangeResult{Status: ChangeRejected, Error: "unknown entity type " + change.EntityType}
+}
+
+// changeResult turns the outcome of a write into a result carrying the
+// stored entity.
+func (s *changeService) changeResult(entityType string, id uint, err error) ChangeResult {
+	result := ChangeResult{Status: ChangeApplied, EntityID: id}
+	switch {
+	case errors.Is(err, repository.ErrConflict):
+		result.Status = ChangeConflict
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		return ChangeResult{Status: ChangeRejected, EntityID: id, Error: entityType + " not found"}
+	case err != nil:
+		return ChangeResult{Status: ChangeRejected, EntityID: id, Error: err.Error()}
+	}
+
+	var current interface{}
+	switch entityType {
+	case "school":
+		if schools, err := s.changes.GetSchoolsIncludingDeleted([]uint{id}); err == nil && len(schools) > 0 {
+			current = &schools[0]
+		}
+	case "class":
+		if classes, err := s.changes.GetClassesIncludingDeleted([]uint{id}); err == nil && len(classes) > 0 {
+			current = &classes[0]
+		}
+	case "student":
+		if students, err := s.changes.GetStudentsIncludingDeleted([]uint{id}); err == nil && len(students) > 0 {
+			current = &students[0]
+		}
+	}
+	result.Current = current
+	return result
+}
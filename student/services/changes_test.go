@@ -0,0 +1,51 @@
+package services_test
+
+import (
+	"encoding/json"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func newChangeService(db *gorm.DB) services.ChangeService {
+	return services.NewChangeService(repository.NewRepository(db), repository.NewChangeRepository(db), repository.NewOutboxRepository(db))
+}
+
+func upsert(t *testing.T, entityType string, data interface{}) services.ClientChange {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	assert.Nil(t, err)
+	return services.ClientChange{EntityType: entityType, Operation: services.OperationUpsert, Data: raw}
+}
+
+func TestApplyChanges_ValidatesClasses(t *testing.T) {
+	db := openTestDB(t)
+	class := models.Class{ClassName: "Grade 3-A", Capacity: 2}
+	class.ID = 10
+	first := models.Student{ClassID: 10}
+	second := models.Student{ClassID: 10}
+	create(t, db, &class, &first, &second)
+	var stored models.Class
+	assert.Nil(t, db.First(&stored, 10).Error)
+	stored.Capacity = 1
+
+	results, err := newChangeService(db).ApplyChanges([]services.ClientChange{
+		upsert(t, "class", models.Class{ClassName: "Grade 3-B", Capacity: -1}),
+		upsert(t, "class", stored),
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, services.ChangeRejected, results[0].Status)
+	assert.Contains(t, results[0].Error, "capacity must not be negative")
+	assert.Equal(t, services.ChangeRejected, results[1].Status)
+	assert.Contains(t, results[1].Error, "already has 2 students")
+	var classes int64
+	assert.Nil(t, db.Model(&models.Class{}).Count(&classes).Error)
+	assert.Equal(t, int64(1), classes)
+	assert.Nil(t, db.First(&stored, 10).Error)
+	assert.Equal(t, 2, stored.Capacity)
+}
@@ -0,0 +1,17 @@
+package services
+
+import "fmt"
+
+// ValidationError reports input the service rejected. Controllers answer it
+// with 400 Bad Request.
+type ValidationError struct {
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+func invalid(format string, args ...interface{}) error {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"stu/events"
+	"stu/repository"
+	"sync"
+)
+
+// EventFilter scopes a stream of student events; zero fields match
+// everything.
+type EventFilter struct {
+	SchoolID uint
+	ClassID  uint
+}
+
+func (f EventFilter) matches(event events.Event) bool {
+	return event.EntityType == "student" &&
+		(f.SchoolID == 0 || event.SchoolID == f.SchoolID) &&
+		(f.ClassID == 0 || event.ClassID == f.ClassID)
+}
+
+type EventStreamService interface {
+	// SubscribeStudentEvents streams live student events matching filter
+	// until cancel is called. The channel is closed early if the
+	// subscriber falls too far behind; it should then resume with
+	// StudentEventsSince.
+	SubscribeStudentEvents(filter EventFilter) (stream <-chan events.Event, cancel func())
+	// StudentEventsSince returns up to limit stored student events after
+	// the given outbox position, in position order.
+	StudentEventsSince(position uint64, filter EventFilter, limit int) ([]events.Event, error)
+}
+
+type eventStreamService struct {
+	bus    *events.Bus
+	repo   repository.OutboxRepository
+	buffer int
+}
+
+// NewEventStreamService streams the events the outbox relay publishes to
+// bus, and replays older ones from the outbox table.
+func NewEventStreamService(bus *events.Bus, repo repository.OutboxRepository) *eventStreamService {
+	return &eventStreamService{
+		bus:    bus,
+		repo:   repo,
+		buffer: 256,
+	}
+}
+
+func (s *eventStreamService) SubscribeStudentEvents(filter EventFilter) (<-chan events.Event, func()) {
+	stream := make(chan events.Event, s.buffer)
+	var mu sync.Mutex
+	closed := false
+	closeStream := func() {
+		if !closed {
+			closed = true
+			close(stream)
+		}
+	}
+
+	unsubscribe := s.bus.Subscribe(events.PublisherFunc(func(event events.Event) error {
+		if !filter.matches(event) {
+			return nil
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if closed {
+			return nil
+		}
+		select {
+		case stream <- event:
+		default:
+			closeStream()
+		}
+		return nil
+	}))
+
+	cancel := func() {
+		unsubscribe()
+		mu.Lock()
+		defer mu.Unlock()
+		closeStream()
+	}
+	return stream, cancel
+}
+
+func (s *eventStreamService) StudentEventsSince(position uint64, filter EventFilter, limit int) ([]events.Event, error) {
+	rows, err := s.repo.GetOutboxEventsAfter(position, repository.OutboxFilter{
+		EntityType: "student",
+		SchoolID:   filter.SchoolID,
+		ClassID:    filter.ClassID,
+	}, limit)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]events.Event, len(rows))
+	for i, row := range rows {
+		result[i] = events.FromOutbox(row)
+	}
+	return result, nil
+}
@@ -0,0 +1,253 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"stu/assessment"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+const maxResultEntries = 1000
+
+type ResultEntry struct {
+	StudentID uint `json:"student_id"`
+	Marks     int  `json:"marks"`
+}
+
+// ExamService manages exams and their results. Once a class has published
+// exam marks in the term in effect, the active term of its school, each
+// student's Marks is their weighted final score over the published exams,
+// recomputed whenever marks are published or reopened.
+type ExamService interface {
+	GetClassExams(classID uint) ([]models.Exam, error)
+	GetExamByID(id uint) (*models.Exam, error)
+	// CreateExam adds an exam to the active term of the class's school
+	// unless it names a term.
+	CreateExam(exam *models.Exam) error
+	UpdateExam(exam *models.Exam) error
+	DeleteExam(id uint) error
+
+	GetExamResults(examID uint) ([]models.ExamResult, error)
+	RecordResults(examID uint, entries []ResultEntry) ([]models.ExamResult, error)
+	// GetStudentScores breaks down the student's final score over the
+	// exams of their class in the term in effect. Unpublished marks are
+	// left out.
+	GetStudentScores(studentID uint) (*assessment.Breakdown, error)
+
+	// TransitionMarks moves the exam's marks through the publishing
+	// workflow; see marksTransitions for the actions.
+	TransitionMarks(examID uint, action, actor, reason string) (*models.Exam, error)
+	GetMarksHistory(examID uint) ([]models.ExamStatusChange, error)
+
+	// GetMarksGrid returns the class roster with their marks in the
+	// assessment, AssessmentMarks or an exam ID, for entry as a grid.
+	GetMarksGrid(classID uint, assessment string) (*MarksGrid, error)
+	SaveMarksGrid(classID uint, assessment string, cells []GridCell) (*MarksGrid, error)
+}
+
+type examService struct {
+	repo     repository.ExamRepository
+	academic repository.AcademicRepository
+	core     repository.Repository
+}
+
+func NewExamService(repo repository.ExamRepository, academic repository.AcademicRepository, core repository.Repository) *examService {
+	return &examService{
+		repo:     repo,
+		academic: academic,
+		core:     core,
+	}
+}
+
+func (s *examService) GetClassExams(classID uint) ([]models.Exam, error) {
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetExamsByClassID(classID)
+}
+
+func (s *examService) GetExamByID(id uint) (*models.Exam, error) {
+	return s.repo.GetExamByID(id)
+}
+
+func (s *examService) CreateExam(exam *models.Exam) error {
+	class, err := s.core.GetClassByID(exam.ClassID)
+	if err != nil {
+		return err
+	}
+	if exam.TermID == 0 {
+		if exam.TermID, err = s.termInEffect(class.SchoolID); err != nil {
+			return err
+		}
+	} else {
+		term, err := s.academic.GetTermByID(exam.TermID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return invalid("term %d does not exist", exam.TermID)
+			}
+			return err
+		}
+		if term.SchoolID != class.SchoolID {
+			return invalid("term %d belongs to another school", term.ID)
+		}
+	}
+	exam.ID = 0
+	exam.Status = models.MarksDraft
+	if err := s.validateExam(exam); err != nil {
+		return err
+	}
+	return s.repo.CreateExam(exam)
+}
+
+func (s *examService) UpdateExam(exam *models.Exam) error {
+	current, err := s.repo.GetExamByID(exam.ID)
+	if err != nil {
+		return err
+	}
+	if err := editable(current); err != nil {
+		return err
+	}
+	exam.ClassID = current.ClassID
+	exam.TermID = current.TermID
+	exam.Status = current.Status
+	exam.CreatedAt = current.CreatedAt
+	if err := s.validateExam(exam); err != nil {
+		return err
+	}
+	results, err := s.repo.GetResultsByExamIDs([]uint{exam.ID})
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if result.Marks > exam.MaxMarks {
+			return invalid("student %d already has %d marks, more than max_marks", result.StudentID, result.Marks)
+		}
+	}
+	return s.repo.UpdateExam(exam)
+}
+
+func (s *examService) DeleteExam(id uint) error {
+	exam, err := s.repo.GetExamByID(id)
+	if err != nil {
+		return err
+	}
+	if err := editable(exam); err != nil {
+		return err
+	}
+	return s.repo.DeleteExam(id)
+}
+
+func (s *examService) GetExamResults(examID uint) ([]models.ExamResult, error) {
+	if _, err := s.repo.GetExamByID(examID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetResultsByExamIDs([]uint{examID})
+}
+
+func (s *examService) RecordResults(examID uint, entries []ResultEntry) ([]models.ExamResult, error) {
+	if len(entries) == 0 {
+		return nil, invalid("no results given")
+	}
+	if len(entries) > maxResultEntries {
+		return nil, invalid("at most %d results can be recorded at once", maxResultEntries)
+	}
+	exam, err := s.repo.GetExamByID(examID)
+	if err != nil {
+		return nil, err
+	}
+	if err := editable(exam); err != nil {
+		return nil, err
+	}
+	students, err := s.core.GetStudentsByClassIDs([]uint{exam.ClassID})
+	if err != nil {
+		return nil, err
+	}
+	inClass := make(map[uint]bool, len(students))
+	for _, student := range students {
+		inClass[student.ID] = true
+	}
+	seen := make(map[uint]bool, len(entries))
+	results := make([]models.ExamResult, len(entries))
+	for i, entry := range entries {
+		if !inClass[entry.StudentID] {
+			return nil, invalid("student %d is not in class %d", entry.StudentID, exam.ClassID)
+		}
+		if seen[entry.StudentID] {
+			return nil, invalid("student %d appears more than once", entry.StudentID)
+		}
+		seen[entry.StudentID] = true
+		if entry.Marks < 0 || entry.Marks > exam.MaxMarks {
+			return nil, invalid("marks of student %d must be between 0 and %d", entry.StudentID, exam.MaxMarks)
+		}
+		results[i] = models.ExamResult{ExamID: exam.ID, StudentID: entry.StudentID, Marks: entry.Marks}
+	}
+	if err := s.repo.SaveResults(exam.ID, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (s *examService) GetStudentScores(studentID uint) (*assessment.Breakdown, error) {
+	student, err := s.core.GetStudentByID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.core.GetClassByID(student.ClassID); err != nil {
+		return nil, err
+	}
+	breakdowns, err := s.repo.GetScoreBreakdowns(student.ClassID, []uint{student.ID})
+	if err != nil {
+		return nil, err
+	}
+	breakdown := breakdowns[student.ID]
+	return &breakdown, nil
+}
+
+// termInEffect returns the ID of the school's active term, or zero if it
+// has none.
+func (s *examService) termInEffect(schoolID uint) (uint, error) {
+	term, err := s.academic.GetActiveTerm(schoolID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return term.ID, nil
+}
+
+// validateExam checks the exam and that the weights of the class's exams
+// in the subject and term stay within 100.
+func (s *examService) validateExam(exam *models.Exam) error {
+	exam.Subject = strings.TrimSpace(exam.Subject)
+	exam.Name = strings.TrimSpace(exam.Name)
+	if exam.Subject == "" || exam.Name == "" {
+		return invalid("subject and name are required")
+	}
+	if exam.MaxMarks <= 0 {
+		return invalid("max_marks must be positive")
+	}
+	if exam.Weight <= 0 || exam.Weight > 100 {
+		return invalid("weight must be between 1 and 100")
+	}
+	if !exam.Date.IsZero() {
+		exam.Date = day(exam.Date)
+	}
+	exams, err := s.repo.GetExamsByClassID(exam.ClassID)
+	if err != nil {
+		return err
+	}
+	total := exam.Weight
+	for _, other := range exams {
+		if other.ID != exam.ID && other.TermID == exam.TermID && other.Subject == exam.Subject {
+			total += other.Weight
+		}
+	}
+	if total > 100 {
+		return invalid("weights of %s exams would add up to %d%%, more than 100%%", exam.Subject, total)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+package services_test
+
+import (
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// newExamFixture sets up class 10 of school 1, in its active term 5, with
+// students 100 and 101 and their results in exam 1, approved for
+// publishing: 45 and 30 out of 50.
+func newExamFixture(t *testing.T) (*gorm.DB, services.ExamService) {
+	t.Helper()
+	db := openTestDB(t)
+	school := models.School{Name: "North"}
+	school.ID = 1
+	class := models.Class{SchoolID: 1}
+	class.ID = 10
+	term := models.Term{SchoolID: 1, Active: true}
+	term.ID = 5
+	first := models.Student{ClassID: 10, Marks: 50}
+	first.ID = 100
+	second := models.Student{ClassID: 10, Marks: 60}
+	second.ID = 101
+	exam := models.Exam{ClassID: 10, TermID: 5, Subject: "Maths", Name: "Final", MaxMarks: 50, Weight: 100, Status: models.MarksApproved}
+	exam.ID = 1
+	create(t, db, &school, &class, &term, &first, &second, &exam,
+		&models.ExamResult{ExamID: 1, StudentID: 100, Marks: 45},
+		&models.ExamResult{ExamID: 1, StudentID: 101, Marks: 30})
+
+	core := repository.NewRepository(db)
+	return db, services.NewExamService(repository.NewExamRepository(db), repository.NewAcademicRepository(db), core)
+}
+
+func studentMarks(t *testing.T, db *gorm.DB, id uint) int {
+	t.Helper()
+	var student models.Student
+	assert.Nil(t, db.First(&student, id).Error)
+	return student.Marks
+}
+
+func TestTransitionMarks_PublishingComputesMarks(t *testing.T) {
+	db, service := newExamFixture(t)
+
+	exam, err := service.TransitionMarks(1, services.MarksPublish, "hod", "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, models.MarksPublished, exam.Status)
+	assert.Equal(t, 90, studentMarks(t, db, 100))
+	assert.Equal(t, 60, studentMarks(t, db, 101))
+}
+
+func TestTransitionMarks_FailedRecomputeKeepsStatus(t *testing.T) {
+	db, service := newExamFixture(t)
+	// Saving the computed marks fails without the enrollments table.
+	assert.Nil(t, db.Migrator().DropTable(&models.Enrollment{}))
+
+	_, err := service.TransitionMarks(1, services.MarksPublish, "hod", "")
+
+	assert.NotNil(t, err)
+	exam, err := service.GetExamByID(1)
+	assert.Nil(t, err)
+	assert.Equal(t, models.MarksApproved, exam.Status)
+	history, err := service.GetMarksHistory(1)
+	assert.Nil(t, err)
+	assert.Empty(t, history)
+	assert.Equal(t, 50, studentMarks(t, db, 100))
+}
+
+func TestUpdateStudent_ComputedMarksCannotBeSet(t *testing.T) {
+	db, service := newExamFixture(t)
+	_, err := service.TransitionMarks(1, services.MarksPublish, "hod", "")
+	assert.Nil(t, err)
+	students := services.NewService(repository.NewRepository(db))
+
+	student, err := students.GetStudentByID(100)
+	assert.Nil(t, err)
+	student.Marks = 70
+	assert.ErrorIs(t, students.UpdateStudent(student), repository.ErrMarksComputed)
+	assert.Equal(t, 90, studentMarks(t, db, 100))
+
+	student.Marks = 90
+	student.Name = "Renamed"
+	assert.Nil(t, students.UpdateStudent(student))
+}
+
+func TestRecordTermMarks_ComputedMarksCannotBeSet(t *testing.T) {
+	db, service := newExamFixture(t)
+	_, err := service.TransitionMarks(1, services.MarksPublish, "hod", "")
+	assert.Nil(t, err)
+	academic := services.NewAcademicService(repository.NewAcademicRepository(db), repository.NewRepository(db))
+
+	_, err = academic.RecordTermMarks(100, 5, 70)
+
+	assert.ErrorIs(t, err, repository.ErrMarksComputed)
+	assert.Equal(t, 90, studentMarks(t, db, 100))
+}
+
+func TestSaveResults_ExamNoLongerDraft(t *testing.T) {
+	db, _ := newExamFixture(t)
+	exams := repository.NewExamRepository(db)
+
+	// The exam left draft after RecordResults checked it.
+	err := exams.SaveResults(1, []models.ExamResult{{StudentID: 100, Marks: 10}})
+
+	assert.ErrorIs(t, err, repository.ErrConflict)
+	results, err := exams.GetResultsByExamIDs([]uint{1})
+	assert.Nil(t, err)
+	for _, result := range results {
+		assert.NotEqual(t, 10, result.Marks)
+	}
+}
@@ -0,0 +1,317 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"stu/fees"
+	"stu/models"
+	"stu/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// InvoiceOptions control invoice generation for a term. A zero ClassID
+// invoices every class of the term's school; a zero DueDate makes the
+// invoices due when the term starts.
+type InvoiceOptions struct {
+	ClassID uint
+	DueDate time.Time
+}
+
+type FeeService interface {
+	GetFeeItems(schoolID uint) ([]models.FeeItem, error)
+	GetFeeItemByID(id uint) (*models.FeeItem, error)
+	CreateFeeItem(item *models.FeeItem) error
+	UpdateFeeItem(item *models.FeeItem) error
+	DeleteFeeItem(id uint) error
+
+	GetStudentDiscounts(studentID uint) ([]models.Discount, error)
+	CreateDiscount(discount *models.Discount) error
+	DeleteDiscount(id uint) error
+
+	// GenerateInvoices invoices the students of a term's school for the
+	// term, skipping those already invoiced and those without fees.
+	GenerateInvoices(termID uint, opts InvoiceOptions) ([]models.Invoice, error)
+	GetInvoices(filter repository.InvoiceFilter) ([]models.Invoice, error)
+	GetInvoiceByID(id uint) (*models.Invoice, error)
+	// GetInvoiceDocument gathers what a printed invoice shows.
+	GetInvoiceDocument(id uint) (*fees.Document, error)
+
+	RecordPayment(payment *models.Payment) error
+	GetStudentLedger(studentID uint) ([]models.LedgerEntry, error)
+	GetOutstandingBalances(filter repository.InvoiceFilter) ([]repository.Balance, error)
+}
+
+type feeService struct {
+	repo     repository.FeeRepository
+	academic repository.AcademicRepository
+	core     repository.Repository
+}
+
+func NewFeeService(repo repository.FeeRepository, academic repository.AcademicRepository, core repository.Repository) *feeService {
+	return &feeService{
+		repo:     repo,
+		academic: academic,
+		core:     core,
+	}
+}
+
+func (s *feeService) GetFeeItems(schoolID uint) ([]models.FeeItem, error) {
+	if _, err := s.core.GetSchoolByID(schoolID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetFeeItemsBySchoolID(schoolID)
+}
+
+func (s *feeService) GetFeeItemByID(id uint) (*models.FeeItem, error) {
+	return s.repo.GetFeeItemByID(id)
+}
+
+func (s *feeService) CreateFeeItem(item *models.FeeItem) error {
+	if err := s.validateFeeItem(item); err != nil {
+		return err
+	}
+	return s.repo.CreateFeeItem(item)
+}
+
+func (s *feeService) UpdateFeeItem(item *models.FeeItem) error {
+	if err := s.validateFeeItem(item); err != nil {
+		return err
+	}
+	return s.repo.UpdateFeeItem(item)
+}
+
+func (s *feeService) DeleteFeeItem(id uint) error {
+	return s.repo.DeleteFeeItem(id)
+}
+
+func (s *feeService) GetStudentDiscounts(studentID uint) ([]models.Discount, error) {
+	if _, err := s.core.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDiscountsByStudentIDs([]uint{studentID})
+}
+
+func (s *feeService) CreateDiscount(discount *models.Discount) error {
+	discount.Name = strings.TrimSpace(discount.Name)
+	if discount.Kind == "" {
+		discount.Kind = models.DiscountKindDiscount
+	}
+	if discount.Kind != models.DiscountKindDiscount && discount.Kind != models.DiscountKindScholarship {
+		return invalid("kind must be %q or %q", models.DiscountKindDiscount, models.DiscountKindScholarship)
+	}
+	if discount.Percent < 0 || discount.Percent > 100 {
+		return invalid("percent must be between 0 and 100")
+	}
+	if discount.Amount < 0 {
+		return invalid("amount must not be negative")
+	}
+	if discount.Percent == 0 && discount.Amount == 0 {
+		return invalid("percent or amount is required")
+	}
+	student, err := s.core.GetStudentByID(discount.StudentID)
+	if err != nil {
+		return err
+	}
+	if discount.FeeItemID != 0 {
+		item, err := s.repo.GetFeeItemByID(discount.FeeItemID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return invalid("fee item %d does not exist", discount.FeeItemID)
+			}
+			return err
+		}
+		class, err := s.core.GetClassByID(student.ClassID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if err != nil || class.SchoolID != item.SchoolID {
+			return invalid("fee item %d is not a fee of student %d's school", item.ID, student.ID)
+		}
+	}
+	return s.repo.CreateDiscount(discount)
+}
+
+func (s *feeService) DeleteDiscount(id uint) error {
+	return s.repo.DeleteDiscount(id)
+}
+
+func (s *feeService) GenerateInvoices(termID uint, opts InvoiceOptions) ([]models.Invoice, error) {
+	term, err := s.academic.GetTermByID(termID)
+	if err != nil {
+		return nil, err
+	}
+	classes, err := s.core.GetClassesBySchoolIDs([]uint{term.SchoolID})
+	if err != nil {
+		return nil, err
+	}
+	var classIDs []uint
+	for _, class := range classes {
+		if opts.ClassID == 0 || class.ID == opts.ClassID {
+			classIDs = append(classIDs, class.ID)
+		}
+	}
+	if opts.ClassID != 0 && len(classIDs) == 0 {
+		return nil, invalid("class %d is not a class of school %d", opts.ClassID, term.SchoolID)
+	}
+	dueDate := term.StartDate
+	if !opts.DueDate.IsZero() {
+		dueDate = day(opts.DueDate)
+	}
+
+	students, err := s.core.GetStudentsByClassIDs(classIDs)
+	if err != nil {
+		return nil, err
+	}
+	invoiced, err := s.repo.GetInvoicedStudentIDs(term.ID)
+	if err != nil {
+		return nil, err
+	}
+	skip := make(map[uint]bool, len(invoiced))
+	for _, id := range invoiced {
+		skip[id] = true
+	}
+	items, err := s.repo.GetFeeItemsBySchoolID(term.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+	studentIDs := make([]uint, len(students))
+	for i, student := range students {
+		studentIDs[i] = student.ID
+	}
+	discounts, err := s.repo.GetDiscountsByStudentIDs(studentIDs)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint][]models.Discount)
+	for _, discount := range discounts {
+		byStudent[discount.StudentID] = append(byStudent[discount.StudentID], discount)
+	}
+
+	invoices := []models.Invoice{}
+	for _, student := range students {
+		if skip[student.ID] {
+			continue
+		}
+		var charged []models.FeeItem
+		for _, item := range items {
+			if item.ClassID == 0 || item.ClassID == student.ClassID {
+				charged = append(charged, item)
+			}
+		}
+		if len(charged) == 0 {
+			continue
+		}
+		lines, subtotal, discount := fees.Price(charged, byStudent[student.ID])
+		invoices = append(invoices, models.Invoice{
+			Number:    fmt.Sprintf("INV-%d-%d", term.ID, student.ID),
+			StudentID: student.ID,
+			TermID:    term.ID,
+			SchoolID:  term.SchoolID,
+			ClassID:   student.ClassID,
+			DueDate:   dueDate,
+			Subtotal:  subtotal,
+			Discount:  discount,
+			Total:     subtotal - discount,
+			Lines:     lines,
+		})
+	}
+	if len(invoices) == 0 {
+		return invoices, nil
+	}
+	if err := s.repo.CreateInvoices(invoices); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (s *feeService) GetInvoices(filter repository.InvoiceFilter) ([]models.Invoice, error) {
+	return s.repo.GetInvoices(filter)
+}
+
+func (s *feeService) GetInvoiceByID(id uint) (*models.Invoice, error) {
+	return s.repo.GetInvoiceByID(id)
+}
+
+func (s *feeService) GetInvoiceDocument(id uint) (*fees.Document, error) {
+	invoice, err := s.repo.GetInvoiceByID(id)
+	if err != nil {
+		return nil, err
+	}
+	doc := &fees.Document{Invoice: *invoice}
+	student, err := s.core.GetStudentByID(invoice.StudentID)
+	if err != nil {
+		return nil, err
+	}
+	doc.Student = *student
+	school, err := s.core.GetSchoolByID(invoice.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+	doc.School = *school
+	if class, err := s.core.GetClassByID(invoice.ClassID); err == nil {
+		doc.Class = *class
+	}
+	term, err := s.academic.GetTermByID(invoice.TermID)
+	if err != nil {
+		return nil, err
+	}
+	doc.Term = *term
+	return doc, nil
+}
+
+func (s *feeService) RecordPayment(payment *models.Payment) error {
+	if payment.Amount <= 0 {
+		return invalid("amount must be positive")
+	}
+	payment.Method = strings.TrimSpace(payment.Method)
+	payment.Reference = strings.TrimSpace(payment.Reference)
+	if payment.PaidAt.IsZero() {
+		payment.PaidAt = time.Now()
+	}
+	payment.ID = 0
+	err := s.repo.CreatePayment(payment)
+	if errors.Is(err, repository.ErrOverpayment) {
+		return invalid("payment of %s exceeds the balance of invoice %d", fees.FormatAmount(payment.Amount), payment.InvoiceID)
+	}
+	return err
+}
+
+func (s *feeService) GetStudentLedger(studentID uint) ([]models.LedgerEntry, error) {
+	return s.repo.GetLedgerEntries(studentID)
+}
+
+func (s *feeService) GetOutstandingBalances(filter repository.InvoiceFilter) ([]repository.Balance, error) {
+	return s.repo.GetBalances(filter)
+}
+
+func (s *feeService) validateFeeItem(item *models.FeeItem) error {
+	item.Name = strings.TrimSpace(item.Name)
+	if item.Name == "" {
+		return invalid("name is required")
+	}
+	if item.Amount <= 0 {
+		return invalid("amount must be positive")
+	}
+	if _, err := s.core.GetSchoolByID(item.SchoolID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("school %d does not exist", item.SchoolID)
+		}
+		return err
+	}
+	if item.ClassID != 0 {
+		class, err := s.core.GetClassByID(item.ClassID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return invalid("class %d does not exist", item.ClassID)
+			}
+			return err
+		}
+		if class.SchoolID != item.SchoolID {
+			return invalid("class %d is not a class of school %d", item.ClassID, item.SchoolID)
+		}
+	}
+	return nil
+}
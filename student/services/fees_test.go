@@ -0,0 +1,34 @@
+package services_test
+
+import (
+	"errors"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateDiscount_FeeItemOfAnotherSchool(t *testing.T) {
+	db := openTestDB(t)
+	rows := []interface{}{}
+	for _, id := range []uint{1, 2} {
+		class := models.Class{SchoolID: id}
+		class.ID = id * 10
+		item := models.FeeItem{SchoolID: id, Name: "Tuition", Amount: 50000}
+		item.ID = id
+		rows = append(rows, &class, &item)
+	}
+	student := models.Student{ClassID: 10}
+	student.ID = 100
+	rows = append(rows, &student)
+	create(t, db, rows...)
+	service := services.NewFeeService(repository.NewFeeRepository(db), repository.NewAcademicRepository(db), repository.NewRepository(db))
+
+	var validation *services.ValidationError
+	err := service.CreateDiscount(&models.Discount{StudentID: 100, FeeItemID: 2, Percent: 50})
+	assert.True(t, errors.As(err, &validation))
+
+	assert.Nil(t, service.CreateDiscount(&models.Discount{StudentID: 100, FeeItemID: 1, Percent: 50}))
+}
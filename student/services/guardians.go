@@ -0,0 +1,194 @@
+package services
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+type GuardianService interface {
+	GetAllGuardians() ([]models.Guardian, error)
+	ListGuardians(offset, limit int) ([]models.Guardian, int64, error)
+	GetGuardianByID(id uint) (*models.Guardian, error)
+	CreateGuardian(guardian *models.Guardian) error
+	UpdateGuardian(guardian *models.Guardian) error
+	DeleteGuardian(id uint) error
+
+	// GetGuardianStudents returns the guardian's links to students.
+	GetGuardianStudents(guardianID uint) ([]models.StudentGuardian, error)
+	// GetStudentContacts returns the student's guardians in emergency
+	// priority order.
+	GetStudentContacts(studentID uint) ([]models.StudentGuardian, error)
+	// LinkGuardian links a guardian to a student. A zero priority puts
+	// the guardian after the student's existing contacts.
+	LinkGuardian(link *models.StudentGuardian) error
+	UpdateLink(link *models.StudentGuardian) error
+	UnlinkGuardian(studentID, guardianID uint) error
+}
+
+type guardianService struct {
+	repo repository.GuardianRepository
+	core repository.Repository
+}
+
+func NewGuardianService(repo repository.GuardianRepository, core repository.Repository) *guardianService {
+	return &guardianService{
+		repo: repo,
+		core: core,
+	}
+}
+
+func (s *guardianService) GetAllGuardians() ([]models.Guardian, error) {
+	return s.repo.GetGuardians()
+}
+
+func (s *guardianService) ListGuardians(offset, limit int) ([]models.Guardian, int64, error) {
+	return s.repo.GetGuardiansPage(offset, limit)
+}
+
+func (s *guardianService) GetGuardianByID(id uint) (*models.Guardian, error) {
+	return s.repo.GetGuardianByID(id)
+}
+
+func (s *guardianService) CreateGuardian(guardian *models.Guardian) error {
+	if err := validateGuardian(guardian); err != nil {
+		return err
+	}
+	return s.repo.CreateGuardian(guardian)
+}
+
+func (s *guardianService) UpdateGuardian(guardian *models.Guardian) error {
+	if err := validateGuardian(guardian); err != nil {
+		return err
+	}
+	return s.repo.UpdateGuardian(guardian)
+}
+
+func (s *guardianService) DeleteGuardian(id uint) error {
+	return s.repo.DeleteGuardian(id)
+}
+
+func (s *guardianService) GetGuardianStudents(guardianID uint) ([]models.StudentGuardian, error) {
+	if _, err := s.repo.GetGuardianByID(guardianID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLinksByGuardianID(guardianID)
+}
+
+func (s *guardianService) GetStudentContacts(studentID uint) ([]models.StudentGuardian, error) {
+	if _, err := s.core.GetStudentByID(studentID); err != nil {
+		return nil, err
+	}
+	links, err := s.repo.GetLinksByStudentID(studentID)
+	if err != nil {
+		return nil, err
+	}
+	guardianIDs := make([]uint, len(links))
+	for i, link := range links {
+		guardianIDs[i] = link.GuardianID
+	}
+	guardians, err := s.repo.GetGuardiansByIDs(guardianIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Guardian, len(guardians))
+	for i := range guardians {
+		byID[guardians[i].ID] = &guardians[i]
+	}
+	for i := range links {
+		links[i].Guardian = byID[links[i].GuardianID]
+	}
+	return links, nil
+}
+
+func (s *guardianService) LinkGuardian(link *models.StudentGuardian) error {
+	if err := validateLink(link); err != nil {
+		return err
+	}
+	if _, err := s.core.GetStudentByID(link.StudentID); err != nil {
+		return err
+	}
+	if _, err := s.repo.GetGuardianByID(link.GuardianID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("guardian %d does not exist", link.GuardianID)
+		}
+		return err
+	}
+	existing, err := s.repo.GetLinksByStudentID(link.StudentID)
+	if err != nil {
+		return err
+	}
+	last := 0
+	for _, other := range existing {
+		if other.GuardianID == link.GuardianID {
+			return invalid("guardian %d is already linked to student %d", link.GuardianID, link.StudentID)
+		}
+		last = max(last, other.Priority)
+	}
+	if link.Priority == 0 {
+		link.Priority = last + 1
+	}
+	link.ID = 0
+	return s.repo.CreateLink(link)
+}
+
+func (s *guardianService) UpdateLink(link *models.StudentGuardian) error {
+	if err := validateLink(link); err != nil {
+		return err
+	}
+	current, err := s.repo.GetLink(link.StudentID, link.GuardianID)
+	if err != nil {
+		return err
+	}
+	link.ID = current.ID
+	link.CreatedAt = current.CreatedAt
+	if link.Priority == 0 {
+		link.Priority = current.Priority
+	}
+	return s.repo.UpdateLink(link)
+}
+
+func (s *guardianService) UnlinkGuardian(studentID, guardianID uint) error {
+	return s.repo.DeleteLink(studentID, guardianID)
+}
+
+func validateGuardian(guardian *models.Guardian) error {
+	guardian.Name = strings.TrimSpace(guardian.Name)
+	if guardian.Name == "" {
+		return invalid("name is required")
+	}
+	if guardian.Email != "" {
+		if _, err := mail.ParseAddress(guardian.Email); err != nil {
+			return invalid("email %q is not a valid address", guardian.Email)
+		}
+	}
+	phones := guardian.Phones[:0]
+	for _, phone := range guardian.Phones {
+		if phone = strings.TrimSpace(phone); phone != "" {
+			if strings.Contains(phone, ",") {
+				return invalid("phone %q must not contain commas", phone)
+			}
+			phones = append(phones, phone)
+		}
+	}
+	guardian.Phones = phones
+	if len(guardian.Phones) == 0 && guardian.Email == "" {
+		return invalid("a phone number or email is required")
+	}
+	return nil
+}
+
+func validateLink(link *models.StudentGuardian) error {
+	link.Relationship = strings.TrimSpace(link.Relationship)
+	if link.Relationship == "" {
+		return invalid("relationship is required")
+	}
+	if link.Priority < 0 {
+		return invalid("priority must not be negative")
+	}
+	return nil
+}
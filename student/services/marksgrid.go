@@ -0,0 +1,210 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"stu/models"
+	"stu/repository"
+	"time"
+)
+
+// AssessmentMarks selects the students' own marks in a marks grid. Any
+// other assessment is the ID of one of the class's exams.
+const AssessmentMarks = "marks"
+
+// maxStudentMarks bounds the students' own marks, which are percentages.
+const maxStudentMarks = 100
+
+// MarksGrid is a class roster with each student's marks in one
+// assessment. Version identifies the marks a row was read with; saving a
+// row whose version is outdated fails as a conflict.
+type MarksGrid struct {
+	ClassID    uint           `json:"class_id"`
+	Assessment string         `json:"assessment"`
+	Exam       *models.Exam   `json:"exam,omitempty"`
+	MaxMarks   int            `json:"max_marks"`
+	Editable   bool           `json:"editable"`
+	Rows       []MarksGridRow `json:"rows"`
+}
+
+type MarksGridRow struct {
+	StudentID uint       `json:"student_id"`
+	Name      string     `json:"name"`
+	Marks     *int       `json:"marks"`
+	Version   *time.Time `json:"version"`
+}
+
+// GridCell is a row of a marks grid sent back for saving. Cells without
+// marks are left as they are.
+type GridCell struct {
+	StudentID uint       `json:"student_id"`
+	Marks     *int       `json:"marks"`
+	Version   *time.Time `json:"version"`
+}
+
+// CellError is why a cell of a marks grid could not be saved.
+type CellError struct {
+	StudentID uint   `json:"student_id"`
+	Error     string `json:"error"`
+}
+
+// MarksGridError reports the cells of a marks grid that could not be
+// saved; nothing was saved. Conflict is set when the cells were changed
+// by someone else since they were read rather than invalid.
+type MarksGridError struct {
+	Conflict bool
+	Cells    []CellError
+}
+
+func (e *MarksGridError) Error() string {
+	if e.Conflict {
+		return fmt.Sprintf("%d marks were changed since they were read", len(e.Cells))
+	}
+	return fmt.Sprintf("%d marks are invalid", len(e.Cells))
+}
+
+func (s *examService) GetMarksGrid(classID uint, assessment string) (*MarksGrid, error) {
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	students, err := s.core.GetStudentsByClassIDs([]uint{classID})
+	if err != nil {
+		return nil, err
+	}
+	grid := &MarksGrid{ClassID: classID, Rows: make([]MarksGridRow, len(students))}
+	for i, student := range students {
+		grid.Rows[i] = MarksGridRow{StudentID: student.ID, Name: student.Name}
+	}
+
+	if assessment == "" || assessment == AssessmentMarks {
+		grid.Assessment = AssessmentMarks
+		grid.MaxMarks = maxStudentMarks
+		computed, err := s.repo.MarksComputed(classID)
+		if err != nil {
+			return nil, err
+		}
+		grid.Editable = !computed
+		for i := range students {
+			grid.Rows[i].Marks = &students[i].Marks
+			grid.Rows[i].Version = students[i].MarksUpdatedAt
+		}
+		return grid, nil
+	}
+
+	exam, err := s.gridExam(classID, assessment)
+	if err != nil {
+		return nil, err
+	}
+	grid.Assessment = assessment
+	grid.Exam = exam
+	grid.MaxMarks = exam.MaxMarks
+	grid.Editable = exam.Status == models.MarksDraft
+	results, err := s.repo.GetResultsByExamIDs([]uint{exam.ID})
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uint]*models.ExamResult, len(results))
+	for i := range results {
+		byStudent[results[i].StudentID] = &results[i]
+	}
+	for i := range grid.Rows {
+		if result, ok := byStudent[grid.Rows[i].StudentID]; ok {
+			grid.Rows[i].Marks = &result.Marks
+			grid.Rows[i].Version = &result.UpdatedAt
+		}
+	}
+	return grid, nil
+}
+
+// SaveMarksGrid saves the cells whose marks differ from the stored ones in
+// one transaction and returns the grid as saved. Invalid or stale cells are
+// reported together in a MarksGridError.
+func (s *examService) SaveMarksGrid(classID uint, assessment string, cells []GridCell) (*MarksGrid, error) {
+	if len(cells) == 0 {
+		return nil, invalid("no marks given")
+	}
+	if len(cells) > maxResultEntries {
+		return nil, invalid("at most %d marks can be saved at once", maxResultEntries)
+	}
+	grid, err := s.GetMarksGrid(classID, assessment)
+	if err != nil {
+		return nil, err
+	}
+	if !grid.Editable {
+		if grid.Exam != nil {
+			return nil, editable(grid.Exam)
+		}
+		return nil, invalid("marks of class %d are computed from its published exams", classID)
+	}
+
+	rows := make(map[uint]MarksGridRow, len(grid.Rows))
+	for _, row := range grid.Rows {
+		rows[row.StudentID] = row
+	}
+	var cellErrors []CellError
+	var changed []repository.MarksCell
+	seen := make(map[uint]bool, len(cells))
+	for _, cell := range cells {
+		row, ok := rows[cell.StudentID]
+		switch {
+		case !ok:
+			cellErrors = append(cellErrors, CellError{cell.StudentID, fmt.Sprintf("student is not in class %d", classID)})
+			continue
+		case seen[cell.StudentID]:
+			cellErrors = append(cellErrors, CellError{cell.StudentID, "student appears more than once"})
+			continue
+		}
+		seen[cell.StudentID] = true
+		if cell.Marks == nil {
+			continue
+		}
+		if *cell.Marks < 0 || *cell.Marks > grid.MaxMarks {
+			cellErrors = append(cellErrors, CellError{cell.StudentID, fmt.Sprintf("marks must be between 0 and %d", grid.MaxMarks)})
+			continue
+		}
+		if row.Marks != nil && *row.Marks == *cell.Marks {
+			continue
+		}
+		changed = append(changed, repository.MarksCell{StudentID: cell.StudentID, Marks: *cell.Marks, Version: cell.Version})
+	}
+	if len(cellErrors) > 0 {
+		return nil, &MarksGridError{Cells: cellErrors}
+	}
+	if len(changed) == 0 {
+		return grid, nil
+	}
+
+	var stale []uint
+	if grid.Exam != nil {
+		stale, err = s.repo.SaveExamMarks(grid.Exam.ID, changed)
+	} else {
+		stale, err = s.repo.SaveClassMarks(classID, changed)
+	}
+	if len(stale) > 0 {
+		conflict := &MarksGridError{Conflict: true, Cells: make([]CellError, len(stale))}
+		for i, studentID := range stale {
+			conflict.Cells[i] = CellError{studentID, "marks were changed since they were read"}
+		}
+		return nil, conflict
+	}
+	if err != nil {
+		return nil, err
+	}
+	return s.GetMarksGrid(classID, assessment)
+}
+
+// gridExam returns the class's exam named by assessment.
+func (s *examService) gridExam(classID uint, assessment string) (*models.Exam, error) {
+	id, err := strconv.ParseUint(assessment, 10, 64)
+	if err != nil {
+		return nil, invalid("assessment must be %q or an exam ID", AssessmentMarks)
+	}
+	exam, err := s.repo.GetExamByID(uint(id))
+	if err != nil {
+		return nil, err
+	}
+	if exam.ClassID != classID {
+		return nil, invalid("exam %d is not an exam of class %d", exam.ID, classID)
+	}
+	return exam, nil
+}
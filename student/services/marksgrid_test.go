@@ -0,0 +1,44 @@
+package services_test
+
+import (
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSaveMarksGrid_OtherEditsDoNotConflict(t *testing.T) {
+	db, service := newExamFixture(t)
+	students := services.NewService(repository.NewRepository(db))
+	grid, err := service.GetMarksGrid(10, services.AssessmentMarks)
+	assert.Nil(t, err)
+	read := grid.Rows[0]
+
+	student, err := students.GetStudentByID(read.StudentID)
+	assert.Nil(t, err)
+	student.Name = "Renamed"
+	assert.Nil(t, students.UpdateStudent(student))
+	marks := 70
+	_, err = service.SaveMarksGrid(10, services.AssessmentMarks, []services.GridCell{{StudentID: read.StudentID, Marks: &marks, Version: read.Version}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, 70, studentMarks(t, db, read.StudentID))
+}
+
+func TestSaveMarksGrid_ChangedMarksConflict(t *testing.T) {
+	db, service := newExamFixture(t)
+	grid, err := service.GetMarksGrid(10, services.AssessmentMarks)
+	assert.Nil(t, err)
+	read := grid.Rows[0]
+	first, second := 70, 80
+	_, err = service.SaveMarksGrid(10, services.AssessmentMarks, []services.GridCell{{StudentID: read.StudentID, Marks: &first, Version: read.Version}})
+	assert.Nil(t, err)
+
+	_, err = service.SaveMarksGrid(10, services.AssessmentMarks, []services.GridCell{{StudentID: read.StudentID, Marks: &second, Version: read.Version}})
+
+	var gridErr *services.MarksGridError
+	assert.ErrorAs(t, err, &gridErr)
+	assert.True(t, gridErr.Conflict)
+	assert.Equal(t, 70, studentMarks(t, db, read.StudentID))
+}
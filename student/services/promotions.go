@@ -0,0 +1,267 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+// ClassProgression names the class the students of a class move up to.
+// A zero NextClassID makes the class a graduating class.
+type ClassProgression struct {
+	ClassID     uint `json:"class_id"`
+	NextClassID uint `json:"next_class_id"`
+}
+
+// PromotionRules decide the outcome proposed for each student: those whose
+// average marks over the academic year's terms reach PassMark move up
+// according to Progression, everyone else repeats their class. Students
+// without term marks are judged on their current marks. Every class of
+// the school with students needs a progression.
+type PromotionRules struct {
+	AcademicYearID uint
+	PassMark       int
+	Progression    []ClassProgression
+}
+
+type PromotionService interface {
+	GetPromotionPlans(schoolID uint) ([]models.PromotionPlan, error)
+	GetPromotionPlan(id uint) (*models.PromotionPlan, error)
+	// DraftPromotionPlan proposes an outcome for every student of the
+	// school. Nothing changes until the plan is applied.
+	DraftPromotionPlan(schoolID uint, rules PromotionRules) (*models.PromotionPlan, error)
+	// OverridePromotion replaces the outcome proposed for a student of a
+	// draft plan. toClassID is only used for promotions.
+	OverridePromotion(planID, studentID uint, outcome string, toClassID uint) (*models.PromotionEntry, error)
+	// ApplyPromotionPlan applies a draft plan atomically. The school must
+	// have moved on from the plan's academic year, so the marks and classes
+	// of its terms stay as they were.
+	ApplyPromotionPlan(id uint) (*models.PromotionPlan, error)
+	DeletePromotionPlan(id uint) error
+}
+
+type promotionService struct {
+	repo     repository.PromotionRepository
+	academic repository.AcademicRepository
+	core     repository.Repository
+}
+
+func NewPromotionService(repo repository.PromotionRepository, academic repository.AcademicRepository, core repository.Repository) *promotionService {
+	return &promotionService{
+		repo:     repo,
+		academic: academic,
+		core:     core,
+	}
+}
+
+func (s *promotionService) GetPromotionPlans(schoolID uint) ([]models.PromotionPlan, error) {
+	if _, err := s.core.GetSchoolByID(schoolID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPromotionPlansBySchoolID(schoolID)
+}
+
+func (s *promotionService) GetPromotionPlan(id uint) (*models.PromotionPlan, error) {
+	return s.repo.GetPromotionPlanByID(id)
+}
+
+func (s *promotionService) DraftPromotionPlan(schoolID uint, rules PromotionRules) (*models.PromotionPlan, error) {
+	if _, err := s.core.GetSchoolByID(schoolID); err != nil {
+		return nil, err
+	}
+	year, err := s.academic.GetAcademicYearByID(rules.AcademicYearID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid("academic year %d does not exist", rules.AcademicYearID)
+		}
+		return nil, err
+	}
+	if year.SchoolID != schoolID {
+		return nil, invalid("academic year %d belongs to another school", year.ID)
+	}
+	if rules.PassMark < 0 {
+		return nil, invalid("pass_mark must not be negative")
+	}
+
+	classes, err := s.core.GetClassesBySchoolIDs([]uint{schoolID})
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uint]string, len(classes))
+	classIDs := make([]uint, len(classes))
+	for i, class := range classes {
+		names[class.ID] = class.ClassName
+		classIDs[i] = class.ID
+	}
+	next := make(map[uint]uint, len(rules.Progression))
+	for _, progression := range rules.Progression {
+		if _, ok := names[progression.ClassID]; !ok {
+			return nil, invalid("class %d is not a class of school %d", progression.ClassID, schoolID)
+		}
+		if _, ok := names[progression.NextClassID]; !ok && progression.NextClassID != 0 {
+			return nil, invalid("class %d is not a class of school %d", progression.NextClassID, schoolID)
+		}
+		if progression.NextClassID == progression.ClassID {
+			return nil, invalid("class %d cannot progress to itself", progression.ClassID)
+		}
+		if _, ok := next[progression.ClassID]; ok {
+			return nil, invalid("class %d has more than one progression", progression.ClassID)
+		}
+		next[progression.ClassID] = progression.NextClassID
+	}
+
+	students, err := s.core.GetStudentsByClassIDs(classIDs)
+	if err != nil {
+		return nil, err
+	}
+	yearMarks, err := s.yearMarks(year.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.PromotionPlan{
+		SchoolID:       schoolID,
+		AcademicYearID: year.ID,
+		PassMark:       rules.PassMark,
+		Status:         models.PromotionDraft,
+		Entries:        make([]models.PromotionEntry, 0, len(students)),
+	}
+	for _, student := range students {
+		nextClassID, ok := next[student.ClassID]
+		if !ok {
+			return nil, invalid("no next class given for class %d (%s)", student.ClassID, names[student.ClassID])
+		}
+		marks := float64(student.Marks)
+		if termMarks, ok := yearMarks[student.ID]; ok {
+			marks = termMarks
+		}
+		entry := models.PromotionEntry{
+			StudentID:   student.ID,
+			FromClassID: student.ClassID,
+			ToClassID:   student.ClassID,
+			Marks:       marks,
+			Outcome:     models.OutcomeRepeated,
+		}
+		if marks >= float64(rules.PassMark) {
+			entry.ToClassID = nextClassID
+			entry.Outcome = models.OutcomePromoted
+			if nextClassID == 0 {
+				entry.Outcome = models.OutcomeGraduated
+			}
+		}
+		plan.Entries = append(plan.Entries, entry)
+	}
+	if err := s.repo.CreatePromotionPlan(plan); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// yearMarks averages each student's marks over the terms of the year.
+func (s *promotionService) yearMarks(yearID uint) (map[uint]float64, error) {
+	terms, err := s.academic.GetTermsByAcademicYearID(yearID)
+	if err != nil {
+		return nil, err
+	}
+	sums := map[uint]int{}
+	counts := map[uint]int{}
+	for _, term := range terms {
+		enrollments, err := s.academic.GetEnrollmentsByTermID(term.ID, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, enrollment := range enrollments {
+			sums[enrollment.StudentID] += enrollment.Marks
+			counts[enrollment.StudentID]++
+		}
+	}
+	averages := make(map[uint]float64, len(sums))
+	for studentID, sum := range sums {
+		averages[studentID] = float64(sum) / float64(counts[studentID])
+	}
+	return averages, nil
+}
+
+func (s *promotionService) OverridePromotion(planID, studentID uint, outcome string, toClassID uint) (*models.PromotionEntry, error) {
+	plan, err := s.draftPlan(planID)
+	if err != nil {
+		return nil, err
+	}
+	var entry *models.PromotionEntry
+	for i := range plan.Entries {
+		if plan.Entries[i].StudentID == studentID {
+			entry = &plan.Entries[i]
+		}
+	}
+	if entry == nil {
+		return nil, fmt.Errorf("student %d is not in plan %d: %w", studentID, planID, gorm.ErrRecordNotFound)
+	}
+	switch outcome {
+	case models.OutcomePromoted:
+		class, err := s.core.GetClassByID(toClassID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, invalid("class %d does not exist", toClassID)
+			}
+			return nil, err
+		}
+		if class.SchoolID != plan.SchoolID {
+			return nil, invalid("class %d is not a class of school %d", toClassID, plan.SchoolID)
+		}
+		if class.ID == entry.FromClassID {
+			return nil, invalid("a promoted student must change class")
+		}
+		entry.ToClassID = class.ID
+	case models.OutcomeRepeated:
+		entry.ToClassID = entry.FromClassID
+	case models.OutcomeGraduated:
+		entry.ToClassID = 0
+	default:
+		return nil, invalid("outcome must be %q, %q or %q", models.OutcomePromoted, models.OutcomeRepeated, models.OutcomeGraduated)
+	}
+	entry.Outcome = outcome
+	entry.Overridden = true
+	if err := s.repo.UpdatePromotionEntry(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *promotionService) ApplyPromotionPlan(id uint) (*models.PromotionPlan, error) {
+	plan, err := s.draftPlan(id)
+	if err != nil {
+		return nil, err
+	}
+	term, err := s.academic.GetActiveTerm(plan.SchoolID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil && term.AcademicYearID == plan.AcademicYearID {
+		return nil, invalid("term %q of the plan's academic year is still active; activate the next year's first term before applying", term.Name)
+	}
+	if err := s.repo.ApplyPromotionPlan(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetPromotionPlanByID(id)
+}
+
+func (s *promotionService) DeletePromotionPlan(id uint) error {
+	if _, err := s.draftPlan(id); err != nil {
+		return err
+	}
+	return s.repo.DeletePromotionPlan(id)
+}
+
+func (s *promotionService) draftPlan(id uint) (*models.PromotionPlan, error) {
+	plan, err := s.repo.GetPromotionPlanByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if plan.Status != models.PromotionDraft {
+		return nil, invalid("plan %d was already applied", id)
+	}
+	return plan, nil
+}
@@ -0,0 +1,103 @@
+package services_test
+
+import (
+	"stu/events"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// newPromotionFixture has school 1 with class 10, which moves up to class
+// 20, and graduating class 20, and a draft plan 1 for academic year 1
+// with the given entries. Each entry's student is created in its
+// FromClassID.
+func newPromotionFixture(t *testing.T, capacity int, entries ...models.PromotionEntry) (*gorm.DB, services.PromotionService) {
+	t.Helper()
+	db := openTestDB(t)
+	school := models.School{Name: "North"}
+	school.ID = 1
+	year := models.AcademicYear{SchoolID: 1}
+	year.ID = 1
+	rows := []interface{}{&school, &year}
+	for _, id := range []uint{10, 20} {
+		class := models.Class{SchoolID: 1, Capacity: capacity}
+		class.ID = id
+		rows = append(rows, &class)
+	}
+	plan := models.PromotionPlan{ID: 1, SchoolID: 1, AcademicYearID: 1, Status: models.PromotionDraft}
+	rows = append(rows, &plan)
+	for i := range entries {
+		student := models.Student{ClassID: entries[i].FromClassID}
+		student.ID = entries[i].StudentID
+		entries[i].PlanID = 1
+		rows = append(rows, &student, &entries[i])
+	}
+	create(t, db, rows...)
+	repo := repository.NewRepository(db)
+	return db, services.NewPromotionService(repository.NewPromotionRepository(db), repository.NewAcademicRepository(db), repo)
+}
+
+func TestApplyPromotionPlan_KeepsGraduates(t *testing.T) {
+	db, service := newPromotionFixture(t, 0,
+		models.PromotionEntry{StudentID: 100, FromClassID: 20, Outcome: models.OutcomeGraduated},
+		models.PromotionEntry{StudentID: 101, FromClassID: 10, ToClassID: 20, Outcome: models.OutcomePromoted},
+	)
+
+	_, err := service.ApplyPromotionPlan(1)
+	assert.Nil(t, err)
+
+	var graduate models.Student
+	assert.Nil(t, db.First(&graduate, 100).Error)
+	assert.Equal(t, uint(0), graduate.ClassID)
+	assert.NotNil(t, graduate.GraduatedAt)
+
+	var recorded []models.OutboxEvent
+	assert.Nil(t, db.Where("entity_id = ?", 100).Find(&recorded).Error)
+	var types []string
+	for _, event := range recorded {
+		types = append(types, event.Type)
+		assert.Equal(t, uint(20), event.ClassID)
+	}
+	assert.Equal(t, []string{events.StudentGraduated}, types)
+}
+
+func TestApplyPromotionPlan_MovesIntoClassesEmptiedByThePlan(t *testing.T) {
+	// Class 20 is full until student 101 graduates, after student 100
+	// moves up into it. Student 102 waits for a seat in class 10.
+	db, service := newPromotionFixture(t, 1,
+		models.PromotionEntry{StudentID: 100, FromClassID: 10, ToClassID: 20, Outcome: models.OutcomePromoted},
+		models.PromotionEntry{StudentID: 101, FromClassID: 20, Outcome: models.OutcomeGraduated},
+	)
+	waiting := models.Student{}
+	waiting.ID = 102
+	create(t, db, &waiting, &models.WaitlistEntry{ClassID: 10, StudentID: 102})
+
+	_, err := service.ApplyPromotionPlan(1)
+	assert.Nil(t, err)
+
+	classes := map[uint]uint{}
+	var students []models.Student
+	assert.Nil(t, db.Order("id").Find(&students).Error)
+	for _, student := range students {
+		classes[student.ID] = student.ClassID
+	}
+	assert.Equal(t, map[uint]uint{100: 20, 101: 0, 102: 10}, classes)
+}
+
+func TestApplyPromotionPlan_ClassFullOnceEveryStudentMoved(t *testing.T) {
+	db, service := newPromotionFixture(t, 1,
+		models.PromotionEntry{StudentID: 100, FromClassID: 10, ToClassID: 20, Outcome: models.OutcomePromoted},
+		models.PromotionEntry{StudentID: 101, FromClassID: 20, ToClassID: 20, Outcome: models.OutcomeRepeated},
+	)
+
+	_, err := service.ApplyPromotionPlan(1)
+	assert.ErrorIs(t, err, repository.ErrClassFull)
+
+	var student models.Student
+	assert.Nil(t, db.First(&student, 100).Error)
+	assert.Equal(t, uint(10), student.ClassID)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"slices"
+	"strings"
+	"stu/models"
+)
+
+// Actions of the marks publishing workflow.
+const (
+	MarksSubmit  = "submit"
+	MarksApprove = "approve"
+	MarksReturn  = "return"
+	MarksPublish = "publish"
+	MarksReopen  = "reopen"
+)
+
+type marksTransition struct {
+	from   []string
+	to     string
+	reason bool
+}
+
+// marksTransitions is the publishing workflow: a teacher submits draft
+// marks, the head of department approves them or returns them with a
+// reason, and approved marks are published to students and parents.
+// Published marks are locked until reopened with a reason.
+var marksTransitions = map[string]marksTransition{
+	MarksSubmit:  {from: []string{models.MarksDraft}, to: models.MarksSubmitted},
+	MarksApprove: {from: []string{models.MarksSubmitted}, to: models.MarksApproved},
+	MarksReturn:  {from: []string{models.MarksSubmitted, models.MarksApproved}, to: models.MarksDraft, reason: true},
+	MarksPublish: {from: []string{models.MarksApproved}, to: models.MarksPublished},
+	MarksReopen:  {from: []string{models.MarksPublished}, to: models.MarksDraft, reason: true},
+}
+
+func (s *examService) TransitionMarks(examID uint, action, actor, reason string) (*models.Exam, error) {
+	transition, ok := marksTransitions[action]
+	if !ok {
+		return nil, invalid("unknown action %q", action)
+	}
+	actor = strings.TrimSpace(actor)
+	reason = strings.TrimSpace(reason)
+	if actor == "" {
+		return nil, invalid("actor is required")
+	}
+	if transition.reason && reason == "" {
+		return nil, invalid("a reason is required to %s marks", action)
+	}
+	exam, err := s.repo.GetExamByID(examID)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(transition.from, exam.Status) {
+		return nil, invalid("cannot %s marks that are %s", action, exam.Status)
+	}
+	if action == MarksSubmit {
+		results, err := s.repo.GetResultsByExamIDs([]uint{exam.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(results) == 0 {
+			return nil, invalid("exam %d has no results to submit", exam.ID)
+		}
+	}
+
+	change := &models.ExamStatusChange{
+		ExamID: exam.ID,
+		Action: action,
+		From:   exam.Status,
+		To:     transition.to,
+		Actor:  actor,
+		Reason: reason,
+	}
+	if err := s.repo.ChangeExamStatus(change); err != nil {
+		return nil, err
+	}
+	exam.Status = transition.to
+	return exam, nil
+}
+
+func (s *examService) GetMarksHistory(examID uint) ([]models.ExamStatusChange, error) {
+	if _, err := s.repo.GetExamByID(examID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetExamStatusChanges(examID)
+}
+
+// editable returns a validation error unless the exam's marks are a
+// draft, the only state in which exams and results can be changed.
+func editable(exam *models.Exam) error {
+	if exam.Status != models.MarksDraft {
+		return invalid("marks of exam %d are %s; only draft marks can be changed", exam.ID, exam.Status)
+	}
+	return nil
+}
@@ -0,0 +1,405 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"stu/assessment"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+// Ranking methods for tied marks.
+const (
+	RankingCompetition = "competition"
+	RankingDense       = "dense"
+)
+
+type RankingOptions struct {
+	// Method is RankingCompetition (1, 2, 2, 4) or RankingDense
+	// (1, 2, 2, 3); empty means competition.
+	Method string
+	// TermID ranks by the marks of a term, from the students' enrollments
+	// in it; zero ranks by the students' current marks.
+	TermID uint
+	// Subject ranks by the students' weighted score in the subject over
+	// the published exams of their class in the term, or in the term in
+	// effect at their school when TermID is zero. Students whose class has
+	// no published exam in the subject are not ranked.
+	Subject string
+	Offset  int
+	Limit   int
+}
+
+// RankedStudent is a leaderboard entry. Score is what the student was
+// ranked by: their marks, or their subject score out of 100.
+type RankedStudent struct {
+	Rank       int64          `json:"rank"`
+	Percentile float64        `json:"percentile"`
+	Score      float64        `json:"score"`
+	Student    models.Student `json:"student"`
+}
+
+type RankingService interface {
+	GetClassRanking(id uint, opts RankingOptions) ([]RankedStudent, int64, error)
+	GetSchoolRanking(id uint, opts RankingOptions) ([]RankedStudent, int64, error)
+	GetDistrictRanking(opts RankingOptions) ([]RankedStudent, int64, error)
+	// GetStudentPercentile places the student in their class, school and
+	// the district among the students ranked by the TermID and Subject of
+	// opts. In a term, their class is the one they were enrolled in.
+	GetStudentPercentile(id uint, opts RankingOptions) (*models.Percentile, error)
+}
+
+type rankingService struct {
+	repo     repository.Repository
+	academic repository.AcademicRepository
+	exams    repository.ExamRepository
+}
+
+func NewRankingService(repo repository.Repository, academic repository.AcademicRepository, exams repository.ExamRepository) *rankingService {
+	return &rankingService{
+		repo:     repo,
+		academic: academic,
+		exams:    exams,
+	}
+}
+
+func (s *rankingService) GetClassRanking(id uint, opts RankingOptions) ([]RankedStudent, int64, error) {
+	class, err := s.repo.GetClassByID(id)
+	if err != nil {
+		return nil, 0, err
+	}
+	return s.ranking(repository.StudentScope{ClassID: id}, class.SchoolID, opts)
+}
+
+func (s *rankingService) GetSchoolRanking(id uint, opts RankingOptions) ([]RankedStudent, int64, error) {
+	if _, err := s.repo.GetSchoolByID(id); err != nil {
+		return nil, 0, err
+	}
+	return s.ranking(repository.StudentScope{SchoolID: id}, id, opts)
+}
+
+func (s *rankingService) GetDistrictRanking(opts RankingOptions) ([]RankedStudent, int64, error) {
+	return s.ranking(repository.StudentScope{}, 0, opts)
+}
+
+// ranking ranks the students in scope, which lies in the school unless
+// schoolID is zero.
+func (s *rankingService) ranking(scope repository.StudentScope, schoolID uint, opts RankingOptions) ([]RankedStudent, int64, error) {
+	if opts.Method != "" && opts.Method != RankingCompetition && opts.Method != RankingDense {
+		return nil, 0, invalid("ranking method must be %q or %q", RankingCompetition, RankingDense)
+	}
+	dense := opts.Method == RankingDense
+	if err := s.checkTerm(opts.TermID, schoolID); err != nil {
+		return nil, 0, err
+	}
+	if subject := strings.TrimSpace(opts.Subject); subject != "" {
+		return s.subjectRanking(scope, subject, opts.TermID, dense, opts.Offset, opts.Limit)
+	}
+
+	var rows []repository.RankedStudent
+	var total int64
+	var err error
+	if opts.TermID != 0 {
+		rows, total, err = s.repo.GetTermMarksRanking(scope, opts.TermID, dense, opts.Offset, opts.Limit)
+	} else {
+		rows, total, err = s.repo.GetMarksRanking(scope, dense, opts.Offset, opts.Limit)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	ranked := make([]RankedStudent, len(rows))
+	for i, row := range rows {
+		ranked[i] = RankedStudent{Rank: row.Rank, Percentile: row.Percentile, Score: row.Score, Student: row.Student}
+	}
+	return ranked, total, nil
+}
+
+// checkTerm checks that the term, if any, exists and, unless schoolID is
+// zero, is a term of the school.
+func (s *rankingService) checkTerm(termID, schoolID uint) error {
+	if termID == 0 {
+		return nil
+	}
+	term, err := s.academic.GetTermByID(termID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("term %d does not exist", termID)
+		}
+		return err
+	}
+	if schoolID != 0 && term.SchoolID != schoolID {
+		return invalid("term %d belongs to another school", term.ID)
+	}
+	return nil
+}
+
+func (s *rankingService) GetStudentPercentile(id uint, opts RankingOptions) (*models.Percentile, error) {
+	student, err := s.repo.GetStudentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	subject := strings.TrimSpace(opts.Subject)
+	if opts.TermID == 0 && subject == "" {
+		return marksPercentile(s.repo, student)
+	}
+
+	classID, marks := student.ClassID, student.Marks
+	if opts.TermID != 0 && subject == "" {
+		enrollment, err := s.academic.GetEnrollment(id, opts.TermID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, invalid("student %d was not enrolled in term %d", id, opts.TermID)
+			}
+			return nil, err
+		}
+		classID, marks = enrollment.ClassID, enrollment.Marks
+	}
+	schoolID, err := s.schoolOf(classID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkTerm(opts.TermID, schoolID); err != nil {
+		return nil, err
+	}
+
+	at := func(scope repository.StudentScope) (float64, error) {
+		if subject == "" {
+			return s.repo.GetTermMarksPercentile(scope, opts.TermID, marks)
+		}
+		ranked, _, err := s.subjectRanking(scope, subject, opts.TermID, false, 0, -1)
+		if err != nil {
+			return 0, err
+		}
+		for _, entry := range ranked {
+			if entry.Student.ID == id {
+				return entry.Percentile, nil
+			}
+		}
+		return 0, invalid("student %d has no published score in %s", id, subject)
+	}
+	district, err := at(repository.StudentScope{})
+	if err != nil {
+		return nil, err
+	}
+	percentile := &models.Percentile{District: district}
+	if classID == 0 {
+		return percentile, nil
+	}
+	class, err := at(repository.StudentScope{ClassID: classID})
+	if err != nil {
+		return nil, err
+	}
+	percentile.Class = &class
+	if schoolID == 0 {
+		return percentile, nil
+	}
+	school, err := at(repository.StudentScope{SchoolID: schoolID})
+	if err != nil {
+		return nil, err
+	}
+	percentile.School = &school
+	return percentile, nil
+}
+
+// schoolOf returns the school of the class, or zero if the class was
+// deleted.
+func (s *rankingService) schoolOf(classID uint) (uint, error) {
+	if classID == 0 {
+		return 0, nil
+	}
+	class, err := s.repo.GetClassByID(classID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, nil
+		}
+		return 0, err
+	}
+	return class.SchoolID, nil
+}
+
+// subjectRanking scores the students of the classes in scope that have
+// published exams in the subject in the term, as GetStudentScores does,
+// and ranks them like the repository ranks marks.
+func (s *rankingService) subjectRanking(scope repository.StudentScope, subject string, termID uint, dense bool, offset, limit int) ([]RankedStudent, int64, error) {
+	published, err := s.exams.GetPublishedExams(scope, subject)
+	if err != nil {
+		return nil, 0, err
+	}
+	var classIDs []uint
+	for _, exam := range published {
+		if len(classIDs) == 0 || classIDs[len(classIDs)-1] != exam.ClassID {
+			classIDs = append(classIDs, exam.ClassID)
+		}
+	}
+	if termID == 0 {
+		if err := s.keepTermsInEffect(&published, classIDs); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	byClass := map[uint][]models.Exam{}
+	var examIDs []uint
+	for _, exam := range published {
+		if termID == 0 || exam.TermID == termID {
+			byClass[exam.ClassID] = append(byClass[exam.ClassID], exam)
+			examIDs = append(examIDs, exam.ID)
+		}
+	}
+	results, err := s.exams.GetResultsByExamIDs(examIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+	graded := make(map[uint]bool)
+	marks := make(map[[2]uint]int, len(results))
+	for _, result := range results {
+		graded[result.ExamID] = true
+		marks[[2]uint{result.ExamID, result.StudentID}] = result.Marks
+	}
+	students, err := s.repo.GetStudentsByClassIDs(classIDs)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var ranked []RankedStudent
+	for _, student := range students {
+		exams := byClass[student.ClassID]
+		components := make([]assessment.Component, len(exams))
+		for i, exam := range exams {
+			components[i] = assessment.Component{
+				ExamID:   exam.ID,
+				Subject:  exam.Subject,
+				MaxMarks: exam.MaxMarks,
+				Weight:   exam.Weight,
+				Graded:   graded[exam.ID],
+			}
+			if m, ok := marks[[2]uint{exam.ID, student.ID}]; ok {
+				components[i].Marks = &m
+			}
+		}
+		breakdown := assessment.Compute(components)
+		if !breakdown.Graded {
+			continue
+		}
+		ranked = append(ranked, RankedStudent{Score: breakdown.Final, Student: student})
+	}
+	rank(ranked, dense)
+
+	total := int64(len(ranked))
+	if offset > len(ranked) {
+		offset = len(ranked)
+	}
+	ranked = ranked[offset:]
+	if limit >= 0 && limit < len(ranked) {
+		ranked = ranked[:limit]
+	}
+	return ranked, total, nil
+}
+
+// keepTermsInEffect keeps the exams of the term in effect at the school of
+// their class, its active term.
+func (s *rankingService) keepTermsInEffect(exams *[]models.Exam, classIDs []uint) error {
+	classes, err := s.repo.GetClassesByIDs(classIDs)
+	if err != nil {
+		return err
+	}
+	terms := map[uint]uint{}
+	classTerms := make(map[uint]uint, len(classes))
+	for _, class := range classes {
+		termID, ok := terms[class.SchoolID]
+		if !ok {
+			term, err := s.academic.GetActiveTerm(class.SchoolID)
+			switch {
+			case err == nil:
+				termID = term.ID
+			case !errors.Is(err, gorm.ErrRecordNotFound):
+				return err
+			}
+			terms[class.SchoolID] = termID
+		}
+		classTerms[class.ID] = termID
+	}
+	kept := (*exams)[:0]
+	for _, exam := range *exams {
+		if termID, ok := classTerms[exam.ClassID]; ok && exam.TermID == termID {
+			kept = append(kept, exam)
+		}
+	}
+	*exams = kept
+	return nil
+}
+
+// rank orders the students by score, best first, and sets their ranks and
+// percentiles as the repository's rankings do.
+func rank(ranked []RankedStudent, dense bool) {
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Score != ranked[j].Score {
+			return ranked[i].Score > ranked[j].Score
+		}
+		return ranked[i].Student.ID < ranked[j].Student.ID
+	})
+	total := float64(len(ranked))
+	var distinct int64
+	for i := 0; i < len(ranked); {
+		j := i
+		for j < len(ranked) && ranked[j].Score == ranked[i].Score {
+			j++
+		}
+		distinct++
+		position := int64(i + 1)
+		if dense {
+			position = distinct
+		}
+		// i students scored more and len-j less; the j-i tied count half.
+		percentile := (float64(len(ranked)-j) + float64(j-i)/2) * 100 / total
+		for k := i; k < j; k++ {
+			ranked[k].Rank = position
+			ranked[k].Percentile = percentile
+		}
+		i = j
+	}
+}
+
+// percentile computes where student stands in their class, school and the
+// district.
+func (s *service) percentile(student *models.Student) (*models.Percentile, error) {
+	return marksPercentile(s.repo, student)
+}
+
+// marksPercentile places the student's marks among the marks of the
+// students of their class, their school and the district.
+func marksPercentile(repo repository.Repository, student *models.Student) (*models.Percentile, error) {
+	district, err := repo.GetMarksPercentile(repository.StudentScope{}, student.Marks)
+	if err != nil {
+		return nil, err
+	}
+	percentile := &models.Percentile{District: district}
+	if student.ClassID == 0 {
+		return percentile, nil
+	}
+	class, err := repo.GetMarksPercentile(repository.StudentScope{ClassID: student.ClassID}, student.Marks)
+	if err != nil {
+		return nil, err
+	}
+	percentile.Class = &class
+
+	classRecord, err := repo.GetClassByID(student.ClassID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// A student in a deleted class still has a district standing.
+		return percentile, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if classRecord.SchoolID == 0 {
+		return percentile, nil
+	}
+	school, err := repo.GetMarksPercentile(repository.StudentScope{SchoolID: classRecord.SchoolID}, student.Marks)
+	if err != nil {
+		return nil, err
+	}
+	percentile.School = &school
+	return percentile, nil
+}
@@ -0,0 +1,141 @@
+package services_test
+
+import (
+	"errors"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newRankingService ranks a school with two classes. Students 100 and 101
+// are in class 10, 102 in class 20; term 4 is over and term 5 is active.
+// Term 6 is another school's.
+func newRankingService(t *testing.T) services.RankingService {
+	t.Helper()
+	db := openTestDB(t)
+	school := models.School{Name: "North"}
+	school.ID = 1
+	rows := []interface{}{&school}
+	for _, id := range []uint{10, 20} {
+		class := models.Class{SchoolID: 1}
+		class.ID = id
+		rows = append(rows, &class)
+	}
+	for _, s := range []struct {
+		id, classID uint
+		marks       int
+	}{{100, 10, 50}, {101, 10, 90}, {102, 20, 70}} {
+		student := models.Student{ClassID: s.classID, Marks: s.marks}
+		student.ID = s.id
+		rows = append(rows, &student)
+	}
+	for _, term := range []struct {
+		id, schoolID uint
+		active       bool
+	}{{4, 1, false}, {5, 1, true}, {6, 2, true}} {
+		record := models.Term{SchoolID: term.schoolID, Active: term.active}
+		record.ID = term.id
+		rows = append(rows, &record)
+	}
+	rows = append(rows,
+		&models.Enrollment{StudentID: 100, TermID: 4, ClassID: 10, Marks: 80},
+		&models.Enrollment{StudentID: 101, TermID: 4, ClassID: 10, Marks: 60},
+		&models.Enrollment{StudentID: 102, TermID: 4, ClassID: 20, Marks: 80},
+	)
+	for _, e := range []struct {
+		id, classID, termID uint
+		status              string
+	}{{1, 10, 5, models.MarksPublished}, {2, 20, 5, models.MarksDraft}, {3, 10, 4, models.MarksPublished}} {
+		exam := models.Exam{ClassID: e.classID, TermID: e.termID, Subject: "Maths", Name: "Final", MaxMarks: 50, Weight: 100, Status: e.status}
+		exam.ID = e.id
+		rows = append(rows, &exam)
+	}
+	rows = append(rows,
+		&models.ExamResult{ExamID: 1, StudentID: 100, Marks: 45},
+		&models.ExamResult{ExamID: 1, StudentID: 101, Marks: 30},
+		&models.ExamResult{ExamID: 2, StudentID: 102, Marks: 50},
+		&models.ExamResult{ExamID: 3, StudentID: 101, Marks: 50},
+	)
+	create(t, db, rows...)
+	return services.NewRankingService(repository.NewRepository(db), repository.NewAcademicRepository(db), repository.NewExamRepository(db))
+}
+
+type standing struct {
+	StudentID uint
+	Rank      int64
+	Score     float64
+}
+
+func standings(ranked []services.RankedStudent) []standing {
+	var result []standing
+	for _, entry := range ranked {
+		result = append(result, standing{entry.Student.ID, entry.Rank, entry.Score})
+	}
+	return result
+}
+
+func TestRanking_ByTermMarks(t *testing.T) {
+	service := newRankingService(t)
+
+	ranked, total, err := service.GetSchoolRanking(1, services.RankingOptions{TermID: 4, Limit: 10})
+
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []standing{{100, 1, 80}, {102, 1, 80}, {101, 3, 60}}, standings(ranked))
+	assert.InDelta(t, 100.0/6, ranked[2].Percentile, 1e-9)
+}
+
+func TestRanking_BySubjectInTermInEffect(t *testing.T) {
+	service := newRankingService(t)
+
+	ranked, total, err := service.GetSchoolRanking(1, services.RankingOptions{Subject: "Maths", Limit: 10})
+
+	// Class 20's Maths marks are unpublished, so student 102 is not ranked.
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, []standing{{100, 1, 90}, {101, 2, 60}}, standings(ranked))
+	assert.Equal(t, 75.0, ranked[0].Percentile)
+}
+
+func TestRanking_BySubjectInTerm(t *testing.T) {
+	service := newRankingService(t)
+
+	ranked, _, err := service.GetClassRanking(10, services.RankingOptions{Subject: "Maths", TermID: 4, Method: services.RankingDense, Limit: 10})
+
+	// Student 100 has no result in the graded exam and scores zero.
+	assert.Nil(t, err)
+	assert.Equal(t, []standing{{101, 1, 100}, {100, 2, 0}}, standings(ranked))
+}
+
+func TestRanking_TermOfAnotherSchool(t *testing.T) {
+	service := newRankingService(t)
+
+	var validation *services.ValidationError
+	_, _, err := service.GetClassRanking(10, services.RankingOptions{TermID: 6, Limit: 10})
+	assert.True(t, errors.As(err, &validation))
+
+	_, _, err = service.GetDistrictRanking(services.RankingOptions{TermID: 9, Limit: 10})
+	assert.True(t, errors.As(err, &validation))
+}
+
+func TestRanking_StudentPercentileInTermAndSubject(t *testing.T) {
+	service := newRankingService(t)
+
+	percentile, err := service.GetStudentPercentile(101, services.RankingOptions{TermID: 4})
+	assert.Nil(t, err)
+	assert.Equal(t, 25.0, *percentile.Class)
+	assert.InDelta(t, 100.0/6, *percentile.School, 1e-9)
+	assert.InDelta(t, 100.0/6, percentile.District, 1e-9)
+
+	percentile, err = service.GetStudentPercentile(100, services.RankingOptions{Subject: "Maths"})
+	assert.Nil(t, err)
+	assert.Equal(t, 75.0, *percentile.Class)
+
+	// Class 20's Maths marks are unpublished, so student 102 has no score.
+	var validation *services.ValidationError
+	_, err = service.GetStudentPercentile(102, services.RankingOptions{Subject: "Maths"})
+	assert.True(t, errors.As(err, &validation))
+}
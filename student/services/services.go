@@ -3,11 +3,14 @@ package services
 import (
 	"stu/models"
 	"stu/repository"
+	"stu/search"
 )
 
 type SchoolService interface {
 	GetAllSchools() ([]models.School, error)
+	ListSchools(offset, limit int) ([]models.School, int64, error)
 	GetSchoolByID(id uint) (*models.School, error)
+	GetSchoolsByIDs(ids []uint) ([]models.School, error)
 	CreateSchool(school *models.School) error
 	UpdateSchool(school *models.School) error
 	DeleteSchool(id uint) error
@@ -15,7 +18,10 @@ type SchoolService interface {
 
 type ClassService interface {
 	GetAllClasses() ([]models.Class, error)
+	ListClasses(offset, limit int) ([]models.Class, int64, error)
 	GetClassByID(id uint) (*models.Class, error)
+	GetClassesByIDs(ids []uint) ([]models.Class, error)
+	GetClassesBySchoolIDs(schoolIDs []uint) ([]models.Class, error)
 	CreateClass(class *models.Class) error
 	UpdateClass(class *models.Class) error
 	DeleteClass(id uint) error
@@ -23,10 +29,21 @@ type ClassService interface {
 
 type StudentService interface {
 	GetAllStudents() ([]models.Student, error)
+	ListStudents(offset, limit int) ([]models.Student, int64, error)
 	GetStudentByID(id uint) (*models.Student, error)
+	GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error)
 	CreateStudent(student *models.Student) error
 	UpdateStudent(student *models.Student) error
 	DeleteStudent(id uint) error
+	SearchStudents(query string, limit int) ([]StudentMatch, error)
+}
+
+// StudentMatch is a student search result. Highlights holds the matching
+// fields, HTML-escaped with the matched words wrapped in <mark> tags.
+type StudentMatch struct {
+	Student    models.Student    `json:"student"`
+	Score      float64           `json:"score"`
+	Highlights map[string]string `json:"highlights"`
 }
 
 type service struct {
@@ -45,10 +62,18 @@ func (s *service) GetAllSchools() ([]models.School, error) {
 	return s.repo.GetSchools()
 }
 
+func (s *service) ListSchools(offset, limit int) ([]models.School, int64, error) {
+	return s.repo.GetSchoolsPage(offset, limit)
+}
+
 func (s *service) GetSchoolByID(id uint) (*models.School, error) {
 	return s.repo.GetSchoolByID(id)
 }
 
+func (s *service) GetSchoolsByIDs(ids []uint) ([]models.School, error) {
+	return s.repo.GetSchoolsByIDs(ids)
+}
+
 func (s *service) CreateSchool(school *models.School) error {
 	return s.repo.CreateSchool(school)
 }
@@ -64,21 +89,93 @@ func (s *service) DeleteSchool(id uint) error {
 // Class methods
 
 func (s *service) GetAllClasses() ([]models.Class, error) {
-	return s.repo.GetClasses()
+	classes, err := s.repo.GetClasses()
+	if err != nil {
+		return nil, err
+	}
+	return classes, s.addOccupancy(classes)
+}
+
+func (s *service) ListClasses(offset, limit int) ([]models.Class, int64, error) {
+	classes, total, err := s.repo.GetClassesPage(offset, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	return classes, total, s.addOccupancy(classes)
 }
 
 func (s *service) GetClassByID(id uint) (*models.Class, error) {
-	return s.repo.GetClassByID(id)
+	class, err := s.repo.GetClassByID(id)
+	if err != nil {
+		return nil, err
+	}
+	classes := []models.Class{*class}
+	if err := s.addOccupancy(classes); err != nil {
+		return nil, err
+	}
+	return &classes[0], nil
+}
+
+// addOccupancy sets the occupancy of the classes.
+func (s *service) addOccupancy(classes []models.Class) error {
+	ids := make([]uint, len(classes))
+	for i, class := range classes {
+		ids[i] = class.ID
+	}
+	occupancy, err := s.repo.GetClassOccupancy(ids)
+	if err != nil {
+		return err
+	}
+	for i := range classes {
+		if o, ok := occupancy[classes[i].ID]; ok {
+			classes[i].Occupancy = &o
+		}
+	}
+	return nil
+}
+
+func (s *service) GetClassesByIDs(ids []uint) ([]models.Class, error) {
+	return s.repo.GetClassesByIDs(ids)
+}
+
+func (s *service) GetClassesBySchoolIDs(schoolIDs []uint) ([]models.Class, error) {
+	return s.repo.GetClassesBySchoolIDs(schoolIDs)
 }
 
 func (s *service) CreateClass(class *models.Class) error {
+	if err := validateClass(s.repo, class, false); err != nil {
+		return err
+	}
 	return s.repo.CreateClass(class)
 }
 
+// UpdateClass refuses to lower the capacity below the class's current
+// number of students.
 func (s *service) UpdateClass(class *models.Class) error {
+	if err := validateClass(s.repo, class, true); err != nil {
+		return err
+	}
 	return s.repo.UpdateClass(class)
 }
 
+// validateClass checks the class's capacity before it is created or, if
+// existing is set, updated. Every write of a class goes through it.
+func validateClass(repo repository.Repository, class *models.Class, existing bool) error {
+	if class.Capacity < 0 {
+		return invalid("capacity must not be negative")
+	}
+	if existing && class.Capacity > 0 {
+		occupancy, err := repo.GetClassOccupancy([]uint{class.ID})
+		if err != nil {
+			return err
+		}
+		if enrolled := occupancy[class.ID].Enrolled; enrolled > int64(class.Capacity) {
+			return invalid("class %d already has %d students", class.ID, enrolled)
+		}
+	}
+	return nil
+}
+
 func (s *service) DeleteClass(id uint) error {
 	return s.repo.DeleteClass(id)
 }
@@ -89,8 +186,23 @@ func (s *service) GetAllStudents() ([]models.Student, error) {
 	return s.repo.GetStudents()
 }
 
+func (s *service) ListStudents(offset, limit int) ([]models.Student, int64, error) {
+	return s.repo.GetStudentsPage(offset, limit)
+}
+
 func (s *service) GetStudentByID(id uint) (*models.Student, error) {
-	return s.repo.GetStudentByID(id)
+	student, err := s.repo.GetStudentByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if student.Percentile, err = s.percentile(student); err != nil {
+		return nil, err
+	}
+	return student, nil
+}
+
+func (s *service) GetStudentsByClassIDs(classIDs []uint) ([]models.Student, error) {
+	return s.repo.GetStudentsByClassIDs(classIDs)
 }
 
 func (s *service) CreateStudent(student *models.Student) error {
@@ -104,3 +216,25 @@ func (s *service) UpdateStudent(student *models.Student) error {
 func (s *service) DeleteStudent(id uint) error {
 	return s.repo.DeleteStudent(id)
 }
+
+func (s *service) SearchStudents(query string, limit int) ([]StudentMatch, error) {
+	terms := search.Terms(query)
+	if len(terms) == 0 {
+		return nil, invalid("search query must contain letters or digits")
+	}
+	scored, err := s.repo.SearchStudents(terms, limit)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]StudentMatch, len(scored))
+	for i, result := range scored {
+		highlights := map[string]string{}
+		for _, field := range search.StudentFields(result.Student) {
+			if highlighted, ok := search.Highlight(field.Text, terms); ok {
+				highlights[field.Name] = highlighted
+			}
+		}
+		matches[i] = StudentMatch{Student: result.Student, Score: result.Score, Highlights: highlights}
+	}
+	return matches, nil
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"path/filepath"
+	"stu/models"
+	"stu/repository"
+	"stu/services"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB returns an empty SQLite database with the tables the services
+// under test use.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "services.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	assert.Nil(t, err)
+	assert.Nil(t, db.AutoMigrate(&models.School{}, &models.Class{}, &models.Student{}, &models.Term{},
+		&models.Enrollment{}, &models.Exam{}, &models.ExamResult{}, &models.ExamStatusChange{},
+		&models.Appeal{}, &models.MarkChange{}, &models.WaitlistEntry{}, &models.OutboxEvent{},
+		&models.AcademicYear{}, &models.PromotionPlan{}, &models.PromotionEntry{},
+		&models.FeeItem{}, &models.Discount{}))
+	return db
+}
+
+// create inserts the rows as they are, bypassing the repositories.
+func create(t *testing.T, db *gorm.DB, rows ...interface{}) {
+	t.Helper()
+	for _, row := range rows {
+		assert.Nil(t, db.Create(row).Error)
+	}
+}
+
+func TestUpdateStudent_UnknownStudentNeedsASeat(t *testing.T) {
+	db := openTestDB(t)
+	class := models.Class{SchoolID: 1, Capacity: 1}
+	class.ID = 10
+	seated := models.Student{ClassID: 10}
+	seated.ID = 100
+	create(t, db, &class, &seated)
+	students := services.NewService(repository.NewRepository(db))
+
+	unknown := models.Student{Name: "Unknown", ClassID: 10}
+	unknown.ID = 101
+	assert.ErrorIs(t, students.UpdateStudent(&unknown), repository.ErrClassFull)
+
+	var count int64
+	assert.Nil(t, db.Model(&models.Student{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
@@ -0,0 +1,152 @@
+package services
+
+import (
+	"stu/repository"
+)
+
+const (
+	DefaultPassMark   = 40
+	DefaultBucketSize = 10
+	maxHistogramSize  = 1000
+)
+
+// StatsOptions configure the marks statistics. Buckets, when set, are the
+// ascending lower edges of the histogram buckets; otherwise buckets are
+// BucketSize marks wide.
+type StatsOptions struct {
+	PassMark   int
+	BucketSize int
+	Buckets    []int
+}
+
+// HistogramBucket counts marks in [From, To). A nil bound is open.
+type HistogramBucket struct {
+	From  *int  `json:"from"`
+	To    *int  `json:"to"`
+	Count int64 `json:"count"`
+}
+
+type MarksStats struct {
+	Count     int64             `json:"count"`
+	Mean      float64           `json:"mean"`
+	Median    float64           `json:"median"`
+	StdDev    float64           `json:"std_dev"`
+	Min       int               `json:"min"`
+	Max       int               `json:"max"`
+	Q1        float64           `json:"q1"`
+	Q3        float64           `json:"q3"`
+	PassMark  int               `json:"pass_mark"`
+	PassRate  float64           `json:"pass_rate"`
+	Histogram []HistogramBucket `json:"histogram"`
+}
+
+type StatsService interface {
+	GetClassStats(id uint, opts StatsOptions) (*MarksStats, error)
+	GetSchoolStats(id uint, opts StatsOptions) (*MarksStats, error)
+}
+
+func (s *service) GetClassStats(id uint, opts StatsOptions) (*MarksStats, error) {
+	if _, err := s.repo.GetClassByID(id); err != nil {
+		return nil, err
+	}
+	return s.marksStats(repository.StudentScope{ClassID: id}, opts)
+}
+
+func (s *service) GetSchoolStats(id uint, opts StatsOptions) (*MarksStats, error) {
+	if _, err := s.repo.GetSchoolByID(id); err != nil {
+		return nil, err
+	}
+	return s.marksStats(repository.StudentScope{SchoolID: id}, opts)
+}
+
+func (s *service) marksStats(scope repository.StudentScope, opts StatsOptions) (*MarksStats, error) {
+	if opts.BucketSize < 0 {
+		return nil, invalid("bucket size must be positive")
+	}
+	if opts.BucketSize == 0 {
+		opts.BucketSize = DefaultBucketSize
+	}
+	for i := 1; i < len(opts.Buckets); i++ {
+		if opts.Buckets[i] <= opts.Buckets[i-1] {
+			return nil, invalid("bucket edges must be ascending")
+		}
+	}
+	if len(opts.Buckets) > maxHistogramSize {
+		return nil, invalid("at most %d buckets are allowed", maxHistogramSize)
+	}
+
+	summary, err := s.repo.GetMarksSummary(scope, opts.PassMark)
+	if err != nil {
+		return nil, err
+	}
+	result := &MarksStats{
+		Count:     summary.Count,
+		Mean:      summary.Mean,
+		Median:    summary.Median,
+		StdDev:    summary.StdDev,
+		Min:       summary.Min,
+		Max:       summary.Max,
+		Q1:        summary.Q1,
+		Q3:        summary.Q3,
+		PassMark:  opts.PassMark,
+		Histogram: []HistogramBucket{},
+	}
+	if summary.Count == 0 {
+		return result, nil
+	}
+	result.PassRate = float64(summary.Passed) / float64(summary.Count)
+
+	edges, closed := opts.Buckets, false
+	if len(edges) == 0 {
+		first := floorDiv(summary.Min, opts.BucketSize) * opts.BucketSize
+		if (summary.Max-first)/opts.BucketSize >= maxHistogramSize {
+			return nil, invalid("bucket size %d yields more than %d buckets", opts.BucketSize, maxHistogramSize)
+		}
+		edges, closed = sizedEdges(first, summary.Max, opts.BucketSize), true
+	}
+	counts, err := s.repo.GetMarksHistogram(scope, edges)
+	if err != nil {
+		return nil, err
+	}
+	result.Histogram = histogramBuckets(edges, counts, closed, opts.BucketSize)
+	return result, nil
+}
+
+// sizedEdges returns the edges of size-wide buckets from first that cover
+// marks up to max.
+func sizedEdges(first, max, size int) []int {
+	var edges []int
+	for edge := first; edge <= max; edge += size {
+		edges = append(edges, edge)
+	}
+	return edges
+}
+
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// histogramBuckets labels the counts of stats.Histogram. The bucket below
+// the first edge is left out when empty; the last bucket is open unless
+// closed, in which case it is size wide.
+func histogramBuckets(edges []int, counts []int64, closed bool, size int) []HistogramBucket {
+	var buckets []HistogramBucket
+	if counts[0] > 0 {
+		buckets = append(buckets, HistogramBucket{To: &edges[0], Count: counts[0]})
+	}
+	for i := range edges {
+		bucket := HistogramBucket{From: &edges[i], Count: counts[i+1]}
+		if i+1 < len(edges) {
+			bucket.To = &edges[i+1]
+		} else if closed {
+			to := edges[i] + size
+			bucket.To = &to
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets
+}
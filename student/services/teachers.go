@@ -0,0 +1,159 @@
+package services
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+type TeacherService interface {
+	GetAllTeachers() ([]models.Teacher, error)
+	ListTeachers(offset, limit int) ([]models.Teacher, int64, error)
+	GetTeacherByID(id uint) (*models.Teacher, error)
+	CreateTeacher(teacher *models.Teacher) error
+	UpdateTeacher(teacher *models.Teacher) error
+	DeleteTeacher(id uint) error
+
+	// GetTeacherClasses returns the teacher's assignments with their
+	// classes, the basis of a teacher's workload.
+	GetTeacherClasses(teacherID uint) ([]models.TeacherAssignment, error)
+	GetClassTeachers(classID uint) ([]models.TeacherAssignment, error)
+	AssignTeacher(assignment *models.TeacherAssignment) error
+	UnassignTeacher(teacherID, assignmentID uint) error
+}
+
+type teacherService struct {
+	repo repository.TeacherRepository
+	core repository.Repository
+}
+
+func NewTeacherService(repo repository.TeacherRepository, core repository.Repository) *teacherService {
+	return &teacherService{
+		repo: repo,
+		core: core,
+	}
+}
+
+func (s *teacherService) GetAllTeachers() ([]models.Teacher, error) {
+	return s.repo.GetTeachers()
+}
+
+func (s *teacherService) ListTeachers(offset, limit int) ([]models.Teacher, int64, error) {
+	return s.repo.GetTeachersPage(offset, limit)
+}
+
+func (s *teacherService) GetTeacherByID(id uint) (*models.Teacher, error) {
+	return s.repo.GetTeacherByID(id)
+}
+
+func (s *teacherService) CreateTeacher(teacher *models.Teacher) error {
+	if err := validateTeacher(teacher); err != nil {
+		return err
+	}
+	return s.repo.CreateTeacher(teacher)
+}
+
+func (s *teacherService) UpdateTeacher(teacher *models.Teacher) error {
+	if err := validateTeacher(teacher); err != nil {
+		return err
+	}
+	return s.repo.UpdateTeacher(teacher)
+}
+
+func (s *teacherService) DeleteTeacher(id uint) error {
+	return s.repo.DeleteTeacher(id)
+}
+
+func (s *teacherService) GetTeacherClasses(teacherID uint) ([]models.TeacherAssignment, error) {
+	if _, err := s.repo.GetTeacherByID(teacherID); err != nil {
+		return nil, err
+	}
+	assignments, err := s.repo.GetAssignmentsByTeacherID(teacherID)
+	if err != nil {
+		return nil, err
+	}
+	classIDs := make([]uint, len(assignments))
+	for i, assignment := range assignments {
+		classIDs[i] = assignment.ClassID
+	}
+	classes, err := s.core.GetClassesByIDs(classIDs)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.Class, len(classes))
+	for i := range classes {
+		byID[classes[i].ID] = &classes[i]
+	}
+	for i := range assignments {
+		assignments[i].Class = byID[assignments[i].ClassID]
+	}
+	return assignments, nil
+}
+
+func (s *teacherService) GetClassTeachers(classID uint) ([]models.TeacherAssignment, error) {
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAssignmentsByClassID(classID)
+}
+
+func (s *teacherService) AssignTeacher(assignment *models.TeacherAssignment) error {
+	assignment.Subject = strings.TrimSpace(assignment.Subject)
+	switch assignment.Role {
+	case models.RoleHomeroom:
+		if assignment.Subject != "" {
+			return invalid("homeroom assignments have no subject")
+		}
+	case models.RoleSubject:
+		if assignment.Subject == "" {
+			return invalid("subject is required for subject teachers")
+		}
+	default:
+		return invalid("role must be %q or %q", models.RoleHomeroom, models.RoleSubject)
+	}
+	if _, err := s.repo.GetTeacherByID(assignment.TeacherID); err != nil {
+		return err
+	}
+	if _, err := s.core.GetClassByID(assignment.ClassID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return invalid("class %d does not exist", assignment.ClassID)
+		}
+		return err
+	}
+
+	existing, err := s.repo.GetAssignmentsByClassID(assignment.ClassID)
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.Role == models.RoleHomeroom && assignment.Role == models.RoleHomeroom {
+			return invalid("class %d already has a homeroom teacher", assignment.ClassID)
+		}
+		if other.TeacherID == assignment.TeacherID && other.Role == assignment.Role && other.Subject == assignment.Subject {
+			return invalid("teacher %d already has this assignment", assignment.TeacherID)
+		}
+	}
+	assignment.ID = 0
+	return s.repo.CreateTeacherAssignment(assignment)
+}
+
+func (s *teacherService) UnassignTeacher(teacherID, assignmentID uint) error {
+	return s.repo.DeleteTeacherAssignment(teacherID, assignmentID)
+}
+
+func validateTeacher(teacher *models.Teacher) error {
+	teacher.Name = strings.TrimSpace(teacher.Name)
+	if teacher.Name == "" {
+		return invalid("name is required")
+	}
+	if teacher.Email != "" {
+		if _, err := mail.ParseAddress(teacher.Email); err != nil {
+			return invalid("email %q is not a valid address", teacher.Email)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"stu/models"
+	"stu/repository"
+
+	"gorm.io/gorm"
+)
+
+// WaitlistService queues students for seats in full classes. Seats freed
+// by a student leaving a class, or by a larger capacity, go to the
+// class's waitlist in order.
+type WaitlistService interface {
+	GetWaitlist(classID uint) ([]models.WaitlistEntry, error)
+	// AddToWaitlist queues a student for a full class. Students join
+	// classes with free seats directly instead.
+	AddToWaitlist(classID, studentID uint) (*models.WaitlistEntry, error)
+	RemoveFromWaitlist(classID, studentID uint) error
+}
+
+type waitlistService struct {
+	repo repository.WaitlistRepository
+	core repository.Repository
+}
+
+func NewWaitlistService(repo repository.WaitlistRepository, core repository.Repository) *waitlistService {
+	return &waitlistService{
+		repo: repo,
+		core: core,
+	}
+}
+
+func (s *waitlistService) GetWaitlist(classID uint) ([]models.WaitlistEntry, error) {
+	if _, err := s.core.GetClassByID(classID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetWaitlist(classID)
+}
+
+func (s *waitlistService) AddToWaitlist(classID, studentID uint) (*models.WaitlistEntry, error) {
+	class, err := s.core.GetClassByID(classID)
+	if err != nil {
+		return nil, err
+	}
+	student, err := s.core.GetStudentByID(studentID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid("student %d does not exist", studentID)
+		}
+		return nil, err
+	}
+	if student.ClassID == class.ID {
+		return nil, invalid("student %d is already in class %d", student.ID, class.ID)
+	}
+	if _, err := s.repo.GetWaitlistEntry(class.ID, student.ID); err == nil {
+		return nil, invalid("student %d is already waiting for class %d", student.ID, class.ID)
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	occupancy, err := s.core.GetClassOccupancy([]uint{class.ID})
+	if err != nil {
+		return nil, err
+	}
+	if o := occupancy[class.ID]; o.Available == nil || (*o.Available > 0 && o.Waitlisted == 0) {
+		return nil, invalid("class %d has free seats; move the student into it", class.ID)
+	}
+	entry := &models.WaitlistEntry{ClassID: class.ID, StudentID: student.ID}
+	if err := s.repo.AddToWaitlist(entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (s *waitlistService) RemoveFromWaitlist(classID, studentID uint) error {
+	return s.repo.RemoveFromWaitlist(classID, studentID)
+}
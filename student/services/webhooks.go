@@ -0,0 +1,143 @@
+package services
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"stu/events"
+	"stu/models"
+	"stu/repository"
+	"time"
+)
+
+type WebhookService interface {
+	GetAllWebhooks() ([]models.WebhookSubscription, error)
+	GetWebhookByID(id uint) (*models.WebhookSubscription, error)
+	CreateWebhook(subscription *models.WebhookSubscription) error
+	UpdateWebhook(subscription *models.WebhookSubscription) error
+	DeleteWebhook(id uint) error
+
+	GetWebhookDeliveries(status string) ([]models.WebhookDelivery, error)
+	ReplayWebhookDelivery(id uint) (*models.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo repository.WebhookRepository
+}
+
+func NewWebhookService(repo repository.WebhookRepository) *webhookService {
+	return &webhookService{
+		repo: repo,
+	}
+}
+
+// Secrets are only returned when a subscription is created.
+
+func (s *webhookService) GetAllWebhooks() ([]models.WebhookSubscription, error) {
+	subscriptions, err := s.repo.GetWebhookSubscriptions()
+	if err != nil {
+		return nil, err
+	}
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions, nil
+}
+
+func (s *webhookService) GetWebhookByID(id uint) (*models.WebhookSubscription, error) {
+	subscription, err := s.repo.GetWebhookSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// CreateWebhook validates the subscription and generates a signing secret
+// when none is given.
+func (s *webhookService) CreateWebhook(subscription *models.WebhookSubscription) error {
+	if err := validateWebhook(subscription); err != nil {
+		return err
+	}
+	if subscription.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return err
+		}
+		subscription.Secret = secret
+	}
+	subscription.Active = true
+	return s.repo.CreateWebhookSubscription(subscription)
+}
+
+// UpdateWebhook keeps the stored secret unless a new one is given.
+func (s *webhookService) UpdateWebhook(subscription *models.WebhookSubscription) error {
+	if err := validateWebhook(subscription); err != nil {
+		return err
+	}
+	existing, err := s.repo.GetWebhookSubscriptionByID(subscription.ID)
+	if err != nil {
+		return err
+	}
+	if subscription.Secret == "" {
+		subscription.Secret = existing.Secret
+	}
+	subscription.CreatedAt = existing.CreatedAt
+	if err := s.repo.UpdateWebhookSubscription(subscription); err != nil {
+		return err
+	}
+	subscription.Secret = ""
+	return nil
+}
+
+func (s *webhookService) DeleteWebhook(id uint) error {
+	return s.repo.DeleteWebhookSubscription(id)
+}
+
+func (s *webhookService) GetWebhookDeliveries(status string) ([]models.WebhookDelivery, error) {
+	switch status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead:
+	default:
+		return nil, invalid("unknown delivery status %q", status)
+	}
+	return s.repo.GetWebhookDeliveries(status)
+}
+
+// ReplayWebhookDelivery puts a delivery back on the queue for immediate
+// delivery with a fresh retry budget.
+func (s *webhookService) ReplayWebhookDelivery(id uint) (*models.WebhookDelivery, error) {
+	delivery, err := s.repo.GetWebhookDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Status = models.DeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	delivery.LastError = ""
+	if err := s.repo.UpdateWebhookDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func validateWebhook(subscription *models.WebhookSubscription) error {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return invalid("url must be an absolute http or https URL")
+	}
+	for _, eventType := range subscription.Events {
+		if !slices.Contains(events.Types, eventType) {
+			return invalid("unknown event type %q", eventType)
+		}
+	}
+	return nil
+}
+
+func newSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
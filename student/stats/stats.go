@@ -0,0 +1,80 @@
+// Package stats computes descriptive statistics of student marks. The
+// repository computes them in SQL on Postgres; these functions give the
+// same results for other databases.
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Summary describes a set of marks. Quartiles are interpolated like
+// Postgres percentile_cont; the standard deviation is the population one.
+type Summary struct {
+	Count  int64
+	Mean   float64
+	StdDev float64
+	Min    int
+	Max    int
+	Q1     float64
+	Median float64
+	Q3     float64
+	// Passed counts marks at or above the pass mark.
+	Passed int64
+}
+
+func Summarize(marks []int, passMark int) Summary {
+	if len(marks) == 0 {
+		return Summary{}
+	}
+	sorted := append([]int(nil), marks...)
+	sort.Ints(sorted)
+
+	summary := Summary{
+		Count:  int64(len(sorted)),
+		Min:    sorted[0],
+		Max:    sorted[len(sorted)-1],
+		Q1:     Percentile(sorted, 0.25),
+		Median: Percentile(sorted, 0.5),
+		Q3:     Percentile(sorted, 0.75),
+	}
+	sum := 0.0
+	for _, mark := range sorted {
+		sum += float64(mark)
+		if mark >= passMark {
+			summary.Passed++
+		}
+	}
+	summary.Mean = sum / float64(len(sorted))
+	squares := 0.0
+	for _, mark := range sorted {
+		squares += (float64(mark) - summary.Mean) * (float64(mark) - summary.Mean)
+	}
+	summary.StdDev = math.Sqrt(squares / float64(len(sorted)))
+	return summary
+}
+
+// Percentile returns the p-th percentile (0 <= p <= 1) of sorted marks,
+// interpolating linearly between the two nearest marks.
+func Percentile(sorted []int, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := p * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	fraction := position - float64(lower)
+	return float64(sorted[lower]) + fraction*float64(sorted[upper]-sorted[lower])
+}
+
+// Histogram counts marks into the buckets delimited by ascending edges,
+// numbered like Postgres width_bucket: bucket 0 holds marks below the
+// first edge, bucket i marks in [edges[i-1], edges[i]) and the last
+// bucket marks at or above the last edge.
+func Histogram(marks []int, edges []int) []int64 {
+	counts := make([]int64, len(edges)+1)
+	for _, mark := range marks {
+		counts[sort.Search(len(edges), func(i int) bool { return edges[i] > mark })]++
+	}
+	return counts
+}
@@ -0,0 +1,36 @@
+package stats_test
+
+import (
+	"stu/stats"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarize(t *testing.T) {
+	summary := stats.Summarize([]int{90, 35, 60, 40, 75}, 40)
+
+	assert.Equal(t, int64(5), summary.Count)
+	assert.Equal(t, 60.0, summary.Mean)
+	assert.InDelta(t, 20.736, summary.StdDev, 0.001)
+	assert.Equal(t, 35, summary.Min)
+	assert.Equal(t, 90, summary.Max)
+	assert.Equal(t, 40.0, summary.Q1)
+	assert.Equal(t, 60.0, summary.Median)
+	assert.Equal(t, 75.0, summary.Q3)
+	assert.Equal(t, int64(4), summary.Passed)
+
+	assert.Equal(t, stats.Summary{}, stats.Summarize(nil, 40))
+}
+
+func TestPercentile_Interpolates(t *testing.T) {
+	sorted := []int{10, 20, 30, 40}
+	assert.Equal(t, 25.0, stats.Percentile(sorted, 0.5))
+	assert.Equal(t, 17.5, stats.Percentile(sorted, 0.25))
+	assert.Equal(t, 40.0, stats.Percentile(sorted, 1))
+}
+
+func TestHistogram(t *testing.T) {
+	counts := stats.Histogram([]int{5, 10, 19, 20, 35, 99}, []int{10, 20, 30})
+	assert.Equal(t, []int64{1, 2, 1, 2}, counts)
+}
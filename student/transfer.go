@@ -0,0 +1,143 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"stu/bulk"
+)
+
+type transferFlags struct {
+	entity string
+	format bulk.Format
+	path   string
+}
+
+func parseTransferFlags(name string, args []string) (*transferFlags, error) {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	entity := flags.String("entity", "students", "schools, classes or students")
+	format := flags.String("format", "csv", "csv or json")
+	path := flags.String("file", "-", "file to read or write; - for stdin/stdout")
+	flags.Parse(args)
+
+	f, err := bulk.ParseFormat(*format)
+	if err != nil {
+		return nil, err
+	}
+	switch *entity {
+	case "schools", "classes", "students":
+	default:
+		return nil, fmt.Errorf("unknown entity %q", *entity)
+	}
+	return &transferFlags{entity: *entity, format: f, path: *path}, nil
+}
+
+// runImport creates rows without an ID and upserts rows that carry one.
+func runImport(app *App, args []string) error {
+	opts, err := parseTransferFlags("import", args)
+	if err != nil {
+		return err
+	}
+	var in io.Reader = os.Stdin
+	if opts.path != "-" {
+		file, err := os.Open(opts.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		in = file
+	}
+
+	count := 0
+	switch opts.entity {
+	case "schools":
+		schools, err := bulk.ImportSchools(in, opts.format)
+		if err != nil {
+			return err
+		}
+		for i := range schools {
+			if schools[i].ID == 0 {
+				err = app.SchoolService.CreateSchool(&schools[i])
+			} else {
+				err = app.SchoolService.UpdateSchool(&schools[i])
+			}
+			if err != nil {
+				return fmt.Errorf("school %q: %w", schools[i].Name, err)
+			}
+		}
+		count = len(schools)
+	case "classes":
+		classes, err := bulk.ImportClasses(in, opts.format)
+		if err != nil {
+			return err
+		}
+		for i := range classes {
+			if classes[i].ID == 0 {
+				err = app.ClassService.CreateClass(&classes[i])
+			} else {
+				err = app.ClassService.UpdateClass(&classes[i])
+			}
+			if err != nil {
+				return fmt.Errorf("class %q: %w", classes[i].ClassName, err)
+			}
+		}
+		count = len(classes)
+	case "students":
+		students, err := bulk.ImportStudents(in, opts.format)
+		if err != nil {
+			return err
+		}
+		for i := range students {
+			if students[i].ID == 0 {
+				err = app.StudentService.CreateStudent(&students[i])
+			} else {
+				err = app.StudentService.UpdateStudent(&students[i])
+			}
+			if err != nil {
+				return fmt.Errorf("student %q: %w", students[i].Name, err)
+			}
+		}
+		count = len(students)
+	}
+	log.Printf("Imported %d %s", count, opts.entity)
+	return nil
+}
+
+func runExport(app *App, args []string) error {
+	opts, err := parseTransferFlags("export", args)
+	if err != nil {
+		return err
+	}
+	var out io.Writer = os.Stdout
+	if opts.path != "-" {
+		file, err := os.Create(opts.path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	switch opts.entity {
+	case "schools":
+		schools, err := app.SchoolService.GetAllSchools()
+		if err != nil {
+			return err
+		}
+		return bulk.ExportSchools(out, opts.format, schools)
+	case "classes":
+		classes, err := app.ClassService.GetAllClasses()
+		if err != nil {
+			return err
+		}
+		return bulk.ExportClasses(out, opts.format, classes)
+	default:
+		students, err := app.StudentService.GetAllStudents()
+		if err != nil {
+			return err
+		}
+		return bulk.ExportStudents(out, opts.format, students)
+	}
+}
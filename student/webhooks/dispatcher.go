@@ -0,0 +1,224 @@
+// Package webhooks delivers domain events to the URLs integrators register,
+// through a persistent queue with exponential-backoff retries.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"stu/events"
+	"stu/models"
+	"stu/repository"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Options struct {
+	// MaxAttempts is how many times a delivery is tried before it is
+	// moved to the dead-letter list.
+	MaxAttempts int
+	// BaseBackoff is the wait after the first failure; it doubles after
+	// every further failure up to MaxBackoff.
+	BaseBackoff time.Duration
+	MaxBackoff  time.Duration
+	// PollInterval is how often Run looks for due deliveries.
+	PollInterval time.Duration
+	BatchSize    int
+	Timeout      time.Duration
+}
+
+func (o Options) withDefaults() Options {
+	if o.MaxAttempts <= 0 {
+		o.MaxAttempts = 8
+	}
+	if o.BaseBackoff <= 0 {
+		o.BaseBackoff = 30 * time.Second
+	}
+	if o.MaxBackoff <= 0 {
+		o.MaxBackoff = time.Hour
+	}
+	if o.PollInterval <= 0 {
+		o.PollInterval = 5 * time.Second
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = 50
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = 10 * time.Second
+	}
+	return o
+}
+
+// Dispatcher queues events for matching subscriptions when published and
+// delivers queued payloads when Run or ProcessDue is called.
+type Dispatcher struct {
+	repo   repository.WebhookRepository
+	opts   Options
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewDispatcher(repo repository.WebhookRepository, opts Options) *Dispatcher {
+	opts = opts.withDefaults()
+	return &Dispatcher{
+		repo:   repo,
+		opts:   opts,
+		client: &http.Client{Timeout: opts.Timeout},
+		now:    time.Now,
+	}
+}
+
+// Publish queues one delivery per subscription matching the event. An
+// event that is published again, after an earlier attempt failed, is only
+// queued for the subscriptions it was not queued for yet.
+func (d *Dispatcher) Publish(event events.Event) error {
+	subscriptions, err := d.repo.GetWebhookSubscriptions()
+	if err != nil {
+		return fmt.Errorf("load subscriptions for %s: %w", event.Type, err)
+	}
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", event.Type, err)
+	}
+	var eventID *uint64
+	if event.ID != 0 {
+		eventID = &event.ID
+	}
+	var deliveries []models.WebhookDelivery
+	for i := range subscriptions {
+		if !subscriptions[i].Matches(event.Type, event.SchoolID) {
+			continue
+		}
+		deliveries = append(deliveries, models.WebhookDelivery{
+			SubscriptionID: subscriptions[i].ID,
+			EventID:        eventID,
+			EventType:      event.Type,
+			Payload:        string(payload),
+			Status:         models.DeliveryPending,
+			NextAttemptAt:  d.now(),
+		})
+	}
+	if err := d.repo.CreateWebhookDeliveries(deliveries); err != nil {
+		return fmt.Errorf("queue %s: %w", event.Type, err)
+	}
+	return nil
+}
+
+// Run delivers due payloads every PollInterval until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.opts.PollInterval)
+	defer ticker.Stop()
+	for {
+		if _, err := d.ProcessDue(ctx); err != nil {
+			log.Printf("webhooks: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessDue attempts every delivery that is due and returns how many were
+// attempted. Deliveries whose subscription cannot be read are left pending
+// and claimed again once their lease expires.
+//
+// The deliveries of a batch are sent one after another, so the lease
+// covers each of them timing out, with a timeout to spare for recording
+// the outcomes. Deliveries that could not be sent within the lease anyway
+// are left to be claimed again.
+func (d *Dispatcher) ProcessDue(ctx context.Context) (int, error) {
+	claimed := d.now()
+	lease := d.opts.Timeout * time.Duration(d.opts.BatchSize+1)
+	deliveries, err := d.repo.ClaimDueWebhookDeliveries(claimed, lease, d.opts.BatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("claim deliveries: %w", err)
+	}
+	last := claimed.Add(lease - 2*d.opts.Timeout)
+	subscriptions := map[uint]*models.WebhookSubscription{}
+	for i := range deliveries {
+		if d.now().After(last) {
+			return i, nil
+		}
+		delivery := &deliveries[i]
+		subscription, ok := subscriptions[delivery.SubscriptionID]
+		if !ok {
+			subscription, err = d.repo.GetWebhookSubscriptionByID(delivery.SubscriptionID)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				return i, fmt.Errorf("get subscription %d: %w", delivery.SubscriptionID, err)
+			}
+			subscriptions[delivery.SubscriptionID] = subscription
+		}
+		d.attempt(ctx, delivery, subscription)
+		if err := d.repo.UpdateWebhookDelivery(delivery); err != nil {
+			return i + 1, fmt.Errorf("update delivery %d: %w", delivery.ID, err)
+		}
+	}
+	return len(deliveries), nil
+}
+
+// attempt sends a delivery once and records the outcome on it.
+func (d *Dispatcher) attempt(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.WebhookSubscription) {
+	delivery.Attempts++
+	if subscription == nil || !subscription.Active {
+		delivery.Status = models.DeliveryDead
+		delivery.LastError = "subscription removed or inactive"
+		return
+	}
+
+	statusCode, err := d.send(ctx, delivery, subscription)
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		now := d.now()
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= d.opts.MaxAttempts {
+		delivery.Status = models.DeliveryDead
+		return
+	}
+	delivery.NextAttemptAt = d.now().Add(d.backoff(delivery.Attempts))
+}
+
+func (d *Dispatcher) send(ctx context.Context, delivery *models.WebhookDelivery, subscription *models.WebhookSubscription) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", delivery.EventType)
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, d.now(), body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver responded %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.opts.BaseBackoff << (attempts - 1)
+	if delay <= 0 || delay > d.opts.MaxBackoff {
+		return d.opts.MaxBackoff
+	}
+	return delay
+}
@@ -0,0 +1,280 @@
+package webhooks_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"stu/events"
+	"stu/models"
+	"stu/webhooks"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+// memoryRepository is an in-memory WebhookRepository for testing purposes.
+type memoryRepository struct {
+	mu            sync.Mutex
+	subscriptions []models.WebhookSubscription
+	deliveries    []models.WebhookDelivery
+	failQueue     bool
+	failLookup    bool
+	lease         time.Duration
+}
+
+func (m *memoryRepository) GetWebhookSubscriptions() ([]models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]models.WebhookSubscription(nil), m.subscriptions...), nil
+}
+
+func (m *memoryRepository) GetWebhookSubscriptionByID(id uint) (*models.WebhookSubscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failLookup {
+		return nil, errors.New("connection reset")
+	}
+	for _, subscription := range m.subscriptions {
+		if subscription.ID == id {
+			return &subscription, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (m *memoryRepository) CreateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	subscription.ID = uint(len(m.subscriptions) + 1)
+	m.subscriptions = append(m.subscriptions, *subscription)
+	return nil
+}
+
+func (m *memoryRepository) UpdateWebhookSubscription(subscription *models.WebhookSubscription) error {
+	return nil
+}
+
+func (m *memoryRepository) DeleteWebhookSubscription(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i, subscription := range m.subscriptions {
+		if subscription.ID == id {
+			m.subscriptions = append(m.subscriptions[:i], m.subscriptions[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+func (m *memoryRepository) GetWebhookDeliveries(status string) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deliveries []models.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if status == "" || delivery.Status == status {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *memoryRepository) GetWebhookDeliveryByID(id uint) (*models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delivery := m.deliveries[id-1]
+	return &delivery, nil
+}
+
+func (m *memoryRepository) CreateWebhookDeliveries(deliveries []models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.failQueue {
+		return errors.New("database unavailable")
+	}
+	for _, delivery := range deliveries {
+		if m.queued(delivery) {
+			continue
+		}
+		delivery.ID = uint(len(m.deliveries) + 1)
+		m.deliveries = append(m.deliveries, delivery)
+	}
+	return nil
+}
+
+func (m *memoryRepository) queued(delivery models.WebhookDelivery) bool {
+	if delivery.EventID == nil {
+		return false
+	}
+	for _, existing := range m.deliveries {
+		if existing.SubscriptionID == delivery.SubscriptionID && existing.EventID != nil && *existing.EventID == *delivery.EventID {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *memoryRepository) UpdateWebhookDelivery(delivery *models.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries[delivery.ID-1] = *delivery
+	return nil
+}
+
+func (m *memoryRepository) ClaimDueWebhookDeliveries(now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lease = lease
+	var due []models.WebhookDelivery
+	for i, delivery := range m.deliveries {
+		if delivery.Status == models.DeliveryPending && !delivery.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, delivery)
+			m.deliveries[i].NextAttemptAt = now.Add(lease)
+		}
+	}
+	return due, nil
+}
+
+func studentCreated(t *testing.T, schoolID uint) events.Event {
+	event, err := events.New(events.StudentCreated, "student", 7, map[string]string{"name": "Jahnavi"})
+	assert.Nil(t, err)
+	event.SchoolID = schoolID
+	return event
+}
+
+func TestDispatcher_DeliversSignedPayload(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{header: r.Header, body: body}
+	}))
+	defer receiver.Close()
+
+	repo := &memoryRepository{}
+	repo.CreateWebhookSubscription(&models.WebhookSubscription{
+		URL: receiver.URL, Secret: "s3cret", SchoolID: 1, Events: models.StringList{events.StudentCreated}, Active: true,
+	})
+	repo.CreateWebhookSubscription(&models.WebhookSubscription{
+		URL: receiver.URL, Secret: "other", SchoolID: 2, Active: true,
+	})
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{})
+
+	assert.Nil(t, dispatcher.Publish(studentCreated(t, 1)))
+	count, err := dispatcher.ProcessDue(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	request := <-got
+	assert.Equal(t, events.StudentCreated, request.header.Get("X-Webhook-Event"))
+	assert.Nil(t, webhooks.Verify("s3cret", request.header.Get(webhooks.SignatureHeader), request.body, time.Minute))
+	var payload events.Event
+	assert.Nil(t, json.Unmarshal(request.body, &payload))
+	assert.Equal(t, uint(7), payload.EntityID)
+
+	delivered, _ := repo.GetWebhookDeliveries(models.DeliveryDelivered)
+	assert.Len(t, delivered, 1)
+}
+
+func TestDispatcher_RetriesThenDeadLetters(t *testing.T) {
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer receiver.Close()
+
+	repo := &memoryRepository{}
+	repo.CreateWebhookSubscription(&models.WebhookSubscription{URL: receiver.URL, Secret: "s", Active: true})
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{MaxAttempts: 3, BaseBackoff: time.Nanosecond})
+
+	assert.Nil(t, dispatcher.Publish(studentCreated(t, 1)))
+	for i := 0; i < 3; i++ {
+		time.Sleep(time.Millisecond)
+		count, err := dispatcher.ProcessDue(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 1, count)
+	}
+
+	dead, _ := repo.GetWebhookDeliveries(models.DeliveryDead)
+	assert.Len(t, dead, 1)
+	assert.Equal(t, 3, dead[0].Attempts)
+	assert.Equal(t, http.StatusInternalServerError, dead[0].LastStatusCode)
+}
+
+func TestDispatcher_PublishFailsWhenQueueingFails(t *testing.T) {
+	repo := &memoryRepository{failQueue: true}
+	repo.CreateWebhookSubscription(&models.WebhookSubscription{URL: "http://example.invalid", Secret: "s", Active: true})
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{})
+
+	assert.NotNil(t, dispatcher.Publish(studentCreated(t, 1)))
+}
+
+func TestDispatcher_RepublishedEventIsQueuedOnce(t *testing.T) {
+	repo := &memoryRepository{}
+	repo.CreateWebhookSubscription(&models.WebhookSubscription{URL: "http://example.invalid", Secret: "s", Active: true})
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{})
+	event := studentCreated(t, 1)
+	event.ID = 42
+
+	assert.Nil(t, dispatcher.Publish(event))
+	assert.Nil(t, dispatcher.Publish(event))
+
+	pending, _ := repo.GetWebhookDeliveries(models.DeliveryPending)
+	assert.Len(t, pending, 1)
+}
+
+func TestVerify_RejectsTamperedBody(t *testing.T) {
+	header := webhooks.Sign("secret", time.Now(), []byte(`{"marks":90}`))
+
+	assert.Nil(t, webhooks.Verify("secret", header, []byte(`{"marks":90}`), time.Minute))
+	assert.Equal(t, webhooks.ErrInvalidSignature, webhooks.Verify("secret", header, []byte(`{"marks":99}`), time.Minute))
+	assert.Equal(t, webhooks.ErrInvalidSignature, webhooks.Verify("wrong", header, []byte(`{"marks":90}`), time.Minute))
+}
+
+func TestDispatcher_FailedSubscriptionLookupLeavesDeliveryPending(t *testing.T) {
+	repo := &memoryRepository{}
+	repo.CreateWebhookSubscription(&models.WebhookSubscription{URL: "http://example.invalid", Secret: "s", Active: true})
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{})
+	assert.Nil(t, dispatcher.Publish(studentCreated(t, 1)))
+	repo.failLookup = true
+
+	_, err := dispatcher.ProcessDue(context.Background())
+
+	assert.NotNil(t, err)
+	pending, _ := repo.GetWebhookDeliveries(models.DeliveryPending)
+	assert.Len(t, pending, 1)
+	assert.Equal(t, 0, pending[0].Attempts)
+}
+
+func TestDispatcher_RemovedSubscriptionDeadLetters(t *testing.T) {
+	repo := &memoryRepository{}
+	subscription := &models.WebhookSubscription{URL: "http://example.invalid", Secret: "s", Active: true}
+	repo.CreateWebhookSubscription(subscription)
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{})
+	assert.Nil(t, dispatcher.Publish(studentCreated(t, 1)))
+	assert.Nil(t, repo.DeleteWebhookSubscription(subscription.ID))
+
+	count, err := dispatcher.ProcessDue(context.Background())
+
+	assert.Nil(t, err)
+	assert.Equal(t, 1, count)
+	dead, _ := repo.GetWebhookDeliveries(models.DeliveryDead)
+	assert.Len(t, dead, 1)
+}
+
+func TestDispatcher_LeaseCoversTheBatch(t *testing.T) {
+	repo := &memoryRepository{}
+	dispatcher := webhooks.NewDispatcher(repo, webhooks.Options{BatchSize: 50, Timeout: 10 * time.Second})
+
+	_, err := dispatcher.ProcessDue(context.Background())
+
+	assert.Nil(t, err)
+	assert.GreaterOrEqual(t, repo.lease, 50*10*time.Second)
+}